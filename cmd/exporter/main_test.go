@@ -0,0 +1,106 @@
+// cmd/exporter/main_test.go – Tests del envoltorio histórico
+// --------------------------------------------------------------------------------
+// Verifican que cada flag del binario viejo llegue al subcomando equivalente
+// y que la traducción completa se ejecute con éxito en internal/cli.
+// --------------------------------------------------------------------------------
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/cli"
+)
+
+func TestTranslate(t *testing.T) {
+	cases := []struct {
+		name string
+		argv []string
+		want []string
+	}{
+		{
+			name: "export con todos los flags",
+			argv: []string{"-input", "in.json", "-output", "out", "-mode", "v2", "-pretty",
+				"-near-dedup", "-near-report", "near.json", "-near-method", "simhash", "-near-threshold", "0.9"},
+			want: []string{"export", "-input", "in.json", "-output", "out", "-mode", "v2", "-pretty",
+				"-near-dedup", "-near-report", "near.json", "-near-method", "simhash", "-near-threshold", "0.9"},
+		},
+		{
+			name: "export con valores por defecto",
+			argv: []string{"-input", "in.json", "-output", "out"},
+			want: []string{"export", "-input", "in.json", "-output", "out", "-mode", "v1",
+				"-near-method", "minhash", "-near-threshold", "0.8"},
+		},
+		{
+			name: "reverse",
+			argv: []string{"-reverse", "-input", "in.jsonl", "-output", "out.json"},
+			want: []string{"revert", "-input", "in.jsonl", "-output", "out.json"},
+		},
+		{
+			name: "reverse-single",
+			argv: []string{"-reverse-single", "-input", "in.json", "-output", "out.json", "-root-title", "Raíz"},
+			want: []string{"revert", "-input", "in.json", "-output", "out.json", "-root-title", "Raíz"},
+		},
+		{
+			name: "update-texts",
+			argv: []string{"-update-texts", "-input", "plantilla.json", "-updates", "u.jsonl", "-output", "out.json", "-pretty"},
+			want: []string{"revert", "-template", "plantilla.json", "-input", "u.jsonl", "-output", "out.json", "-pretty"},
+		},
+		{
+			name: "parquet",
+			argv: []string{"-mode", "export-parquet"},
+			want: []string{"parquet"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := translate(c.argv)
+			if err != nil {
+				t.Fatalf("translate: %v", err)
+			}
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("args = %q\nwant   %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestTranslate_UnknownFlag(t *testing.T) {
+	if _, err := translate([]string{"-no-existe"}); err == nil {
+		t.Error("un flag desconocido debería ser un error de uso")
+	}
+}
+
+func TestShim_RunsWithAllFlags(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "tiddlers.json")
+	sample := `[
+  {"title":"Foo","text":"el zorro marrón salta sobre el perro perezoso","tags":"[[a]]","created":"20250101120000","modified":"20250102120000"},
+  {"title":"Foo 2","text":"el zorro marrón salta sobre el perro perezoso","tags":"[[a]]","created":"20250101120000","modified":"20250102120000"},
+  {"title":"Bar","text":"nada que ver con lo anterior","tags":"[[b]]","created":"20250103120000","modified":"20250104120000"}
+]`
+	if err := os.WriteFile(in, []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outDir := filepath.Join(dir, "out")
+	report := filepath.Join(dir, "near.json")
+
+	for _, method := range []string{"minhash", "simhash"} {
+		args, err := translate([]string{"-input", in, "-output", outDir, "-mode", "v3", "-pretty",
+			"-near-dedup", "-near-report", report, "-near-method", method, "-near-threshold", "0.7"})
+		if err != nil {
+			t.Fatalf("translate: %v", err)
+		}
+		if code := cli.Run(args); code != cli.ExitOK {
+			t.Fatalf("%s: openpages %q devolvió %d", method, args, code)
+		}
+		for _, path := range []string{filepath.Join(outDir, "tiddlers_v3_pretty.jsonl"), report} {
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("%s: no se generó %s: %v", method, path, err)
+			}
+		}
+	}
+}
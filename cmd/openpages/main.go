@@ -0,0 +1,23 @@
+// cmd/openpages/main.go – Punto de entrada único de la CLI
+// --------------------------------------------------------------------------------
+// Delegamos todo en internal/cli: cada subcomando (export, revert, merge,
+// parquet, dedup, diff, validate, stats) define sus flags y su ayuda.
+//
+// Ejemplos de uso:
+//   go run ./cmd/openpages help
+//   go run ./cmd/openpages export -input ./data/in -output ./data/out -mode v3
+//   go run ./cmd/openpages revert -input out.jsonl -output restored.json
+// --------------------------------------------------------------------------------
+
+package main
+
+import (
+	"os"
+	_ "time/tzdata" // zonas IANA para -tz aun sin base de zonas del sistema
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:]))
+}
@@ -1,22 +1,26 @@
+// cmd/revert/main.go – Envoltorio histórico de `openpages revert -template`
+// --------------------------------------------------------------------------------
+// Uso: revert <plantilla.json> <textos.jsonl> <salida.json>
+// --------------------------------------------------------------------------------
+
 package main
 
 import (
-	"log"
+	"fmt"
 	"os"
 
-	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/cli"
 )
 
 func main() {
 	if len(os.Args) != 4 {
-		log.Fatalf("Uso: revert <plantilla.json> <textos.jsonl> <salida.json>")
-	}
-	textos := os.Args[2]
-	salida := os.Args[3]
-
-	err := exporter.ExportAllFromJSONL(textos, salida)
-	if err != nil {
-		log.Fatal(err)
+		fmt.Fprintln(os.Stderr, "Uso: revert <plantilla.json> <textos.jsonl> <salida.json>")
+		os.Exit(cli.ExitUsage)
 	}
-	log.Printf("✅ Proceso completado: %s actualizado con textos desde %s", salida, textos)
+	os.Exit(cli.Run([]string{
+		"revert",
+		"-template", os.Args[1],
+		"-input", os.Args[2],
+		"-output", os.Args[3],
+	}))
 }
@@ -3,8 +3,10 @@ module github.com/diegoabeltran16/OpenPages-Source
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/xitongsys/parquet-go v1.6.2
 	github.com/xitongsys/parquet-go-source v0.0.0-20200817004010-026bad9b25d0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
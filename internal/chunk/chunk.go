@@ -0,0 +1,236 @@
+// internal/chunk/chunk.go – Fragmentación por tokens para RAG
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un modelo de embeddings sólo mira los primeros N tokens de cada texto: un
+// tiddler largo exportado como un solo registro pierde todo lo que sigue.
+// Split lo corta en fragmentos de como mucho Options.MaxTokens tokens,
+// respetando la estructura en este orden:
+//
+//   1. encabezados (sections.Blocks: ! … en wikitext, # … en Markdown): un
+//      fragmento nunca mezcla dos secciones y lleva la ruta de encabezados
+//      que lo contiene (breadcrumb: ["Proyecto", "Requisitos"]);
+//   2. párrafos (líneas en blanco);
+//   3. oraciones (. ! ? … seguidos de espacio);
+//   4. palabras, sólo si una oración sola ya no cabe.
+//
+// Las piezas se juntan mientras quepan; con Options.Overlap cada fragmento
+// repite al principio las últimas piezas del anterior (hasta ese número de
+// tokens) para no cortar el contexto en seco.
+//
+// Los tokens se estiman sin descargar ningún tokenizador (Tokenizers):
+// "chars" cuenta ~4 caracteres por token y "words" ~0,75 palabras por token,
+// aproximaciones habituales para modelos BPE en textos latinos.
+// --------------------------------------------------------------------------------
+
+package chunk
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/sections"
+)
+
+// Tokenizadores aproximados.
+const (
+	TokenizerChars = "chars"
+	TokenizerWords = "words"
+)
+
+// Tokenizers son los tokenizadores admitidos.
+var Tokenizers = []string{TokenizerChars, TokenizerWords}
+
+// Options ajusta la fragmentación.
+type Options struct {
+	MaxTokens int    // tokens máximos por fragmento (> 0)
+	Overlap   int    // tokens repetidos del fragmento anterior (< MaxTokens)
+	Tokenizer string // TokenizerChars ("" equivale) | TokenizerWords
+}
+
+// Validate comprueba que las opciones sean coherentes.
+func (o Options) Validate() error {
+	if o.MaxTokens <= 0 {
+		return fmt.Errorf("los fragmentos necesitan un máximo de tokens positivo (%d)", o.MaxTokens)
+	}
+	if o.Overlap < 0 || o.Overlap >= o.MaxTokens {
+		return fmt.Errorf("el solapamiento (%d) debe estar entre 0 y el máximo de tokens (%d)", o.Overlap, o.MaxTokens)
+	}
+	switch o.Tokenizer {
+	case "", TokenizerChars, TokenizerWords:
+		return nil
+	}
+	return fmt.Errorf("tokenizador desconocido: %q (usa %s)", o.Tokenizer, strings.Join(Tokenizers, " o "))
+}
+
+// Count estima los tokens de text con el tokenizador indicado.
+func Count(text, tokenizer string) int {
+	if tokenizer == TokenizerWords {
+		words := len(strings.Fields(text))
+		return (words*4 + 2) / 3
+	}
+	return (utf8.RuneCountInString(strings.TrimSpace(text)) + 3) / 4
+}
+
+// Chunk es un fragmento de texto.
+type Chunk struct {
+	Text       string
+	Tokens     int
+	Breadcrumb []string
+}
+
+// piece es una unidad indivisible para el empaquetado; sep es lo que la
+// separa de la anterior ("\n\n" entre párrafos, " " dentro de un párrafo).
+type piece struct {
+	text string
+	sep  string
+}
+
+// Split corta text, de tipo MIME typ, en fragmentos de como mucho
+// opts.MaxTokens tokens (salvo una palabra que por sí sola los supere).  Los
+// textos vacíos no generan fragmentos.
+func Split(text, typ string, opts Options) []Chunk {
+	var out []Chunk
+	for _, b := range sections.Blocks(text, typ) {
+		pieces := split(b.Text, "", opts)
+		for _, c := range pack(pieces, opts) {
+			c.Breadcrumb = b.Path
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// split devuelve las piezas de text, cada una dentro del máximo: el texto
+// entero si cabe; si no, sus párrafos, oraciones o palabras.
+func split(text, sep string, opts Options) []piece {
+	if Count(text, opts.Tokenizer) <= opts.MaxTokens {
+		return []piece{{text: text, sep: sep}}
+	}
+	var parts []string
+	var inner string
+	switch {
+	case strings.Contains(text, "\n\n"):
+		parts, inner = paragraphs(text), "\n\n"
+	case len(sentences(text)) > 1:
+		parts, inner = sentences(text), " "
+	default:
+		return words(text, sep, opts)
+	}
+	var out []piece
+	for i, p := range parts {
+		s := inner
+		if i == 0 {
+			s = sep
+		}
+		out = append(out, split(p, s, opts)...)
+	}
+	return out
+}
+
+func paragraphs(text string) []string {
+	var out []string
+	for _, p := range strings.Split(text, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// sentences corta tras . ! ? o … seguidos de un espacio.
+func sentences(text string) []string {
+	var out []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes)-1; i++ {
+		if strings.ContainsRune(".!?…", runes[i]) && unicode.IsSpace(runes[i+1]) {
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				out = append(out, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// words agrupa las palabras de text en piezas que quepan en el máximo.
+func words(text, sep string, opts Options) []piece {
+	var out []piece
+	var cur []string
+	for _, w := range strings.Fields(text) {
+		if len(cur) > 0 && Count(strings.Join(append(cur, w), " "), opts.Tokenizer) > opts.MaxTokens {
+			out = append(out, piece{text: strings.Join(cur, " "), sep: sep})
+			cur, sep = nil, " "
+		}
+		cur = append(cur, w)
+	}
+	if len(cur) > 0 {
+		out = append(out, piece{text: strings.Join(cur, " "), sep: sep})
+	}
+	return out
+}
+
+// pack junta piezas consecutivas mientras quepan en el máximo y repite al
+// principio de cada fragmento las últimas piezas del anterior que sumen
+// como mucho opts.Overlap tokens.
+func pack(pieces []piece, opts Options) []Chunk {
+	join := func(ps []piece) string {
+		var b strings.Builder
+		for i, p := range ps {
+			if i > 0 {
+				b.WriteString(p.sep)
+			}
+			b.WriteString(p.text)
+		}
+		return b.String()
+	}
+	var out []Chunk
+	var cur []piece
+	fresh := 0 // piezas de cur que no vienen del solapamiento
+	emit := func() {
+		text := join(cur)
+		out = append(out, Chunk{Text: text, Tokens: Count(text, opts.Tokenizer)})
+	}
+	for _, p := range pieces {
+		if fresh > 0 && Count(join(append(cur, p)), opts.Tokenizer) > opts.MaxTokens {
+			emit()
+			cur = overlap(cur, opts)
+			for len(cur) > 0 && Count(join(append(cur, p)), opts.Tokenizer) > opts.MaxTokens {
+				cur = cur[1:]
+			}
+			fresh = 0
+		}
+		cur = append(cur, p)
+		fresh++
+	}
+	if fresh > 0 {
+		emit()
+	}
+	return out
+}
+
+// overlap devuelve las últimas piezas de ps que suman como mucho
+// opts.Overlap tokens.
+func overlap(ps []piece, opts Options) []piece {
+	if opts.Overlap == 0 {
+		return nil
+	}
+	start := len(ps)
+	for start > 0 {
+		var texts []string
+		for _, p := range ps[start-1:] {
+			texts = append(texts, p.text)
+		}
+		if Count(strings.Join(texts, " "), opts.Tokenizer) > opts.Overlap {
+			break
+		}
+		start--
+	}
+	return append([]piece(nil), ps[start:]...)
+}
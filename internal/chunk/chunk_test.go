@@ -0,0 +1,113 @@
+// internal/chunk/chunk_test.go – Tests de Split, Count y Fragments
+// --------------------------------------------------------------------------------
+
+package chunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+const doc = `Introducción breve.
+
+! Requisitos
+!! Funcionales
+El sistema exporta tiddlers. El sistema revierte registros. El sistema compara wikis.
+
+Otro párrafo corto.
+!! No funcionales
+Rápido.
+! Apéndice
+` + "```" + `
+! esto es código
+` + "```"
+
+func TestSplit_Headings(t *testing.T) {
+	chunks := Split(doc, "", Options{MaxTokens: 1000})
+	var crumbs [][]string
+	for _, c := range chunks {
+		crumbs = append(crumbs, c.Breadcrumb)
+	}
+	want := [][]string{nil, {"Requisitos"}, {"Requisitos", "Funcionales"}, {"Requisitos", "No funcionales"}, {"Apéndice"}}
+	if !reflect.DeepEqual(crumbs, want) {
+		t.Errorf("breadcrumbs = %q, want %q", crumbs, want)
+	}
+	if !strings.Contains(chunks[4].Text, "! esto es código") {
+		t.Errorf("el código no debe abrir otra sección: %q", chunks[4].Text)
+	}
+
+	// En Markdown los encabezados son "#"; "!" no abre sección.
+	md := Split("# Guía\nUno.\n! no\n## Pasos\nDos.", "text/x-markdown", Options{MaxTokens: 1000})
+	crumbs = nil
+	for _, c := range md {
+		crumbs = append(crumbs, c.Breadcrumb)
+	}
+	if want := [][]string{{"Guía"}, {"Guía", "Pasos"}}; !reflect.DeepEqual(crumbs, want) {
+		t.Errorf("breadcrumbs Markdown = %q, want %q", crumbs, want)
+	}
+}
+
+func TestSplit_MaxAndOverlap(t *testing.T) {
+	text := "Uno dos tres. Cuatro cinco seis. Siete ocho nueve. Diez once doce.\n\nTrece catorce quince dieciséis diecisiete dieciocho diecinueve veinte."
+	opts := Options{MaxTokens: 8, Overlap: 4, Tokenizer: TokenizerWords}
+	chunks := Split(text, "", opts)
+	if len(chunks) < 3 {
+		t.Fatalf("fragmentos = %d: %+v", len(chunks), chunks)
+	}
+	for i, c := range chunks {
+		if c.Tokens > opts.MaxTokens {
+			t.Errorf("fragmento %d con %d tokens: %q", i, c.Tokens, c.Text)
+		}
+	}
+	if !strings.HasPrefix(chunks[1].Text, "Cuatro cinco seis.") {
+		t.Errorf("sin solapamiento: %q tras %q", chunks[1].Text, chunks[0].Text)
+	}
+	if last := chunks[len(chunks)-1].Text; !strings.HasSuffix(last, "veinte.") {
+		t.Errorf("se perdió el final: %q", last)
+	}
+
+	if got := Split("   ", "", opts); len(got) != 0 {
+		t.Errorf("texto vacío = %+v", got)
+	}
+}
+
+func TestCount(t *testing.T) {
+	if got := Count("abcdefgh", TokenizerChars); got != 2 {
+		t.Errorf("chars = %d, want 2", got)
+	}
+	if got := Count("uno dos tres", TokenizerWords); got != 4 {
+		t.Errorf("words = %d, want 4", got)
+	}
+	for _, bad := range []Options{{}, {MaxTokens: 10, Overlap: 10}, {MaxTokens: 10, Tokenizer: "bpe"}} {
+		if bad.Validate() == nil {
+			t.Errorf("%+v sin error", bad)
+		}
+	}
+}
+
+func TestFragments(t *testing.T) {
+	tiddler := models.Tiddler{Title: "Manual", Text: doc, Tags: "guía"}
+	opts := Options{MaxTokens: 1000}
+	recs := Fragments(tiddler, opts, transform.Options{})
+	if len(recs) != 5 {
+		t.Fatalf("fragmentos = %d", len(recs))
+	}
+	r := recs[2]
+	if r.Type != FragmentType || r.Parent != "Manual" || r.Meta.Title != "Manual" || r.Chunk.Index != 2 || r.Chunk.Count != 5 ||
+		!strings.HasPrefix(r.ID, "Manual#") || !strings.Contains(r.Content.Plain, "compara wikis") {
+		t.Errorf("fragmento = %+v", r)
+	}
+
+	// Editar otra sección no cambia el ID de este fragmento.
+	tiddler.Text = strings.Replace(doc, "Rápido.", "Muy rápido.", 1)
+	if again := Fragments(tiddler, opts, transform.Options{}); again[2].ID != r.ID || again[3].ID == recs[3].ID {
+		t.Errorf("IDs inestables: %s/%s, %s/%s", again[2].ID, r.ID, again[3].ID, recs[3].ID)
+	}
+	if got := Fragments(models.Tiddler{Title: "Vacío"}, opts, transform.Options{}); got != nil {
+		t.Errorf("tiddler vacío = %+v", got)
+	}
+}
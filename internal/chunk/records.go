@@ -0,0 +1,67 @@
+// internal/chunk/records.go – Fragmentos como registros v2 (type "fragment")
+// --------------------------------------------------------------------------------
+// Cada fragmento es un models.RecordV2 con Type "fragment": la meta del
+// tiddler completo (título, etiquetas, fechas, kind…), el texto del
+// fragmento en content.plain, `parent` con el ID del registro del tiddler y
+// `chunk` con su posición, sus tokens y el breadcrumb de encabezados.
+//
+// El ID es estable: "<título>#<hash>", con los 12 primeros hex del SHA-256
+// del breadcrumb y el texto.  Un fragmento que no cambia conserva su ID entre
+// exports aunque se edite otra parte del tiddler, así que sus embeddings se
+// pueden reutilizar.
+// --------------------------------------------------------------------------------
+
+package chunk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// FragmentType es el Type de los registros de fragmento.
+const FragmentType = transform.FragmentType
+
+// Fragments convierte t en sus registros de fragmento (ninguno si no tiene
+// texto).  conv son las opciones de conversión v2 de la meta.
+func Fragments(t models.Tiddler, opts Options, conv transform.Options) []models.RecordV2 {
+	chunks := Split(transform.GetTextContent(t.Text), t.Type, opts)
+	if len(chunks) == 0 {
+		return nil
+	}
+	base := transform.ConvertTiddlerV2With(t, conv)
+	out := make([]models.RecordV2, len(chunks))
+	seen := make(map[string]bool, len(chunks))
+	for i, c := range chunks {
+		id := ID(base.ID, c)
+		if seen[id] {
+			id = fmt.Sprintf("%s-%d", id, i) // texto repetido en la misma sección
+		}
+		seen[id] = true
+		out[i] = models.RecordV2{
+			SchemaVersion: models.SchemaV2,
+			ID:            id,
+			Type:          FragmentType,
+			Meta:          base.Meta,
+			Content:       models.Content{Plain: c.Text},
+			Parent:        base.ID,
+			Chunk: &models.ChunkInfo{
+				Index:      i,
+				Count:      len(chunks),
+				Tokens:     c.Tokens,
+				Breadcrumb: c.Breadcrumb,
+			},
+		}
+	}
+	return out
+}
+
+// ID devuelve el ID estable del fragmento c del registro parent.
+func ID(parent string, c Chunk) string {
+	sum := sha256.Sum256([]byte(strings.Join(c.Breadcrumb, "\x1f") + "\x00" + c.Text))
+	return parent + "#" + hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,115 @@
+// internal/classify/classify.go – Clases de tiddler: contenido, sistema, borrador…
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un export completo de TiddlyWiki trae, además del contenido escrito por el
+// equipo, tiddlers que sólo tienen sentido dentro de la wiki:
+//
+//   draft   → "Draft of 'X'" (o con campo draft.of): ediciones sin guardar
+//   plugin  → paquetes con plugin-type (ver internal/plugin)
+//   state   → $:/state/…, $:/temp/…, $:/StoryList, $:/HistoryList, $:/Import:
+//             qué está abierto, desplegado o pendiente de importar
+//   system  → el resto de títulos $:/… (configuración, paleta, macros)
+//   content → todo lo demás
+//
+// Para RAG son ruido; para una copia de seguridad, imprescindibles.  Of
+// decide la clase (en ese orden de prioridad: un borrador de un tiddler de
+// sistema es draft) y cada modo la escribe en el campo `kind`, junto a
+// `is_system` (título $:/…, como is[system] en TiddlyWiki).  El export usa
+// la clase para incluir, excluir o separar lo que no es contenido.
+// --------------------------------------------------------------------------------
+
+package classify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Clases de tiddler.
+const (
+	Content = "content"
+	System  = "system"
+	Draft   = "draft"
+	State   = "state"
+	Plugin  = "plugin"
+)
+
+// Kinds son todas las clases, en el orden en que se informan.
+var Kinds = []string{Content, System, Draft, State, Plugin}
+
+// Políticas para los tiddlers que no son contenido.
+const (
+	PolicyInclude = "include" // exportarlos junto al contenido (copia completa)
+	PolicyExclude = "exclude" // descartarlos
+	PolicySplit   = "split"   // escribirlos en un archivo aparte
+)
+
+// Policies son las políticas admitidas.
+var Policies = []string{PolicyInclude, PolicyExclude, PolicySplit}
+
+// stateTitles son tiddlers de estado fuera de $:/state/.
+var stateTitles = map[string]bool{"$:/StoryList": true, "$:/HistoryList": true, "$:/Import": true}
+
+// IsSystem indica si title es de sistema ($:/…).
+func IsSystem(title string) bool { return strings.HasPrefix(title, "$:/") }
+
+// Of devuelve la clase de t.
+func Of(t models.Tiddler) string {
+	switch {
+	case strings.HasPrefix(t.Title, "Draft of '") || t.ExtraFields["draft.of"] != nil:
+		return Draft
+	case plugin.IsPlugin(t):
+		return Plugin
+	case stateTitles[t.Title] || strings.HasPrefix(t.Title, "$:/state/") || strings.HasPrefix(t.Title, "$:/temp/"):
+		return State
+	case IsSystem(t.Title):
+		return System
+	}
+	return Content
+}
+
+// ParsePolicy valida una política ("" = include).
+func ParsePolicy(s string) (string, error) {
+	switch s {
+	case "", PolicyInclude:
+		return PolicyInclude, nil
+	case PolicyExclude, PolicySplit:
+		return s, nil
+	}
+	return "", fmt.Errorf("política desconocida: %q (usa %s)", s, strings.Join(Policies, ", "))
+}
+
+// Split separa ts en contenido y el resto, conservando el orden.  counts
+// cuenta los tiddlers de cada clase.
+func Split(ts []models.Tiddler) (content, other []models.Tiddler, counts map[string]int) {
+	counts = make(map[string]int, len(Kinds))
+	for _, t := range ts {
+		kind := Of(t)
+		counts[kind]++
+		if kind == Content {
+			content = append(content, t)
+		} else {
+			other = append(other, t)
+		}
+	}
+	return content, other, counts
+}
+
+// Summary describe counts en una línea ("3 content, 1 system, 2 draft").
+// Las clases sin tiddlers se omiten.
+func Summary(counts map[string]int) string {
+	var parts []string
+	for _, kind := range Kinds {
+		if n := counts[kind]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	if len(parts) == 0 {
+		return "ningún tiddler"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,56 @@
+// internal/classify/classify_test.go – Tests de Of, Split y ParsePolicy
+// --------------------------------------------------------------------------------
+
+package classify
+
+import (
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestOf(t *testing.T) {
+	cases := []struct {
+		t    models.Tiddler
+		want string
+	}{
+		{models.Tiddler{Title: "Glosario"}, Content},
+		{models.Tiddler{Title: "$:/config/Tema"}, System},
+		{models.Tiddler{Title: "$:/state/popup/menu"}, State},
+		{models.Tiddler{Title: "$:/StoryList"}, State},
+		{models.Tiddler{Title: "$:/temp/search"}, State},
+		{models.Tiddler{Title: "Draft of 'Glosario'"}, Draft},
+		{models.Tiddler{Title: "Draft 2 of '$:/config/Tema'", ExtraFields: map[string]any{"draft.of": "$:/config/Tema"}}, Draft},
+		{models.Tiddler{Title: "$:/plugins/equipo/glosario", Type: "application/json",
+			ExtraFields: map[string]any{"plugin-type": "plugin"}}, Plugin},
+		{models.Tiddler{Title: "$:/plugins/equipo/notas", Type: "text/vnd.tiddlywiki"}, System},
+	}
+	for _, c := range cases {
+		if got := Of(c.t); got != c.want {
+			t.Errorf("Of(%q) = %s, want %s", c.t.Title, got, c.want)
+		}
+	}
+	if !IsSystem("$:/StoryList") || IsSystem("Draft of '$:/x'") {
+		t.Error("IsSystem sólo mira el prefijo $:/")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	ts := []models.Tiddler{{Title: "A"}, {Title: "$:/config/x"}, {Title: "B"}, {Title: "Draft of 'A'"}}
+	content, other, counts := Split(ts)
+	if len(content) != 2 || content[1].Title != "B" || len(other) != 2 || other[1].Title != "Draft of 'A'" {
+		t.Errorf("Split = %v / %v", content, other)
+	}
+	if got := Summary(counts); got != "2 content, 1 system, 1 draft" {
+		t.Errorf("Summary = %q", got)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if p, err := ParsePolicy(""); err != nil || p != PolicyInclude {
+		t.Errorf(`ParsePolicy("") = %q, %v`, p, err)
+	}
+	if _, err := ParsePolicy("ocultar"); err == nil {
+		t.Error("política desconocida sin error")
+	}
+}
@@ -0,0 +1,144 @@
+// internal/cli/apply.go – Subcomando `apply`
+// --------------------------------------------------------------------------------
+// Aplica un parche generado con `diff -format patch` a un export JSON de
+// TiddlyWiki o, en su sitio, a una wiki en carpeta (.tid).  Las operaciones
+// sobre tiddlers que cambiaron desde que se generó el parche se rechazan y
+// entonces no se aplica nada (ver internal/patch).
+//
+//   openpages diff -format patch -output cambios.patch.jsonl wiki.json revisado.jsonl
+//   openpages apply -patch cambios.patch.jsonl -input wiki.json -output wiki_nueva.json
+//   openpages apply -patch cambios.patch.jsonl -folder mi-wiki/tiddlers
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/patch"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/wikifolder"
+)
+
+func runApply(args []string) error {
+	fs := newFlagSet("apply", "-patch cambios.patch.jsonl (-input wiki.json [-output salida.json] | -folder carpeta) [flags]", `
+Aplica un parche JSONL (ver "diff -format patch") a un export JSON de
+TiddlyWiki o a una wiki en carpeta de archivos .tid.  Cada operación lleva el
+hash de la versión de partida: si el tiddler cambió desde entonces el parche
+se rechaza entero, no se escribe nada y el comando termina con código 3.`)
+	patchPath := fs.String("patch", "", "Parche JSONL (\"-\" = stdin)")
+	in := fs.String("input", "", "Export JSON de TiddlyWiki sobre el que aplicar (\"-\" = stdin)")
+	out := fs.String("output", "", "JSON resultante (\"-\" = stdout, por defecto)")
+	folder := fs.String("folder", "", "Wiki en carpeta (.tid) que se modifica en su sitio")
+	check := fs.Bool("check", false, "Sólo comprobar que el parche aplica, sin escribir nada")
+	report := fs.String("report", "", "Informe JSON de cada operación")
+	pretty := fs.Bool("pretty", false, "JSON indentado")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *patchPath == "" {
+		return usagef("falta -patch")
+	}
+	if (*in == "") == (*folder == "") {
+		return usagef("indica -input o -folder (uno de los dos)")
+	}
+	if *folder != "" && *out != "" {
+		return usagef("-output no aplica con -folder: la carpeta se modifica en su sitio")
+	}
+	if *patchPath == importer.Stdio && *in == importer.Stdio {
+		return usagef("sólo una de las entradas puede ser stdin")
+	}
+	if *out == "" {
+		*out = exporter.Stdio
+	}
+
+	ops, err := readPatch(*patchPath)
+	if err != nil {
+		return err
+	}
+
+	var rep patch.Report
+	if *folder != "" {
+		rep, err = applyFolder(*folder, ops, *check)
+	} else {
+		rep, err = applyExport(*in, *out, ops, *check, *pretty)
+	}
+	if *report != "" {
+		if werr := exporter.WriteJSON(*report, rep, true); werr != nil {
+			return fmt.Errorf("escribiendo informe: %w", werr)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe del parche: %s\n", *report)
+	}
+	var rejected *patch.RejectedError
+	if errors.As(err, &rejected) {
+		for _, r := range rejected.Rejected {
+			fmt.Fprintf(os.Stderr, "  ⛔ #%d %s %q: %s\n", r.Index, r.Op, r.Title, r.Reason)
+		}
+		return findingsError{msg: fmt.Sprintf("%v; no se escribió nada", err)}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *check:
+		fmt.Fprintf(os.Stderr, "✅ El parche aplica limpio: %d operaciones\n", rep.Ops)
+	case *folder != "":
+		fmt.Fprintf(os.Stderr, "✅ Parche aplicado: %d operaciones (carpeta: %s)\n", rep.Applied, *folder)
+	default:
+		fmt.Fprintf(os.Stderr, "✅ Parche aplicado: %d operaciones (destino: %s)\n", rep.Applied, *out)
+	}
+	return nil
+}
+
+// applyExport aplica el parche a un export JSON y escribe el resultado.
+func applyExport(in, out string, ops []patch.Op, check, pretty bool) (patch.Report, error) {
+	ts, err := importer.Read(context.Background(), in)
+	if err != nil {
+		return patch.Report{}, fmt.Errorf("leyendo %s: %w", in, err)
+	}
+	res, rep, err := patch.Apply(ts, ops)
+	if err != nil || check {
+		return rep, err
+	}
+	if err := exporter.WriteJSON(out, res, pretty); err != nil {
+		return rep, fmt.Errorf("escribiendo resultado: %w", err)
+	}
+	return rep, nil
+}
+
+// applyFolder aplica el parche a una wiki en carpeta (o sólo lo comprueba).
+func applyFolder(dir string, ops []patch.Op, check bool) (patch.Report, error) {
+	if !check {
+		return patch.ApplyFolder(dir, ops)
+	}
+	folder, err := wikifolder.Read(dir)
+	if err != nil {
+		return patch.Report{}, err
+	}
+	_, rep, err := patch.Apply(folder.Tiddlers, ops)
+	return rep, err
+}
+
+// readPatch lee un parche JSONL de un archivo o de stdin.
+func readPatch(path string) ([]patch.Op, error) {
+	var r io.Reader = os.Stdin
+	if path != importer.Stdio {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo abrir '%s': %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	ops, err := patch.Read(r)
+	if err != nil {
+		return nil, fmt.Errorf("parche %s: %w", path, err)
+	}
+	return ops, nil
+}
@@ -0,0 +1,208 @@
+// internal/cli/batch.go – Exportación por lotes de una carpeta completa
+// --------------------------------------------------------------------------------
+// Cuando `export -input` apunta a una carpeta:
+//
+//   1. collectInputs reúne todos los archivos que coinciden con -glob
+//      (recorriendo subcarpetas con -recursive), ordenados por ruta.
+//   2. Un pool de -workers goroutines lee y convierte cada archivo.
+//   3. Sin -merge → una salida por entrada dentro de -output, replicando las
+//      subcarpetas relativas para evitar colisiones de nombres.
+//      Con -merge  → todos los tiddlers se combinan (en el orden de los
+//      archivos) y se escribe un único JSONL.
+//   4. Al final se imprime un resumen por archivo.  Un archivo fallido no
+//      detiene a los demás; el comando termina con error si hubo alguno.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// batchOptions describe cómo recorrer la carpeta de entrada.
+type batchOptions struct {
+	dir       string
+	out       string
+	glob      string
+	recursive bool
+	merge     bool
+	workers   int
+}
+
+// batchResult es el resultado de procesar un archivo del lote.
+type batchResult struct {
+	input    string
+	output   string
+	records  int
+	tiddlers []models.Tiddler // sólo con -merge
+	err      error
+}
+
+func runBatch(ctx context.Context, b batchOptions, opts exportOptions) error {
+	inputs, err := collectInputs(b.dir, b.glob, b.recursive)
+	if err != nil {
+		return err
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no se encontraron archivos '%s' en '%s'", b.glob, b.dir)
+	}
+	fmt.Fprintf(os.Stderr, "📂 %d archivos a procesar en '%s'\n", len(inputs), b.dir)
+
+	var mergedOut string
+	switch {
+	case b.merge && filepath.Ext(b.out) != "":
+		mergedOut = b.out // archivo explícito: se respeta su nombre
+	case b.merge:
+		mergedOut, err = resolveOutput(filepath.Join(b.dir, "merged.json"), b.out, opts)
+	default:
+		err = os.MkdirAll(b.out, 0o755)
+	}
+	if err != nil {
+		return err
+	}
+
+	// El paralelismo está en los archivos: cada uno se convierte con un worker.
+	fileOpts := opts
+	fileOpts.workers = 1
+	results := make([]batchResult, len(inputs))
+	workers := b.workers
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = processBatchFile(ctx, b, inputs[i], fileOpts)
+			}
+		}()
+	}
+	for i := range inputs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if b.merge {
+		var all []models.Tiddler
+		for _, r := range results {
+			all = append(all, r.tiddlers...)
+		}
+		n, err := exportTiddlers(ctx, all, mergedOut, opts)
+		printBatchSummary(results)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "✅ Exportación combinada completada: %d registros (destino: %s)\n", n, mergedOut)
+	} else {
+		printBatchSummary(results)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d de %d archivos fallaron", failed, len(results))
+	}
+	return nil
+}
+
+// processBatchFile lee un archivo y, salvo con -merge, lo exporta a su propia salida.
+func processBatchFile(ctx context.Context, b batchOptions, input string, opts exportOptions) batchResult {
+	res := batchResult{input: input}
+	if b.merge {
+		res.tiddlers, res.err = importer.Read(ctx, input)
+		res.records = len(res.tiddlers)
+		return res
+	}
+
+	rel, err := filepath.Rel(b.dir, input)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	outDir := filepath.Join(b.out, filepath.Dir(rel))
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		res.err = err
+		return res
+	}
+	res.output, err = resolveOutput(input, outDir, opts)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	if opts.nearReport != "" {
+		// Un informe por archivo: <informe>_<entrada>.json
+		ext := filepath.Ext(opts.nearReport)
+		name := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+		opts.nearReport = strings.TrimSuffix(opts.nearReport, ext) + "_" + name + ext
+	}
+	res.records, res.err = exportFile(ctx, input, res.output, opts)
+	return res
+}
+
+// collectInputs devuelve, ordenados, los archivos de dir cuyo nombre coincide
+// con pattern.  Con recursive también recorre las subcarpetas.
+func collectInputs(dir, pattern string, recursive bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ok, err := filepath.Match(pattern, d.Name())
+		if err != nil {
+			return err
+		}
+		if ok {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo listar '%s': %w", dir, err)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func printBatchSummary(results []batchResult) {
+	fmt.Fprintln(os.Stderr, "--------------------------------------------------")
+	fmt.Fprintln(os.Stderr, "📋 Resumen por archivo:")
+	ok := 0
+	for _, r := range results {
+		if r.err != nil {
+			fmt.Fprintf(os.Stderr, "  ❌ %s: %v\n", r.input, r.err)
+			continue
+		}
+		ok++
+		if r.output != "" {
+			fmt.Fprintf(os.Stderr, "  ✅ %s → %s (%d registros)\n", r.input, r.output, r.records)
+		} else {
+			fmt.Fprintf(os.Stderr, "  ✅ %s (%d tiddlers)\n", r.input, r.records)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "📊 %d correctos, %d con error\n", ok, len(results)-ok)
+	fmt.Fprintln(os.Stderr, "--------------------------------------------------")
+}
@@ -0,0 +1,210 @@
+// internal/cli/cli.go – Despachador de subcomandos del binario `openpages`
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Antes, `cmd/exporter` mezclaba exportación, reversión, actualización de textos
+// y Parquet en un solo conjunto de flags seleccionado con `-mode`.  Este paquete
+// organiza la CLI en subcomandos, cada uno con sus propios flags y su ayuda:
+//
+//   openpages <comando> [flags] [argumentos]
+//
+// Cada subcomando vive en su propio archivo (export.go, revert.go, …) y se
+// registra en la tabla `commands`.  Los binarios históricos (`cmd/exporter`,
+// `cmd/revert`) quedan como envoltorios delgados que traducen sus argumentos
+// y llaman a Run.
+//
+// Códigos de salida (consistentes en todos los subcomandos):
+//   0 → éxito
+//   1 → error de ejecución (E/S, parseo, conversión)
+//   2 → uso incorrecto (flags inválidos, argumentos faltantes)
+//   3 → la verificación encontró problemas (p.ej. `validate`)
+//
+// Entrada/salida estándar: en los comandos que leen y escriben datos, "-"
+// (o la omisión de -input/-output) significa stdin/stdout, para poder usarlos
+// en pipelines:  curl … | openpages export -mode v3 | jq …
+// Por eso todos los mensajes de progreso y diagnóstico van a stderr.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+)
+
+// Códigos de salida de Run.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitFindings = 3
+)
+
+// command describe un subcomando registrado.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands es la tabla de subcomandos en el orden en que aparecen en la ayuda.
+var commands = []command{
+	{"run", "Ejecuta el pipeline declarado en openpages.yaml / openpages.toml", runRun},
+	{"export", "Convierte un export de TiddlyWiki a JSONL (v1 | v2 | v3 | hybrid)", runExport},
+	{"revert", "Revierte JSONL a JSON TiddlyWiki (completo, sobre plantilla o tiddler raíz)", runRevert},
+	{"plugin", "Empaqueta tiddlers como plugin de TiddlyWiki o desempaqueta plugins", runPlugin},
+	{"subtree", "Exporta un proyecto: la raíz y todo lo que cuelga de ella (JSON o plugin)", runSubtree},
+	{"roundtrip", "Verifica qué pierde cada modo en la ida y vuelta export → revert", runRoundtrip},
+	{"normalize", "Convierte cualquier entrada (TiddlyWiki o JSONL de cualquier modo) a JSONL v3", runNormalize},
+	{"migrate", "Lleva un JSONL a otra versión de esquema (v1 | v2 | v3 | hybrid)", runMigrate},
+	{"merge", "Combina varios exports de TiddlyWiki en uno solo", runMerge},
+	{"merge3", "Fusión de tres vías: export original, JSONL editado y wiki actual", runMerge3},
+	{"parquet", "Convierte un archivo JSONL a Parquet", runParquet},
+	{"dedup", "Elimina duplicados exactos y casi-duplicados", runDedup},
+	{"diff", "Compara dos exports: tiddlers añadidos, eliminados, renombrados y modificados campo a campo, o un parche para `apply`", runDiff},
+	{"apply", "Aplica un parche de `diff -format patch` a un export o a una wiki en carpeta", runApply},
+	{"validate", "Verifica la estructura de un export JSON o JSONL", runValidate},
+	{"stats", "Muestra estadísticas de un export", runStats},
+}
+
+// usageError marca errores de uso: Run los traduce a ExitUsage.
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+func usagef(format string, a ...any) error {
+	return usageError{msg: fmt.Sprintf(format, a...)}
+}
+
+// findingsError indica que el comando terminó bien pero encontró problemas
+// en los datos; Run lo traduce a ExitFindings.
+type findingsError struct{ msg string }
+
+func (e findingsError) Error() string { return e.msg }
+
+// Run ejecuta el subcomando indicado en args[0] y devuelve el código de salida.
+func Run(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			if cmd, ok := lookup(args[1]); ok {
+				return Run([]string{cmd.name, "-h"})
+			}
+		}
+		printUsage(os.Stdout)
+		return ExitOK
+	}
+
+	cmd, ok := lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "❌ comando desconocido: %s\n\n", name)
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	err := cmd.run(args[1:])
+	var uerr usageError
+	var ferr findingsError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK
+	case errors.As(err, &uerr):
+		// Un mensaje vacío indica que flag ya imprimió el error y la ayuda.
+		if uerr.msg != "" {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			fmt.Fprintf(os.Stderr, "Ejecute 'openpages %s -h' para ver la ayuda.\n", cmd.name)
+		}
+		return ExitUsage
+	case errors.As(err, &ferr):
+		fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		return ExitFindings
+	default:
+		fmt.Fprintf(os.Stderr, "❌ %s: %v\n", cmd.name, err)
+		return ExitError
+	}
+}
+
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Uso: openpages <comando> [flags] [argumentos]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Comandos:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Ejecute 'openpages help <comando>' para ver los flags de cada comando.")
+}
+
+// newFlagSet crea un FlagSet que no termina el proceso y cuya ayuda incluye
+// la sinopsis y la descripción del subcomando.
+func newFlagSet(name, synopsis, description string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "Uso: openpages %s %s\n\n", name, synopsis)
+		fmt.Fprintln(out, strings.TrimSpace(description))
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Flags:")
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// defaultStdio completa -input y -output vacíos con "-" (stdin/stdout).  La
+// entrada sólo se toma de stdin si llega por un pipe o una redirección: en una
+// terminal interactiva falta -input y es un error de uso.
+func defaultStdio(in, out *string) error {
+	if *in == "" {
+		if !stdinIsPipe() {
+			return usagef("falta -input (o envíe los datos por stdin)")
+		}
+		*in = importer.Stdio
+	}
+	if *out == "" {
+		*out = exporter.Stdio
+	}
+	return nil
+}
+
+// stdinIsPipe indica si stdin no es una terminal ni un dispositivo (/dev/null).
+func stdinIsPipe() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice == 0
+}
+
+// parseFlags parsea args y convierte los errores de flag en usageError.
+// El error de -h se devuelve tal cual para que Run salga con ExitOK.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{} // flag ya informó el error en fs.Output()
+	}
+	return nil
+}
@@ -0,0 +1,841 @@
+// internal/cli/cli_test.go – Tests de despacho y códigos de salida de la CLI
+// --------------------------------------------------------------------------------
+// Cada prueba invoca Run como lo haría `openpages` y verifica el código de
+// salida y los archivos producidos en un directorio temporal.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleExport = `[
+  {"title":"Foo","text":"hola","type":"text/plain","tags":"[[a]]","created":"20250101120000","modified":"20250102120000"},
+  {"title":"Bar","text":"mundo","type":"text/plain","tags":"[[b]]","created":"20250103120000","modified":"20250104120000"}
+]`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("escribiendo %s: %v", path, err)
+	}
+	return path
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"sin argumentos", nil, ExitUsage},
+		{"comando desconocido", []string{"nada"}, ExitUsage},
+		{"ayuda general", []string{"help"}, ExitOK},
+		{"ayuda de comando", []string{"export", "-h"}, ExitOK},
+		{"flag inválido", []string{"export", "-nope"}, ExitUsage},
+		{"faltan flags", []string{"export"}, ExitUsage},
+		{"archivo inexistente", []string{"stats", "/no/existe.json"}, ExitError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Run(c.args); got != c.want {
+				t.Errorf("Run(%v) = %d, want %d", c.args, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRun_Export(t *testing.T) {
+	dir := t.TempDir()
+	in := writeFile(t, dir, "tiddlers.json", sampleExport)
+	outDir := filepath.Join(dir, "out")
+
+	if code := Run([]string{"export", "-input", in, "-output", outDir, "-mode", "v3"}); code != ExitOK {
+		t.Fatalf("export devolvió %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "tiddlers_v3.jsonl"))
+	if err != nil {
+		t.Fatalf("no se generó la salida: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("líneas = %d, want 2", lines)
+	}
+
+	if code := Run([]string{"export", "-input", in, "-output", outDir, "-mode", "v1", "-format", "csv"}); code != ExitOK {
+		t.Fatalf("export -format csv devolvió %d", code)
+	}
+	data, err = os.ReadFile(filepath.Join(outDir, "tiddlers_v1.csv"))
+	if err != nil {
+		t.Fatalf("no se generó el CSV: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "schema_version,id,tags,") || strings.Count(string(data), "\n") != 3 {
+		t.Errorf("CSV inesperado:\n%s", data)
+	}
+	if code := Run([]string{"export", "-input", in, "-output", "-", "-format", "parquet"}); code != ExitUsage {
+		t.Errorf("parquet a stdout devolvió %d, want %d", code, ExitUsage)
+	}
+
+	filtered := filepath.Join(dir, "filtrado")
+	if code := Run([]string{"export", "-input", in, "-output", filtered, "-mode", "v3", "-filter", "[!tag[b]] -[[Nada]]"}); code != ExitOK {
+		t.Fatalf("export -filter devolvió %d", code)
+	}
+	data, err = os.ReadFile(filepath.Join(filtered, "tiddlers_v3.jsonl"))
+	if err != nil {
+		t.Fatalf("no se generó la salida filtrada: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("líneas filtradas = %d, want 1:\n%s", lines, data)
+	}
+	if code := Run([]string{"export", "-input", in, "-filter", "[tag[b]"}); code != ExitUsage {
+		t.Errorf("filtro inválido devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	dir := t.TempDir()
+	good := writeFile(t, dir, "ok.json", sampleExport)
+	bad := writeFile(t, dir, "bad.json", `[{"title":"X","created":"ayer"},{"title":"X"}]`)
+
+	if code := Run([]string{"validate", good}); code != ExitOK {
+		t.Errorf("validate(ok) = %d, want %d", code, ExitOK)
+	}
+	if code := Run([]string{"validate", bad}); code != ExitFindings {
+		t.Errorf("validate(bad) = %d, want %d", code, ExitFindings)
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "tiddlers.json", sampleExport)
+	cfg := writeFile(t, dir, "openpages.yaml", `
+inputs:
+  - path: tiddlers.json
+outputs:
+  - path: out/a.jsonl
+    mode: v3
+  - path: out/a.json
+    mode: tiddlywiki
+filters:
+  exclude_tags: [b]
+`)
+
+	if code := Run([]string{"run", "-config", cfg, "-mode", "v1"}); code != ExitOK {
+		t.Fatalf("run devolvió %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "a.jsonl"))
+	if err != nil {
+		t.Fatalf("no se generó la salida: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("líneas = %d, want 1 (filtro exclude_tags)", lines)
+	}
+	// -mode v1 reemplaza el modo del archivo: v1 usa "textPlain", no "text"
+	if !strings.Contains(string(data), `"textPlain"`) {
+		t.Errorf("el flag -mode no reemplazó el modo configurado: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "out", "a.json")); err != nil {
+		t.Errorf("falta la salida tiddlywiki: %v", err)
+	}
+}
+
+func TestRun_DedupStateAfterOutputs(t *testing.T) {
+	dir := t.TempDir()
+	in := writeFile(t, dir, "tiddlers.json", sampleExport)
+	state := filepath.Join(dir, "seen.txt")
+	writeFile(t, dir, "bloqueo", "no es una carpeta")
+	cfg := func(out string) string {
+		return writeFile(t, dir, "openpages.yaml", `
+inputs:
+  - path: tiddlers.json
+outputs:
+  - path: `+out+`
+    mode: v3
+dedup:
+  state: seen.txt
+`)
+	}
+
+	// Una salida que no se puede escribir no debe dejar hashes marcados.
+	if code := Run([]string{"run", "-config", cfg("bloqueo/a.jsonl")}); code != ExitError {
+		t.Fatalf("run con salida imposible devolvió %d, want %d", code, ExitError)
+	}
+	if data, _ := os.ReadFile(state); len(data) != 0 {
+		t.Errorf("el estado se marcó aunque la salida falló:\n%s", data)
+	}
+	if code := Run([]string{"run", "-config", cfg("out/a.jsonl")}); code != ExitOK {
+		t.Fatalf("run devolvió %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "out", "a.jsonl"))
+	if err != nil {
+		t.Fatalf("no se generó la salida: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("líneas = %d, want 2 (nada debía darse por procesado)", lines)
+	}
+
+	// Lo mismo con el subcomando dedup.
+	os.Remove(state)
+	if code := Run([]string{"dedup", "-input", in, "-output", filepath.Join(dir, "bloqueo", "x.json"), "-state", state}); code != ExitError {
+		t.Fatalf("dedup con salida imposible devolvió %d, want %d", code, ExitError)
+	}
+	if data, _ := os.ReadFile(state); len(data) != 0 {
+		t.Errorf("dedup marcó el estado aunque la salida falló:\n%s", data)
+	}
+	out := filepath.Join(dir, "dedup.json")
+	for run, want := range []int{2, 0} {
+		if code := Run([]string{"dedup", "-input", in, "-output", out, "-state", state}); code != ExitOK {
+			t.Fatalf("dedup devolvió %d", code)
+		}
+		var got []map[string]any
+		data, _ := os.ReadFile(out)
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("salida de dedup: %v", err)
+		}
+		if len(got) != want {
+			t.Errorf("corrida %d: %d tiddlers, want %d", run+1, len(got), want)
+		}
+	}
+}
+
+func TestRun_ExportBatch(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in")
+	if err := os.MkdirAll(filepath.Join(in, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, in, "a.json", sampleExport)
+	writeFile(t, in, "notas.txt", "no es JSON")
+	writeFile(t, filepath.Join(in, "sub"), "b.json", sampleExport)
+	out := filepath.Join(dir, "out")
+
+	args := []string{"export", "-input", in, "-output", out, "-mode", "v3", "-recursive", "-workers", "2"}
+	if code := Run(args); code != ExitOK {
+		t.Fatalf("export por lotes devolvió %d", code)
+	}
+	for _, p := range []string{"a_v3.jsonl", filepath.Join("sub", "b_v3.jsonl")} {
+		if _, err := os.Stat(filepath.Join(out, p)); err != nil {
+			t.Errorf("falta %s: %v", p, err)
+		}
+	}
+
+	merged := filepath.Join(dir, "todo.jsonl")
+	if code := Run([]string{"export", "-input", in, "-output", merged, "-mode", "v3", "-recursive", "-merge"}); code != ExitOK {
+		t.Fatalf("export -merge devolvió %d", code)
+	}
+	data, _ := os.ReadFile(merged)
+	if lines := strings.Count(string(data), "\n"); lines != 4 {
+		t.Errorf("líneas combinadas = %d, want 4", lines)
+	}
+
+	// Un archivo inválido no detiene a los demás, pero el comando falla.
+	writeFile(t, in, "roto.json", "{no")
+	if code := Run([]string{"export", "-input", in, "-output", out, "-mode", "v3"}); code != ExitError {
+		t.Errorf("con un archivo roto devolvió %d, want %d", code, ExitError)
+	}
+	if _, err := os.Stat(filepath.Join(out, "a_v3.jsonl")); err != nil {
+		t.Errorf("el archivo válido debió exportarse: %v", err)
+	}
+}
+
+func TestRun_Merge(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.json", sampleExport)
+	b := writeFile(t, dir, "b.json", `[{"title":"Foo","text":"otra","modified":"20250201120000"}]`)
+	out := filepath.Join(dir, "todo.json")
+	report := filepath.Join(dir, "conflictos.json")
+
+	args := []string{"merge", "-policy", "prefix", "-report", report, "-output", out, a, "equipoB=" + b}
+	if code := Run(args); code != ExitOK {
+		t.Fatalf("merge devolvió %d", code)
+	}
+	data, _ := os.ReadFile(out)
+	for _, want := range []string{`"a/Foo"`, `"equipoB/Foo"`, `"source_wiki": "equipoB"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("falta %s en la salida:\n%s", want, data)
+		}
+	}
+	if rep, _ := os.ReadFile(report); !strings.Contains(string(rep), `"resolution": "prefix"`) {
+		t.Errorf("informe inesperado:\n%s", rep)
+	}
+
+	if code := Run([]string{"merge", "-policy", "fail", "-output", out, a, b}); code != ExitFindings {
+		t.Errorf("merge -policy fail devolvió %d, want %d", code, ExitFindings)
+	}
+}
+
+func TestRun_ExportStdio(t *testing.T) {
+	dir := t.TempDir()
+	stdin, err := os.Open(writeFile(t, dir, "in.json", sampleExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	stdout, err := os.Create(filepath.Join(dir, "stdout.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	oldIn, oldOut := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	code := Run([]string{"export", "-mode", "v3"})
+	os.Stdin, os.Stdout = oldIn, oldOut
+	if code != ExitOK {
+		t.Fatalf("export por stdin/stdout devolvió %d", code)
+	}
+
+	// stdout sólo debe contener registros JSONL: nada de mensajes de progreso.
+	data, _ := os.ReadFile(stdout.Name())
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("stdout tiene %d líneas, want 2:\n%s", len(lines), data)
+	}
+	for _, l := range lines {
+		if !json.Valid([]byte(l)) {
+			t.Errorf("línea que no es un registro en stdout: %s", l)
+		}
+	}
+}
+
+var update = flag.Bool("update", false, "regenerar los archivos golden de testdata/golden")
+
+// TestRun_ExportGolden comprueba que -deterministic produce exactamente los
+// mismos bytes en cada corrida, también con varios workers.  Para regenerar
+// los esperados: go test ./internal/cli -run Golden -update
+func TestRun_ExportGolden(t *testing.T) {
+	input := filepath.Join("testdata", "golden", "input.json")
+	cases := []struct {
+		name string
+		args []string
+	}{
+		{"v1", []string{"-mode", "v1"}},
+		{"v2", []string{"-mode", "v2"}},
+		{"v3", []string{"-mode", "v3"}},
+		{"hybrid", []string{"-mode", "hybrid"}},
+		{"v3_title", []string{"-mode", "v3", "-sort", "title"}},
+		{"v3_fixed", []string{"-mode", "v3", "-fallback-date", "20240101000000"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			golden := filepath.Join("testdata", "golden", tc.name+".jsonl")
+			outDir := t.TempDir()
+			args := append([]string{"export", "-input", input, "-output", outDir, "-deterministic", "-workers", "4"}, tc.args...)
+			if code := Run(args); code != ExitOK {
+				t.Fatalf("export %v devolvió %d", tc.args, code)
+			}
+			files, _ := filepath.Glob(filepath.Join(outDir, "*.jsonl"))
+			if len(files) != 1 {
+				t.Fatalf("se esperaba un archivo de salida, hay %v", files)
+			}
+			got, err := os.ReadFile(files[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("falta %s (use -update): %v", golden, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("salida distinta de %s:\n got: %s\nwant: %s", golden, got, want)
+			}
+		})
+	}
+
+	// -deterministic rechaza una fecha de respaldo que depende del reloj.
+	bad := []string{"export", "-input", input, "-output", filepath.Join(t.TempDir(), "x.jsonl"), "-deterministic", "-fallback-date", "now"}
+	if code := Run(bad); code != ExitUsage {
+		t.Errorf("-deterministic -fallback-date now devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Roundtrip(t *testing.T) {
+	input := filepath.Join("testdata", "golden", "input.json")
+	report := filepath.Join(t.TempDir(), "rt.json")
+	if code := Run([]string{"roundtrip", "-modes", "v3", "-strict", "-report", report, input}); code != ExitOK {
+		t.Fatalf("roundtrip v3 -strict devolvió %d, want %d", code, ExitOK)
+	}
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rep struct {
+		Modes []struct {
+			Mode   string `json:"mode"`
+			Intact int    `json:"intact"`
+		} `json:"modes"`
+	}
+	if err := json.Unmarshal(data, &rep); err != nil || len(rep.Modes) != 1 || rep.Modes[0].Intact != 4 {
+		t.Errorf("informe inesperado (%v): %s", err, data)
+	}
+
+	// v1 reindenta los textos JSON: con -strict es un hallazgo.
+	if code := Run([]string{"roundtrip", "-modes", "v1,v3", "-strict", input}); code != ExitFindings {
+		t.Errorf("roundtrip v1 -strict devolvió %d, want %d", code, ExitFindings)
+	}
+	if code := Run([]string{"roundtrip", "-modes", "v9", input}); code != ExitUsage {
+		t.Errorf("modo inválido devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Normalize(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "v3.jsonl")
+	input := filepath.Join("testdata", "golden", "input.json")
+	if code := Run([]string{"normalize", "-deterministic", "-input", input, "-output", out}); code != ExitOK {
+		t.Fatalf("normalize devolvió %d, want %d", code, ExitOK)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 4 {
+		t.Errorf("%d líneas, want 4", n)
+	}
+
+	// Las líneas ilegibles son un hallazgo, pero el resto se escribe.
+	mixed := filepath.Join(dir, "mezcla.jsonl")
+	if err := os.WriteFile(mixed, []byte("{\"title\":\"A\"}\n{roto\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code := Run([]string{"normalize", "-input", mixed, "-output", out}); code != ExitFindings {
+		t.Errorf("entrada con líneas rotas devolvió %d, want %d", code, ExitFindings)
+	}
+	if data, _ := os.ReadFile(out); !strings.Contains(string(data), `"title":"A"`) {
+		t.Errorf("falta el registro válido: %s", data)
+	}
+}
+
+func TestRun_Migrate(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join("testdata", "golden")
+	// Bajar el export v3 reproduce byte a byte el export v1 y v2 de la misma entrada.
+	for _, to := range []string{"v1", "v2"} {
+		out := filepath.Join(dir, to+".jsonl")
+		if code := Run([]string{"migrate", "-deterministic", "-input", filepath.Join(golden, "v3.jsonl"), "-output", out, "-to", to}); code != ExitOK {
+			t.Fatalf("migrate -to %s devolvió %d", to, code)
+		}
+		got, _ := os.ReadFile(out)
+		want, _ := os.ReadFile(filepath.Join(golden, to+".jsonl"))
+		if string(got) != string(want) {
+			t.Errorf("migrate -to %s:\n%s\nwant:\n%s", to, got, want)
+		}
+	}
+
+	if code := Run([]string{"migrate", "-input", filepath.Join(golden, "v3.jsonl")}); code != ExitUsage {
+		t.Errorf("sin -to devolvió %d, want %d", code, ExitUsage)
+	}
+	if code := Run([]string{"migrate", "-input", filepath.Join(golden, "v3.jsonl"), "-to", "v3", "-from", "v7"}); code != ExitUsage {
+		t.Errorf("-from v7 devolvió %d, want %d", code, ExitUsage)
+	}
+	broken := writeFile(t, dir, "roto.jsonl", "{\"id\":\"A\",\"title\":\"A\",\"text\":\"x\"}\n{roto\n")
+	if code := Run([]string{"migrate", "-input", broken, "-output", filepath.Join(dir, "x.jsonl"), "-to", "v2"}); code != ExitFindings {
+		t.Errorf("línea rota devolvió %d, want %d", code, ExitFindings)
+	}
+}
+
+func TestRun_Merge3(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	base := write("base.json", `[{"title":"A","text":"uno\ndos","tags":"x","path":"w/a"},{"title":"B","text":"b"}]`)
+	theirs := write("theirs.json", `[{"title":"A","text":"uno\ndos\ntres","tags":"x","path":"w/a"},{"title":"B","text":"b de la wiki"}]`)
+
+	// ours: export v1 (sin path) con el texto de A editado.
+	if code := Run([]string{"export", "-input", base, "-output", dir, "-mode", "v1"}); code != ExitOK {
+		t.Fatalf("export devolvió %d", code)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*_v1.jsonl"))
+	if len(matches) != 1 {
+		t.Fatalf("export v1: %v", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	ours := write("ours.jsonl", strings.ReplaceAll(string(data), `uno\ndos`, `UNO\ndos`))
+
+	out := filepath.Join(dir, "out.json")
+	report := filepath.Join(dir, "rep.json")
+	if code := Run([]string{"merge3", "-base", base, "-ours", ours, "-theirs", theirs, "-output", out, "-report", report}); code != ExitOK {
+		t.Fatalf("merge3 devolvió %d, want %d", code, ExitOK)
+	}
+	var got []map[string]any
+	data, _ = os.ReadFile(out)
+	if err := json.Unmarshal(data, &got); err != nil || len(got) != 2 {
+		t.Fatalf("salida inesperada (%v): %s", err, data)
+	}
+	if got[0]["text"] != "UNO\ndos\ntres" || got[0]["path"] != "w/a" || got[1]["text"] != "b de la wiki" {
+		t.Errorf("fusión inesperada: %s", data)
+	}
+
+	// Mismo texto editado en ambos lados: conflicto (código 3), vía revert -base.
+	conflict := write("ours2.jsonl", `{"id":"B","textPlain":"b editado"}`+"\n")
+	if code := Run([]string{"revert", "-template", theirs, "-base", base, "-input", conflict, "-output", out}); code != ExitFindings {
+		t.Errorf("revert -base con conflicto devolvió %d, want %d", code, ExitFindings)
+	}
+	data, _ = os.ReadFile(out)
+	if err := json.Unmarshal(data, &got); err != nil || !strings.Contains(got[1]["text"].(string), "<<<<<<< ours") {
+		t.Errorf("faltan marcadores de conflicto (%v): %s", err, data)
+	}
+	if code := Run([]string{"revert", "-base", base, "-input", conflict, "-output", out}); code != ExitUsage {
+		t.Errorf("-base sin -template devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_RevertFields(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "wiki.json")
+	if err := os.WriteFile(tmpl, []byte(`[{"title":"A","text":"a","tags":"x","color":"red","modified":"20240101000000","caption":"c"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	in := filepath.Join(dir, "editado.jsonl")
+	line := `{"id":"A","title":"A","text":"a","tags":["x","y"],"color":"blue","fields":{"caption":"C"}}`
+	if err := os.WriteFile(in, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.json")
+	report := filepath.Join(dir, "cambios.json")
+	args := []string{"revert", "-template", tmpl, "-input", in, "-output", out,
+		"-fields", "tags,color,fields.caption", "-bump", "tags", "-report", report}
+	if code := Run(args); code != ExitOK {
+		t.Fatalf("revert -fields devolvió %d, want %d", code, ExitOK)
+	}
+	var got []map[string]any
+	data, _ := os.ReadFile(out)
+	if err := json.Unmarshal(data, &got); err != nil || len(got) != 1 {
+		t.Fatalf("salida inesperada (%v): %s", err, data)
+	}
+	if got[0]["color"] != "blue" || got[0]["caption"] != "C" || got[0]["tags"] != "[[x]] [[y]]" || got[0]["modified"] == "20240101000000" {
+		t.Errorf("plantilla actualizada = %v", got[0])
+	}
+	var rep struct {
+		Changes []struct{ Field string } `json:"changes"`
+	}
+	data, _ = os.ReadFile(report)
+	if err := json.Unmarshal(data, &rep); err != nil || len(rep.Changes) != 3 {
+		t.Errorf("informe inesperado (%v): %s", err, data)
+	}
+
+	if code := Run([]string{"revert", "-template", tmpl, "-input", in, "-output", out, "-fields", "created"}); code != ExitUsage {
+		t.Errorf("-fields inválido devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	dir := t.TempDir()
+	before := writeFile(t, dir, "antes.json", sampleExport)
+	// Foo cambia de texto, Bar se renombra a Baz y aparece Qux; la entrada
+	// nueva es JSONL v3, así que las formas no tienen por qué coincidir.
+	after := writeFile(t, dir, "despues.jsonl",
+		`{"id":"Foo","title":"Foo","type":"text/plain","text":"hola\nmundo","tags":["a"],"created":"2025-01-01T12:00:00Z","modified":"2025-01-02T12:00:00Z"}`+"\n"+
+			`{"id":"Baz","title":"Baz","type":"text/plain","text":"mundo","tags":["b"],"created":"2025-01-03T12:00:00Z","modified":"2025-01-04T12:00:00Z"}`+"\n"+
+			`{"id":"Qux","title":"Qux","type":"text/plain","text":"nuevo"}`+"\n")
+
+	out := filepath.Join(dir, "diff.json")
+	if code := Run([]string{"diff", "-format", "json", "-output", out, before, after}); code != ExitOK {
+		t.Fatalf("diff devolvió %d, want %d", code, ExitOK)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res struct {
+		Added, Removed, Renamed, Modified int
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Added != 1 || res.Removed != 0 || res.Renamed != 1 || res.Modified != 1 {
+		t.Errorf("informe = %+v\n%s", res, data)
+	}
+
+	if code := Run([]string{"diff", "-exit-code", "-output", out, before, after}); code != ExitFindings {
+		t.Errorf("-exit-code con diferencias devolvió %d, want %d", code, ExitFindings)
+	}
+	if code := Run([]string{"diff", "-exit-code", "-output", out, before, before}); code != ExitOK {
+		t.Errorf("-exit-code sin diferencias devolvió %d, want %d", code, ExitOK)
+	}
+	if code := Run([]string{"diff", "-format", "html", before, after}); code != ExitUsage {
+		t.Errorf("formato desconocido devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Apply(t *testing.T) {
+	dir := t.TempDir()
+	wiki := writeFile(t, dir, "wiki.json", sampleExport)
+	revised := writeFile(t, dir, "revisado.json", `[
+  {"title":"Foo","text":"hola corregido","type":"text/plain","tags":"[[a]]","created":"20250101120000","modified":"20250105120000"},
+  {"title":"Bar","text":"mundo","type":"text/plain","tags":"[[b]]","created":"20250103120000","modified":"20250104120000"}
+]`)
+	patchPath := filepath.Join(dir, "cambios.patch.jsonl")
+	if code := Run([]string{"diff", "-format", "patch", "-output", patchPath, wiki, revised}); code != ExitOK {
+		t.Fatalf("diff -format patch devolvió %d, want %d", code, ExitOK)
+	}
+
+	out := filepath.Join(dir, "nueva.json")
+	if code := Run([]string{"apply", "-patch", patchPath, "-input", wiki, "-output", out}); code != ExitOK {
+		t.Fatalf("apply devolvió %d, want %d", code, ExitOK)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0]["text"] != "hola corregido" || got[0]["modified"] != "20250105120000" {
+		t.Errorf("resultado = %s", data)
+	}
+
+	// Sobre la wiki ya parcheada el parche está obsoleto: no se escribe nada.
+	stale := filepath.Join(dir, "otra.json")
+	if code := Run([]string{"apply", "-patch", patchPath, "-input", out, "-output", stale}); code != ExitFindings {
+		t.Errorf("parche obsoleto devolvió %d, want %d", code, ExitFindings)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("un parche rechazado no debería escribir salida")
+	}
+	if code := Run([]string{"apply", "-patch", patchPath}); code != ExitUsage {
+		t.Errorf("sin -input ni -folder devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_Subtree(t *testing.T) {
+	dir := t.TempDir()
+	wiki := writeFile(t, dir, "wiki.json", `[
+  {"title":"Proyecto","text":"Ver [[Guía]]","list":"Capítulo"},
+  {"title":"Capítulo","text":"uno"},
+  {"title":"Guía","text":"guía"},
+  {"title":"Tarea","text":"tarea","tags":"Proyecto"},
+  {"title":"Ajeno","text":"otro proyecto"}
+]`)
+	out := filepath.Join(dir, "proyecto.json")
+	if code := Run([]string{"subtree", "-input", wiki, "-output", out, "-root", "Proyecto"}); code != ExitOK {
+		t.Fatalf("subtree devolvió %d, want %d", code, ExitOK)
+	}
+	var got []map[string]any
+	data, _ := os.ReadFile(out)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Errorf("%d tiddlers, want 4: %s", len(got), data)
+	}
+
+	plugin := filepath.Join(dir, "plugin.json")
+	if code := Run([]string{"subtree", "-input", wiki, "-output", plugin, "-root", "Proyecto", "-depth", "0", "-format", "plugin"}); code != ExitOK {
+		t.Fatalf("subtree -format plugin devolvió %d, want %d", code, ExitOK)
+	}
+	data, _ = os.ReadFile(plugin)
+	got = nil
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["title"] != "$:/plugins/openpages/proyecto" || got[0]["plugin-type"] != "plugin" {
+		t.Errorf("plugin = %s", data)
+	}
+
+	if code := Run([]string{"subtree", "-input", wiki, "-output", out, "-root", "No existe"}); code != ExitError {
+		t.Errorf("raíz inexistente devolvió %d, want %d", code, ExitError)
+	}
+}
+
+func TestRun_Plugin(t *testing.T) {
+	dir := t.TempDir()
+	wiki := writeFile(t, dir, "wiki.json", `[
+  {"title":"Glosario","text":"índice"},
+  {"title":"Término","text":"definición","tags":"Glosario"},
+  {"title":"Otro","text":"fuera del plugin"}
+]`)
+	pluginPath := filepath.Join(dir, "glosario.json")
+	if code := Run([]string{"plugin", "-input", wiki, "-output", pluginPath, "-title", "$:/plugins/equipo/glosario",
+		"-tag", "Glosario", "-version", "1.2.0", "-description", "Glosario común", "-dependents", "$:/plugins/equipo/base"}); code != ExitOK {
+		t.Fatalf("plugin devolvió %d, want %d", code, ExitOK)
+	}
+	var got []map[string]any
+	data, _ := os.ReadFile(pluginPath)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["version"] != "1.2.0" || got[0]["dependents"] != "$:/plugins/equipo/base" ||
+		strings.Contains(got[0]["text"].(string), "Otro") {
+		t.Errorf("plugin = %s", data)
+	}
+
+	unpacked := filepath.Join(dir, "sueltos.json")
+	if code := Run([]string{"plugin", "-unpack", "-input", pluginPath, "-output", unpacked}); code != ExitOK {
+		t.Fatalf("plugin -unpack devolvió %d, want %d", code, ExitOK)
+	}
+	got = nil
+	data, _ = os.ReadFile(unpacked)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[1]["plugin"] != "$:/plugins/equipo/glosario" {
+		t.Errorf("desempaquetado = %s", data)
+	}
+
+	// export -unpack-plugins convierte también los shadows.
+	outDir := filepath.Join(dir, "out")
+	if code := Run([]string{"export", "-input", pluginPath, "-output", outDir, "-mode", "v3", "-unpack-plugins"}); code != ExitOK {
+		t.Fatalf("export -unpack-plugins devolvió %d, want %d", code, ExitOK)
+	}
+	data, _ = os.ReadFile(filepath.Join(outDir, "glosario_v3.jsonl"))
+	if n := strings.Count(string(data), "\n"); n != 3 {
+		t.Errorf("%d registros, want 3:\n%s", n, data)
+	}
+
+	if code := Run([]string{"plugin", "-input", wiki, "-output", pluginPath}); code != ExitUsage {
+		t.Errorf("sin -title devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_ExportSystemPolicy(t *testing.T) {
+	dir := t.TempDir()
+	in := writeFile(t, dir, "wiki.json", `[
+  {"title":"Nota","text":"contenido"},
+  {"title":"$:/config/Tema","text":"oscuro"},
+  {"title":"$:/StoryList","list":"Nota"},
+  {"title":"Draft of 'Nota'","text":"a medias","draft.of":"Nota"}
+]`)
+
+	out := filepath.Join(dir, "split", "wiki.jsonl")
+	if code := Run([]string{"export", "-input", in, "-output", out, "-mode", "v3", "-system-policy", "split"}); code != ExitOK {
+		t.Fatalf("export -system-policy split devolvió %d", code)
+	}
+	content, _ := os.ReadFile(filepath.Join(dir, "split", "wiki_v3.jsonl"))
+	system, _ := os.ReadFile(filepath.Join(dir, "split", "wiki_v3_system.jsonl"))
+	if strings.Count(string(content), "\n") != 1 || !strings.Contains(string(content), `"kind":"content"`) {
+		t.Errorf("contenido = %s", content)
+	}
+	for _, want := range []string{`"kind":"system"`, `"kind":"state"`, `"kind":"draft"`, `"is_system":true`} {
+		if !strings.Contains(string(system), want) {
+			t.Errorf("falta %s en %s", want, system)
+		}
+	}
+	if n := strings.Count(string(system), "\n"); n != 3 {
+		t.Errorf("%d registros de sistema, want 3", n)
+	}
+
+	if code := Run([]string{"export", "-input", in, "-output", "-", "-system-policy", "split"}); code != ExitUsage {
+		t.Errorf("split a stdout sin -system-output devolvió %d, want %d", code, ExitUsage)
+	}
+	if code := Run([]string{"export", "-input", in, "-system-policy", "ocultar"}); code != ExitUsage {
+		t.Errorf("política desconocida devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestRun_ExportChunks(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("Una oración de relleno para el manual. ", 40)
+	in := writeFile(t, dir, "wiki.json", `[
+  {"title":"Manual","text":"! Uso\n`+long+`\n! Límites\nPocos."},
+  {"title":"Nota","text":"corta"}
+]`)
+	outDir := filepath.Join(dir, "out")
+	if code := Run([]string{"export", "-input", in, "-output", outDir, "-mode", "v2", "-chunk-tokens", "64", "-chunk-overlap", "8"}); code != ExitOK {
+		t.Fatalf("export -chunk-tokens devolvió %d", code)
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, "wiki_v2.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 4 {
+		t.Fatalf("%d fragmentos, want más de 3:\n%s", len(lines), data)
+	}
+	var last map[string]any
+	for _, line := range lines {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec["type"] != "fragment" || rec["parent"] == nil {
+			t.Errorf("registro sin tipo fragment o parent: %s", line)
+		}
+		if info := rec["chunk"].(map[string]any); info["tokens"].(float64) > 64 {
+			t.Errorf("fragmento de %v tokens", info["tokens"])
+		}
+		if rec["parent"] == "Manual" {
+			last = rec
+		}
+	}
+	if crumb := last["chunk"].(map[string]any)["breadcrumb"]; !reflect.DeepEqual(crumb, []any{"Límites"}) {
+		t.Errorf("breadcrumb = %v", crumb)
+	}
+
+	if code := Run([]string{"export", "-input", in, "-output", outDir, "-mode", "v3", "-chunk-tokens", "64"}); code != ExitUsage {
+		t.Errorf("-chunk-tokens con v3 devolvió %d, want %d", code, ExitUsage)
+	}
+}
+
+// Un export fragmentado no se puede revertir: los fragmentos se omiten (con
+// aviso) y, si no queda ningún tiddler, el comando falla en lugar de
+// escribir una wiki vacía.
+func TestRun_RevertChunks(t *testing.T) {
+	dir := t.TempDir()
+	long := strings.Repeat("Una oración de relleno para el manual. ", 40)
+	in := writeFile(t, dir, "wiki.json", `[{"title":"Manual","text":"! Uso\n`+long+`"}]`)
+	outDir := filepath.Join(dir, "out")
+	chunked := filepath.Join(outDir, "wiki_v2.jsonl")
+	if code := Run([]string{"export", "-input", in, "-output", outDir, "-mode", "v2", "-chunk-tokens", "64"}); code != ExitOK {
+		t.Fatalf("export -chunk-tokens devolvió %d", code)
+	}
+	out := filepath.Join(dir, "wiki_restaurada.json")
+	if code := Run([]string{"revert", "-input", chunked, "-output", out}); code != ExitError {
+		t.Errorf("revert de un export fragmentado devolvió %d, want %d", code, ExitError)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Error("revert escribió una salida sin tiddlers")
+	}
+
+	// Con registros normales al lado, los fragmentos se omiten y el resto vuelve.
+	data, err := os.ReadFile(chunked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed := writeFile(t, dir, "mixto.jsonl", string(data)+`{"id":"Nota","meta":{"title":"Nota"},"content":{"plain":"corta"}}`+"\n")
+	for _, args := range [][]string{
+		{"revert", "-input", mixed, "-output", out},
+		{"normalize", "-input", mixed, "-output", filepath.Join(dir, "norm.jsonl")},
+	} {
+		if code := Run(args); code != ExitOK {
+			t.Fatalf("%s devolvió %d", args[0], code)
+		}
+	}
+	var restored []map[string]any
+	data, _ = os.ReadFile(out)
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 1 || restored[0]["title"] != "Nota" {
+		t.Errorf("revert = %v, want sólo Nota", restored)
+	}
+	data, _ = os.ReadFile(filepath.Join(dir, "norm.jsonl"))
+	if lines := strings.Count(string(data), "\n"); lines != 1 {
+		t.Errorf("normalize escribió %d líneas, want 1:\n%s", lines, data)
+	}
+
+	// diff tampoco confunde los fragmentos con versiones del tiddler.
+	before := writeFile(t, dir, "antes.jsonl", `{"id":"Nota","meta":{"title":"Nota"},"content":{"plain":"corta"}}`+"\n")
+	if code := Run([]string{"diff", "-output", filepath.Join(dir, "cambios.txt"), before, mixed}); code != ExitOK {
+		t.Errorf("diff sin cambios reales devolvió %d, want %d", code, ExitOK)
+	}
+}
@@ -0,0 +1,116 @@
+// internal/cli/dedup.go – Subcomando `dedup`
+// --------------------------------------------------------------------------------
+// Elimina duplicados exactos (dedup.HashTiddler + Store) y, opcionalmente,
+// casi-duplicados (dedup.FindNearDuplicates).  Con -state los hashes se
+// persisten en un FileStore, de modo que corridas sucesivas sólo emiten
+// tiddlers nuevos o modificados.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func runDedup(args []string) error {
+	fs := newFlagSet("dedup", "[-input origen.json|-] [-output destino.json|-] [flags]", `
+Elimina tiddlers duplicados de un export de TiddlyWiki.  Los duplicados exactos
+se detectan por hash (título, modified y texto).  Con -near también se agrupan
+los casi-duplicados y se conserva un representante por cluster.`)
+	in := fs.String("input", "", "Export JSON de TiddlyWiki (\"-\" = stdin)")
+	out := fs.String("output", "", "Archivo JSON TiddlyWiki de salida (\"-\" = stdout, por defecto)")
+	state := fs.String("state", "", "Archivo de hashes ya vistos (persistente entre corridas)")
+	near := fs.Bool("near", false, "Eliminar también casi-duplicados")
+	report := fs.String("report", "", "Ruta del informe JSON de clusters de casi-duplicados")
+	method := fs.String("method", dedup.MethodMinHash, "Método de casi-duplicados: minhash | simhash")
+	threshold := fs.Float64("threshold", 0.8, "Similitud Jaccard mínima para agrupar (minhash)")
+	pretty := fs.Bool("pretty", true, "Indentar el JSON de salida")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+
+	tiddlers, err := importer.Read(context.Background(), *in)
+	if err != nil {
+		return fmt.Errorf("leyendo tiddlers: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "📦 %d tiddlers cargados\n", len(tiddlers))
+
+	var store dedup.Store = dedup.NewMemStore()
+	if *state != "" {
+		fstore, err := dedup.NewFileStore(*state)
+		if err != nil {
+			return fmt.Errorf("abriendo estado %s: %w", *state, err)
+		}
+		store = fstore
+	}
+	defer func() {
+		if store != nil {
+			store.Close() // la salida falló: no se marca ningún hash
+		}
+	}()
+	unique, fresh := dropSeen(tiddlers, store)
+	fmt.Fprintf(os.Stderr, "🧹 %d duplicados exactos eliminados\n", len(tiddlers)-len(unique))
+
+	if *near || *report != "" {
+		unique, err = applyNearDedup(unique, dedup.NearOptions{Method: *method, Threshold: *threshold}, *report, *near)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := exporter.WriteJSON(*out, unique, *pretty); err != nil {
+		return err
+	}
+	err = markSeen(store, fresh)
+	store = nil
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Deduplicación completada: %d tiddlers (destino: %s)\n", len(unique), *out)
+	return nil
+}
+
+// dropSeen conserva sólo los tiddlers cuyo hash no estaba en store ni se
+// repite en tiddlers.  No marca nada: devuelve los hashes nuevos para que el
+// llamador los pase a markSeen cuando la salida ya esté escrita, de modo que
+// una corrida fallida no dé por procesados tiddlers que nunca se exportaron.
+func dropSeen(tiddlers []models.Tiddler, store dedup.Store) ([]models.Tiddler, []string) {
+	out := make([]models.Tiddler, 0, len(tiddlers))
+	var fresh []string
+	batch := make(map[string]struct{})
+	for _, t := range tiddlers {
+		h := dedup.HashTiddler(t)
+		if _, dup := batch[h]; dup || store.Seen(h) {
+			continue
+		}
+		batch[h] = struct{}{}
+		fresh = append(fresh, h)
+		out = append(out, t)
+	}
+	return out, fresh
+}
+
+// markSeen registra hashes en store y lo cierra.
+func markSeen(store dedup.Store, hashes []string) error {
+	var err error
+	for _, h := range hashes {
+		if err = store.Mark(h); err != nil {
+			err = fmt.Errorf("marcando hash: %w", err)
+			break
+		}
+	}
+	if cerr := store.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
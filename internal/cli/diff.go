@@ -0,0 +1,137 @@
+// internal/cli/diff.go – Subcomando `diff`
+// --------------------------------------------------------------------------------
+// Compara dos entradas de cualquier forma admitida (export de TiddlyWiki o
+// JSONL de cualquier modo) por título y lista los tiddlers añadidos,
+// eliminados, renombrados y modificados, con los campos que cambiaron y el
+// diff unificado de los textos (ver internal/diff).  Con -format patch escribe
+// en su lugar un parche aplicable con `apply` (ver internal/patch).
+//
+//   openpages diff semana_pasada.json hoy.json
+//   openpages diff -format markdown -output cambios.md antes.jsonl despues.jsonl
+//   openpages diff -format patch -output cambios.patch.jsonl wiki.json revisado.jsonl
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/diff"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/patch"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func runDiff(args []string) error {
+	fs := newFlagSet("diff", "[flags] antes despues", `
+Compara dos exports (JSON de TiddlyWiki o JSONL de cualquier modo; "-" = stdin)
+por título e informa qué tiddlers se añadieron, eliminaron, renombraron (mismo
+contenido, otro título) o modificaron, campo a campo.  Para comparar campos que
+un modo JSONL no conserva, compara entradas de la misma forma.  -format patch
+escribe un parche JSONL aplicable con "openpages apply".`)
+	formats := append(slices.Clip(diff.Formats), "patch")
+	format := fs.String("format", "text", "Formato del informe: "+strings.Join(formats, " | "))
+	out := fs.String("output", exporter.Stdio, "Archivo del informe (\"-\" = stdout)")
+	lines := fs.Int("context", 3, "Líneas de contexto en los diffs de texto")
+	exitCode := fs.Bool("exit-code", false, "Terminar con código 3 si hay diferencias")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return usagef("se necesitan exactamente dos archivos a comparar")
+	}
+	if !slices.Contains(formats, *format) {
+		return usagef("formato desconocido: %s (usa %s)", *format, strings.Join(formats, ", "))
+	}
+	if *lines < 0 {
+		return usagef("-context no puede ser negativo")
+	}
+	if fs.Arg(0) == importer.Stdio && fs.Arg(1) == importer.Stdio {
+		return usagef("sólo una de las entradas puede ser stdin")
+	}
+
+	before, err := readAnyShape(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := readAnyShape(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != exporter.Stdio {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("no se pudo crear '%s': %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := writeDiff(w, before, after, *format, diff.Options{Before: fs.Arg(0), After: fs.Arg(1), Context: *lines})
+	if err != nil {
+		return err
+	}
+	if *out != exporter.Stdio {
+		fmt.Fprintf(os.Stderr, "📝 Informe de diferencias: %s\n", *out)
+	}
+	if *exitCode && n > 0 {
+		return findingsError{msg: fmt.Sprintf("%d diferencias", n)}
+	}
+	return nil
+}
+
+// writeDiff escribe el informe (o el parche) y devuelve cuántos tiddlers
+// difieren.
+func writeDiff(w io.Writer, before, after []models.Tiddler, format string, opts diff.Options) (int, error) {
+	if format == "patch" {
+		ops, err := patch.Make(before, after)
+		if err != nil {
+			return 0, err
+		}
+		if err := patch.Write(w, ops); err != nil {
+			return 0, fmt.Errorf("escribiendo parche: %w", err)
+		}
+		return len(ops), nil
+	}
+	res, err := diff.Compare(before, after, opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := diff.Write(w, res, format); err != nil {
+		return 0, fmt.Errorf("escribiendo informe: %w", err)
+	}
+	return len(res.Entries), nil
+}
+
+// readAnyShape lee un export de TiddlyWiki o un JSONL de cualquier modo.  Un
+// registro ilegible invalida la comparación: se listan y se devuelve error.
+func readAnyShape(path string) ([]models.Tiddler, error) {
+	var r io.Reader = os.Stdin
+	if path != importer.Stdio {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo abrir '%s': %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	ts, _, err := transform.ReadRecords(r)
+	var skipped *transform.SkippedError
+	if errors.As(err, &skipped) {
+		for _, l := range skipped.Lines {
+			fmt.Fprintf(os.Stderr, "  • %v\n", l)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("leyendo %s: %w", path, err)
+	}
+	return ts, nil
+}
@@ -0,0 +1,472 @@
+// internal/cli/export.go – Subcomando `export`
+// --------------------------------------------------------------------------------
+// Pipeline principal: importer.Stream → transform.ConvertTiddler{,V2,V3,Hybrid}
+// → exporter.RecordWriter (jsonl | json | csv | parquet).  Cada tiddler se
+// convierte y escribe en cuanto se lee, con memoria acotada aun en exports
+// enormes.  La conversión se reparte entre -workers goroutines
+// (transform.ConvertStream) sin alterar el orden de salida.  Si se piden
+// casi-duplicados (ver dedup.FindNearDuplicates) hace falta ver todo el
+// conjunto, así que el archivo se carga completo.
+//
+// Salida reproducible: -deterministic garantiza archivos idénticos byte a byte
+// entre corridas (sin fechas de respaldo tomadas del reloj); -sort title
+// ordena por título en lugar de conservar el orden del archivo.  -tz elige la
+// zona en que v2 y v3 escriben las fechas (TiddlyWiki las guarda en UTC).
+// -filter selecciona los tiddlers con una expresión de filtro de TiddlyWiki
+// (ver internal/filter) antes de convertirlos.  -system-policy decide qué
+// hacer con lo que no es contenido (sistema, borradores, estado y plugins;
+// ver internal/classify): incluirlo, descartarlo o separarlo en otro archivo.
+//
+//   openpages export -input data/in/tiddlers.json -output data/out -mode v3
+//   openpages export -mode v3 -filter '[tag[Glosario]!is[system]] +[sort[title]]'
+//   openpages export -input wiki.json -output out -mode v3 -system-policy split
+//   openpages export -input wiki.json -output out -mode v2 -chunk-tokens 512 -chunk-overlap 64
+//   curl -s https://wiki/tiddlers.json | openpages export -mode v3 | jq .title
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/chunk"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/classify"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/filter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// exportModes describe los modos de conversión admitidos por `export`.
+var exportModes = map[string]string{
+	"v1":     "Compacto heredado (TextPlain/TextMarkdown)",
+	"v2":     "Meta + Content (AI-friendly, contexto rico)",
+	"v3":     "Minimal JSONL (una línea por objeto, ideal para IA)",
+	"hybrid": "Híbrido (estructura extendida para IA/RAG)",
+}
+
+// exportOptions agrupa los flags de `export` que se aplican a cada archivo.
+type exportOptions struct {
+	mode       string
+	format     string
+	pretty     bool
+	workers    int    // goroutines de conversión por archivo
+	sortBy     string // "input" (orden del archivo) | "title"
+	conv       transform.Options
+	near       dedup.NearOptions
+	nearKeep   bool
+	nearReport string
+	unpack     bool           // desempaquetar los plugins en tiddlers sueltos
+	filter     *filter.Filter // nil = todos los tiddlers
+	policy     string         // classify.Policy*: qué hacer con lo que no es contenido
+	systemOut  string         // destino con policy split; "" = <salida>_system.<ext>
+	chunk      chunk.Options  // MaxTokens > 0: fragmentos v2 en lugar de un registro por tiddler
+}
+
+func (o exportOptions) wantsNear() bool { return o.nearKeep || o.nearReport != "" }
+
+// splitsKinds indica si la política aparta los tiddlers que no son contenido.
+func (o exportOptions) splitsKinds() bool {
+	return o.policy == classify.PolicyExclude || o.policy == classify.PolicySplit
+}
+
+func runExport(args []string) error {
+	fs := newFlagSet("export", "[-input origen.json|carpeta|-] [-output destino.jsonl|carpeta|-] [flags]", `
+Convierte un export JSON de TiddlyWiki en registros según el modo elegido y
+los escribe en el formato de -format.  Si -output es una carpeta (o una ruta
+sin extensión), el archivo se nombra <entrada>_<modo>[_pretty].<formato>
+dentro de ella.
+
+"-" (o la omisión del flag) significa stdin para -input y stdout para
+-output; los mensajes de progreso se escriben siempre en stderr.
+
+Si -input es una carpeta se procesan en paralelo todos los archivos que
+coincidan con -glob (en subcarpetas con -recursive): uno por archivo en
+-output, o todos combinados en un solo JSONL con -merge.
+
+-workers fija las goroutines de conversión de un archivo; en modo carpeta,
+cuántos archivos se procesan a la vez (cada uno con un solo worker).
+
+Con -deterministic dos corridas sobre la misma entrada producen archivos
+idénticos: las fechas ausentes en v3 se omiten (o usan -fallback-date) en vez
+de tomar la hora actual.
+
+-filter admite un subconjunto de los filtros de TiddlyWiki: runs con prefijo
++, - o ~ y los operadores title, field:<campo>, tag, is[system|shadow|tiddler],
+prefix, suffix, has, days, sort y limit (negables con !), p.ej.
+'[tag[Glosario]!is[system]] -[[Borrador]] +[sort[modified]]'.
+
+Cada registro lleva "kind" (content, system, draft, state o plugin) e
+"is_system".  -system-policy include (por defecto) exporta todo, como
+copia completa; exclude deja sólo el contenido, sin ruido para RAG; split
+escribe el resto en -system-output (por defecto <salida>_system.<ext>).
+
+Con -chunk-tokens N (sólo -mode v2) cada tiddler se escribe como registros
+"fragment" de como mucho N tokens, cortados por encabezados, párrafos y
+oraciones, con "parent" (el ID del tiddler), un ID estable y el breadcrumb
+de encabezados.  Los tokens se estiman con -tokenizer (chars ≈ 4 caracteres,
+words ≈ 0,75 palabras por token).`)
+	in := fs.String("input", "", "Archivo o carpeta con JSON exportado de TiddlyWiki (\"-\" = stdin)")
+	out := fs.String("output", "", "Ruta de salida: archivo .jsonl, carpeta o \"-\" (stdout, por defecto)")
+	mode := fs.String("mode", "v1", "Modo de conversión: v1 (plano) | v2 (meta/content) | v3 (JSONL mínimo) | hybrid (IA/RAG)")
+	format := fs.String("format", exporter.FormatJSONL, "Formato de salida: jsonl | json | csv | parquet")
+	pretty := fs.Bool("pretty", false, "Usar indentación en lugar de JSONL compacto")
+	nearDedup := fs.Bool("near-dedup", false, "Conservar un solo tiddler por cluster de casi-duplicados")
+	nearReport := fs.String("near-report", "", "Ruta del informe JSON de clusters de casi-duplicados")
+	nearMethod := fs.String("near-method", dedup.MethodMinHash, "Método de casi-duplicados: minhash | simhash")
+	nearThreshold := fs.Float64("near-threshold", 0.8, "Similitud Jaccard mínima para agrupar (minhash)")
+	glob := fs.String("glob", "*.json", "Patrón de archivos a procesar cuando -input es una carpeta")
+	recursive := fs.Bool("recursive", false, "Buscar archivos también en subcarpetas de -input")
+	merge := fs.Bool("merge", false, "Combinar todos los archivos de la carpeta en un solo JSONL")
+	workers := fs.Int("workers", runtime.NumCPU(), "Goroutines de conversión (archivos en paralelo en modo carpeta)")
+	deterministic := fs.Bool("deterministic", false, "Salida reproducible byte a byte (sin fechas del reloj)")
+	sortBy := fs.String("sort", "input", "Orden de salida: input (orden del archivo) | title")
+	fallback := fs.String("fallback-date", "", "Fecha v3 si falta created/modified: now | omit | yyyymmddhhMMSS | RFC3339 (por defecto now; omit con -deterministic)")
+	tz := fs.String("tz", "UTC", "Zona de las fechas v2/v3: UTC | Local | nombre IANA (America/Bogota) | offset (-05:00)")
+	unpack := fs.Bool("unpack-plugins", false, "Exportar también los tiddlers de cada plugin como registros sueltos")
+	filterExpr := fs.String("filter", "", "Filtro de TiddlyWiki que selecciona los tiddlers a exportar (p.ej. '[tag[x]!is[system]]')")
+	policy := fs.String("system-policy", classify.PolicyInclude, "Tiddlers de sistema, borradores, estado y plugins: include | exclude | split")
+	chunkTokens := fs.Int("chunk-tokens", 0, "Fragmentar cada tiddler en registros de como mucho N tokens (0 = no fragmentar; requiere -mode v2)")
+	chunkOverlap := fs.Int("chunk-overlap", 0, "Tokens del fragmento anterior repetidos al principio de cada fragmento")
+	tokenizer := fs.String("tokenizer", chunk.TokenizerChars, "Estimación de tokens: chars (≈4 caracteres) | words (≈0,75 palabras)")
+	systemOut := fs.String("system-output", "", "Destino de lo que no es contenido con -system-policy split (por defecto <salida>_system.<ext>)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+	if _, ok := exportModes[*mode]; !ok {
+		return usagef("modo desconocido: %s (usa 'v1', 'v2', 'v3' o 'hybrid')", *mode)
+	}
+	if _, err := filepath.Match(*glob, ""); err != nil {
+		return usagef("patrón -glob inválido: %v", err)
+	}
+	switch *format {
+	case exporter.FormatJSONL, exporter.FormatJSON, exporter.FormatCSV:
+	case exporter.FormatParquet:
+		if *out == exporter.Stdio {
+			return usagef("el formato parquet necesita un archivo en -output")
+		}
+	default:
+		return usagef("formato desconocido: %s (usa 'jsonl', 'json', 'csv' o 'parquet')", *format)
+	}
+
+	conv, err := conversionOptions(*deterministic, *fallback, *tz)
+	if err != nil {
+		return err
+	}
+	if *sortBy != "input" && *sortBy != "title" {
+		return usagef("orden desconocido: %s (usa 'input' o 'title')", *sortBy)
+	}
+	var sel *filter.Filter
+	if *filterExpr != "" {
+		if sel, err = filter.Parse(*filterExpr); err != nil {
+			return usagef("%v", err)
+		}
+	}
+	if *policy, err = classify.ParsePolicy(*policy); err != nil {
+		return usagef("%v", err)
+	}
+	if *systemOut != "" && *policy != classify.PolicySplit {
+		return usagef("-system-output sólo tiene sentido con -system-policy split")
+	}
+	if *policy == classify.PolicySplit && *systemOut == "" && *out == exporter.Stdio {
+		return usagef("-system-policy split con salida a stdout necesita -system-output")
+	}
+	chunking := chunk.Options{MaxTokens: *chunkTokens, Overlap: *chunkOverlap, Tokenizer: *tokenizer}
+	if *chunkTokens != 0 {
+		if *mode != "v2" {
+			return usagef("los fragmentos son registros v2: -chunk-tokens necesita -mode v2")
+		}
+		if err := chunking.Validate(); err != nil {
+			return usagef("%v", err)
+		}
+	}
+
+	opts := exportOptions{
+		mode:       *mode,
+		format:     *format,
+		pretty:     *pretty,
+		workers:    *workers,
+		sortBy:     *sortBy,
+		conv:       conv,
+		near:       dedup.NearOptions{Method: *nearMethod, Threshold: *nearThreshold},
+		nearKeep:   *nearDedup,
+		nearReport: *nearReport,
+		unpack:     *unpack,
+		filter:     sel,
+		policy:     *policy,
+		systemOut:  *systemOut,
+		chunk:      chunking,
+	}
+	ctx := context.Background()
+
+	if *in != importer.Stdio {
+		fi, err := os.Stat(*in)
+		if err != nil {
+			return fmt.Errorf("no se pudo acceder a '%s': %w", *in, err)
+		}
+		if fi.IsDir() {
+			if *out == exporter.Stdio {
+				return usagef("-output - no admite una carpeta de entrada")
+			}
+			if *systemOut != "" && !*merge {
+				return usagef("-system-output con una carpeta de entrada necesita -merge")
+			}
+			return runBatch(ctx, batchOptions{
+				dir:       *in,
+				out:       *out,
+				glob:      *glob,
+				recursive: *recursive,
+				merge:     *merge,
+				workers:   *workers,
+			}, opts)
+		}
+	}
+
+	outputPath, err := resolveOutput(*in, *out, opts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "--------------------------------------------------")
+	fmt.Fprintf(os.Stderr, "🧠 Modo de exportación seleccionado: %s\n", *mode)
+	fmt.Fprintf(os.Stderr, "  - %s\n", exportModes[*mode])
+	switch {
+	case *format != exporter.FormatJSONL:
+		fmt.Fprintf(os.Stderr, "📦 Formato de salida: %s\n", *format)
+	case *pretty:
+		fmt.Fprintln(os.Stderr, "📦 Formato de salida: JSON indentado (multilínea, inspección humana)")
+	default:
+		fmt.Fprintln(os.Stderr, "📦 Formato de salida: JSONL plano (una línea por objeto, ingestión IA)")
+	}
+	fmt.Fprintf(os.Stderr, "📥 Archivo de entrada: %s\n", *in)
+	fmt.Fprintf(os.Stderr, "📤 Archivo de salida:  %s\n", outputPath)
+	fmt.Fprintln(os.Stderr, "--------------------------------------------------")
+
+	n, err := exportFile(ctx, *in, outputPath, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Exportación completada: %d registros (destino: %s)\n", n, outputPath)
+	return nil
+}
+
+// exportFile exporta un archivo de entrada.  Sin casi-duplicados, orden,
+// plugins, filtro ni política de sistema los tiddlers fluyen de a uno desde
+// importer.StreamFile hasta el RecordWriter.
+func exportFile(ctx context.Context, input, outputPath string, opts exportOptions) (int, error) {
+	if opts.wantsNear() || opts.sortBy == "title" || opts.unpack || opts.filter != nil || opts.splitsKinds() {
+		tiddlers, err := importer.Read(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("leyendo tiddlers: %w", err)
+		}
+		return exportTiddlers(ctx, tiddlers, outputPath, opts)
+	}
+	return writeRecords(ctx, outputPath, opts, func(emit func(models.Tiddler) error) error {
+		return importer.StreamFile(ctx, input, emit)
+	})
+}
+
+// exportTiddlers desempaqueta los plugins, aplica el filtro y la
+// deduplicación aproximada (si se pidieron), ordena, aparta lo que no es
+// contenido según opts.policy, convierte y escribe outputPath.
+// Devuelve la cantidad de registros escritos.
+func exportTiddlers(ctx context.Context, tiddlers []models.Tiddler, outputPath string, opts exportOptions) (int, error) {
+	if opts.unpack {
+		var n int
+		var err error
+		tiddlers, n, err = plugin.UnpackAll(tiddlers)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(os.Stderr, "🧩 %d tiddlers desempaquetados de los plugins\n", n)
+	}
+	if opts.filter != nil {
+		before := len(tiddlers)
+		tiddlers = opts.filter.Apply(tiddlers, filter.Options{})
+		fmt.Fprintf(os.Stderr, "🔍 Filtro %s: %d de %d tiddlers\n", opts.filter, len(tiddlers), before)
+	}
+	if opts.wantsNear() {
+		var err error
+		tiddlers, err = applyNearDedup(tiddlers, opts.near, opts.nearReport, opts.nearKeep)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if opts.sortBy == "title" {
+		tiddlers = sortByTitle(tiddlers)
+	}
+	if opts.splitsKinds() {
+		content, other, counts := classify.Split(tiddlers)
+		fmt.Fprintf(os.Stderr, "🗂️  Clasificación: %s\n", classify.Summary(counts))
+		if opts.policy == classify.PolicySplit {
+			path := opts.systemOut
+			if path == "" {
+				path = systemPath(outputPath)
+			}
+			n, err := writeRecords(ctx, path, opts, emitAll(ctx, other))
+			if err != nil {
+				return 0, err
+			}
+			fmt.Fprintf(os.Stderr, "🗄️  %d registros de sistema, borradores, estado y plugins en %s\n", n, path)
+		} else {
+			fmt.Fprintf(os.Stderr, "🧹 %d tiddlers que no son contenido descartados\n", len(other))
+		}
+		tiddlers = content
+	}
+	return writeRecords(ctx, outputPath, opts, emitAll(ctx, tiddlers))
+}
+
+// emitAll es la fuente de writeRecords para un slice ya cargado.
+func emitAll(ctx context.Context, ts []models.Tiddler) func(emit func(models.Tiddler) error) error {
+	return func(emit func(models.Tiddler) error) error {
+		for _, t := range ts {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := emit(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// systemPath deriva el destino de lo que no es contenido:
+// "out/wiki_v3.jsonl" → "out/wiki_v3_system.jsonl".
+func systemPath(outputPath string) string {
+	ext := filepath.Ext(outputPath)
+	return strings.TrimSuffix(outputPath, ext) + "_system" + ext
+}
+
+// writeRecords abre el RecordWriter de opts.format en outputPath, convierte
+// (con opts.workers goroutines) cada tiddler que entrega source y lo escribe
+// en el orden original.  Con opts.chunk cada tiddler da varios registros
+// (sus fragmentos).  Devuelve los registros escritos.
+func writeRecords(ctx context.Context, outputPath string, opts exportOptions, source func(emit func(models.Tiddler) error) error) (int, error) {
+	w, err := exporter.NewRecordWriter(outputPath, opts.format, opts.pretty)
+	if err != nil {
+		return 0, err
+	}
+	conv := transform.ConverterFor(opts.mode, opts.conv)
+	if opts.chunk.MaxTokens > 0 {
+		conv = func(t models.Tiddler) any { return chunk.Fragments(t, opts.chunk, opts.conv) }
+	}
+	n := 0
+	err = transform.ConvertStream(ctx, opts.workers, source, conv, func(rec any) error {
+		if fragments, ok := rec.([]models.RecordV2); ok {
+			for _, f := range fragments {
+				if err := w.Write(f); err != nil {
+					return err
+				}
+			}
+			n += len(fragments)
+			return nil
+		}
+		n++
+		return w.Write(rec)
+	})
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("escribir %s %s: %w", opts.format, opts.mode, err)
+	}
+	return n, nil
+}
+
+// conversionOptions arma las opciones de conversión a partir de los flags
+// -deterministic, -fallback-date y -tz.
+func conversionOptions(deterministic bool, fallback, tz string) (transform.Options, error) {
+	if deterministic && fallback == "" {
+		fallback = transform.FallbackOmit
+	}
+	opts, err := transform.ParseFallback(fallback)
+	if err != nil {
+		return opts, usagef("%v", err)
+	}
+	if deterministic && !opts.Reproducible() {
+		return opts, usagef("-deterministic no admite -fallback-date now")
+	}
+	if opts.Location, err = transform.ParseLocation(tz); err != nil {
+		return opts, usagef("%v", err)
+	}
+	return opts, nil
+}
+
+// sortByTitle devuelve una copia de ts ordenada (de forma estable) por título.
+func sortByTitle(ts []models.Tiddler) []models.Tiddler {
+	sorted := append([]models.Tiddler(nil), ts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Title < sorted[j].Title })
+	return sorted
+}
+
+// applyNearDedup calcula los clusters de casi-duplicados, escribe el informe
+// si reportPath no está vacío y, si keep es true, deja un representante por cluster.
+func applyNearDedup(tiddlers []models.Tiddler, opts dedup.NearOptions, reportPath string, keep bool) ([]models.Tiddler, error) {
+	report := dedup.FindNearDuplicates(tiddlers, opts)
+	fmt.Fprintf(os.Stderr, "🔎 %d clusters de casi-duplicados (%d tiddlers redundantes)\n",
+		len(report.Clusters), report.Duplicates())
+	if reportPath != "" {
+		if err := exporter.WriteJSON(reportPath, report, true); err != nil {
+			return nil, fmt.Errorf("escribiendo informe de clusters: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de clusters: %s\n", reportPath)
+	}
+	if keep {
+		tiddlers = dedup.KeepRepresentatives(tiddlers, report)
+		fmt.Fprintf(os.Stderr, "🧹 %d tiddlers tras conservar un representante por cluster\n", len(tiddlers))
+	}
+	return tiddlers, nil
+}
+
+// resolveOutput decide el archivo de salida.  Si out es una carpeta (existente
+// o una ruta sin extensión) se crea y se usa <entrada>_<modo>[_pretty].<formato>
+// dentro; si out termina en .jsonl (formato jsonl) se conserva su carpeta con
+// el mismo nombre.  "-" se devuelve tal cual (stdout); con entrada por stdin,
+// <entrada> es "stdin".
+func resolveOutput(inputPath, out string, opts exportOptions) (string, error) {
+	if out == exporter.Stdio {
+		return out, nil
+	}
+	base := filepath.Base(inputPath)
+	if inputPath == importer.Stdio {
+		base = "stdin"
+	}
+	name := base[:len(base)-len(filepath.Ext(base))]
+	prettySuffix := ""
+	if opts.pretty {
+		prettySuffix = "_pretty"
+	}
+	format := opts.format
+	if format == "" {
+		format = exporter.FormatJSONL
+	}
+	fileName := fmt.Sprintf("%s_%s%s.%s", name, opts.mode, prettySuffix, format)
+
+	fo, err := os.Stat(out)
+	switch {
+	case err == nil && fo.IsDir():
+		return filepath.Join(out, fileName), nil
+	case os.IsNotExist(err) && filepath.Ext(out) == "":
+		if mkdirErr := os.MkdirAll(out, 0o755); mkdirErr != nil {
+			return "", fmt.Errorf("no se pudo crear carpeta '%s': %w", out, mkdirErr)
+		}
+		return filepath.Join(out, fileName), nil
+	case filepath.Ext(out) == ".jsonl" && format == exporter.FormatJSONL:
+		return filepath.Join(filepath.Dir(out), fileName), nil
+	}
+	return out, nil
+}
@@ -0,0 +1,108 @@
+// internal/cli/merge.go – Subcomando `merge`
+// --------------------------------------------------------------------------------
+// Combina varios exports de TiddlyWiki (merge.Merge): cada tiddler queda
+// etiquetado con su wiki de origen en "source_wiki" y los títulos repetidos se
+// resuelven con -policy.  El nombre de cada wiki es el del archivo sin
+// extensión, o el indicado con la forma nombre=ruta.
+//
+//   openpages merge -policy prefix -report conflictos.json \
+//     -output data/out/todo.json equipoA=wikiA.json equipoB=wikiB.json
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/merge"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func runMerge(args []string) error {
+	fs := newFlagSet("merge", "[-output destino.json|-] [flags] [nombre=]wiki1.json [nombre=]wiki2.json [...]", `
+Combina varios exports de TiddlyWiki en uno solo.  Cada tiddler recibe el
+campo "source_wiki" con el nombre de su wiki (un campo "source" propio no se
+toca).  Los títulos repetidos con contenido distinto se resuelven según
+-policy:
+  newest  gana la versión con 'modified' más reciente
+  prefix  todas las versiones se renombran "<wiki>/<título>"
+  suffix  la primera conserva el título; las demás reciben " (<wiki>)"
+  fail    no se escribe salida y el comando termina con código 3`)
+	out := fs.String("output", exporter.Stdio, "Archivo de salida (\"-\" = stdout)")
+	policy := fs.String("policy", string(merge.PolicyNewest), "Política de conflictos: newest | prefix | suffix | fail")
+	report := fs.String("report", "", "Ruta del informe JSON de conflictos")
+	mode := fs.String("mode", "", "Convertir la salida a JSONL (v1 | v2 | v3 | hybrid); vacío = JSON TiddlyWiki")
+	pretty := fs.Bool("pretty", true, "Indentar el JSON de salida")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return usagef("se necesitan al menos dos archivos de entrada")
+	}
+	pol, err := merge.ParsePolicy(*policy)
+	if err != nil {
+		return usagef("%v", err)
+	}
+	if _, ok := exportModes[*mode]; *mode != "" && !ok {
+		return usagef("modo desconocido: %s (usa 'v1', 'v2', 'v3' o 'hybrid')", *mode)
+	}
+
+	ctx := context.Background()
+	var sources []merge.Source
+	for _, arg := range fs.Args() {
+		name, path := splitSource(arg)
+		tiddlers, err := importer.Read(ctx, path)
+		if err != nil {
+			return fmt.Errorf("leyendo %s: %w", path, err)
+		}
+		fmt.Fprintf(os.Stderr, "📦 %d tiddlers cargados de %s (%s)\n", len(tiddlers), path, name)
+		sources = append(sources, merge.Source{Name: name, Tiddlers: tiddlers})
+	}
+
+	merged, rep, mergeErr := merge.Merge(sources, pol)
+	if *report != "" {
+		if err := exporter.WriteJSON(*report, rep, true); err != nil {
+			return fmt.Errorf("escribiendo informe de conflictos: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de conflictos: %s\n", *report)
+	}
+	var cerr *merge.ConflictError
+	if errors.As(mergeErr, &cerr) {
+		return findingsError{msg: cerr.Error()}
+	}
+	if mergeErr != nil {
+		return mergeErr
+	}
+
+	if err := writeMerged(ctx, merged, *out, *mode, *pretty); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Combinación completada: %d tiddlers, %d títulos en conflicto (destino: %s)\n",
+		len(merged), len(rep.Conflicts), *out)
+	return nil
+}
+
+// splitSource separa "nombre=ruta"; sin nombre explícito usa la base del archivo.
+func splitSource(arg string) (name, path string) {
+	if i := strings.Index(arg, "="); i > 0 {
+		return arg[:i], arg[i+1:]
+	}
+	base := filepath.Base(arg)
+	return strings.TrimSuffix(base, filepath.Ext(base)), arg
+}
+
+// writeMerged escribe JSON TiddlyWiki o, con mode, el JSONL convertido.
+func writeMerged(ctx context.Context, tiddlers []models.Tiddler, out, mode string, pretty bool) error {
+	if mode == "" {
+		return exporter.WriteJSON(out, tiddlers, pretty)
+	}
+	_, err := exportTiddlers(ctx, tiddlers, out, exportOptions{mode: mode, format: exporter.FormatJSONL})
+	return err
+}
@@ -0,0 +1,141 @@
+// internal/cli/merge3.go – Subcomando `merge3`
+// --------------------------------------------------------------------------------
+// Fusión de tres vías (merge.ThreeWay) entre el export original (base), el
+// JSONL editado (ours) y la wiki actual (theirs).  A diferencia de
+// `revert -template`, lo editado en la wiki después del export no se pierde:
+// los cambios que no chocan se combinan solos y los textos en conflicto
+// quedan con marcadores <<<<<<< / ||||||| / ======= / >>>>>>>.
+//
+//   openpages merge3 -base export.json -ours editado.jsonl -theirs wiki_hoy.json \
+//                    -output fusionado.json -report conflictos.json
+//
+// Si hubo conflictos la salida se escribe igual y el comando termina con
+// código 3.  `revert -template wiki_hoy.json -base export.json` usa lo mismo.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/merge"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// threeWayJob son los parámetros de una fusión de tres vías.
+type threeWayJob struct {
+	base, ours, theirs string
+	output, report     string
+	schema             string // esquema de ours: auto | v1 | v2 | v3 | hybrid | tiddlywiki
+	deletions, pretty  bool
+}
+
+func runMerge3(args []string) error {
+	fs := newFlagSet("merge3", "-base export.json -ours editado.jsonl -theirs wiki.json -output fusionado.json [flags]", `
+Fusiona un JSONL editado (ours) con la wiki actual (theirs) usando como base
+el export original.  Los cambios que no chocan se combinan; los textos
+editados en ambos lados se fusionan línea a línea y, si chocan, quedan con
+marcadores de conflicto.  Con conflictos termina con código 3.`)
+	job := threeWayJob{}
+	fs.StringVar(&job.base, "base", "", "Export original de TiddlyWiki del que salió el JSONL (requerido)")
+	fs.StringVar(&job.ours, "ours", "", "JSONL editado, de cualquier modo (requerido)")
+	fs.StringVar(&job.theirs, "theirs", "", "Export actual de la wiki (requerido)")
+	fs.StringVar(&job.output, "output", "", "Archivo JSON TiddlyWiki de salida (\"-\" = stdout)")
+	fs.StringVar(&job.report, "report", "", "Ruta del informe JSON de conflictos")
+	fs.StringVar(&job.schema, "schema", transform.SchemaAuto, "Esquema de -ours: auto | v1 | v2 | v3 | hybrid | tiddlywiki")
+	fs.BoolVar(&job.deletions, "deletions", false, "Los tiddlers de la base que faltan en -ours se borran")
+	fs.BoolVar(&job.pretty, "pretty", false, "Indentar la salida")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if job.base == "" || job.ours == "" || job.theirs == "" || job.output == "" {
+		return usagef("-base, -ours, -theirs y -output son obligatorios")
+	}
+	return runThreeWay(job)
+}
+
+// runThreeWay lee las tres versiones, las fusiona y escribe salida e informe.
+func runThreeWay(job threeWayJob) error {
+	switch job.schema {
+	case transform.SchemaAuto, "v1", "v2", "v3", "hybrid", "tiddlywiki":
+	default:
+		return usagef("esquema desconocido: %s (usa 'auto', 'v1', 'v2', 'v3', 'hybrid' o 'tiddlywiki')", job.schema)
+	}
+
+	ctx := context.Background()
+	base, err := importer.Read(ctx, job.base)
+	if err != nil {
+		return fmt.Errorf("leyendo base: %w", err)
+	}
+	theirs, err := importer.Read(ctx, job.theirs)
+	if err != nil {
+		return fmt.Errorf("leyendo theirs: %w", err)
+	}
+	f, err := os.Open(job.ours)
+	if err != nil {
+		return fmt.Errorf("no se pudo abrir '%s': %w", job.ours, err)
+	}
+	ours, detected, err := transform.ReadRecords(f)
+	f.Close()
+	var skipped *transform.SkippedError
+	if errors.As(err, &skipped) {
+		// Un registro ilegible no puede fusionarse: mejor no escribir nada.
+		for _, l := range skipped.Lines {
+			fmt.Fprintf(os.Stderr, "  • %v\n", l)
+		}
+		return fmt.Errorf("leyendo ours: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("leyendo ours: %w", err)
+	}
+
+	schema := job.schema
+	if schema == transform.SchemaAuto {
+		schema = detected
+	}
+	opts := merge.ThreeWayOptions{
+		Deletions: job.deletions,
+		Now:       transform.FormatTWDate(time.Now()), // TiddlyWiki guarda UTC
+	}
+	if schema != "tiddlywiki" && schema != "" {
+		opts.OursView = func(t models.Tiddler) (models.Tiddler, error) {
+			return roundtrip.Through(t, schema, transform.Options{})
+		}
+	}
+
+	out, rep, err := merge.ThreeWay(base, ours, theirs, opts)
+	if err != nil {
+		return err
+	}
+	if err := exporter.WriteJSON(job.output, out, job.pretty); err != nil {
+		return fmt.Errorf("escribiendo resultado: %w", err)
+	}
+	if job.report != "" {
+		if err := exporter.WriteJSON(job.report, rep, true); err != nil {
+			return fmt.Errorf("escribiendo informe: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de conflictos: %s\n", job.report)
+	}
+	fmt.Fprintf(os.Stderr, "🔀 Fusión de tres vías (ours: %s): %d tiddlers, %d actualizados, %d nuevos, %d borrados\n",
+		schema, rep.Total, rep.Updated, rep.Added, rep.Deleted)
+	if len(rep.Conflicts) > 0 {
+		for _, c := range rep.Conflicts {
+			field := c.Field
+			if field == "" {
+				field = "(tiddler)"
+			}
+			fmt.Fprintf(os.Stderr, "  ⚠️  %q %s: conflicto %s → %s\n", c.Title, field, c.Kind, c.Resolution)
+		}
+		return findingsError{msg: fmt.Sprintf("%d conflictos (destino: %s)", len(rep.Conflicts), job.output)}
+	}
+	fmt.Fprintf(os.Stderr, "✅ Fusión completada sin conflictos (destino: %s)\n", job.output)
+	return nil
+}
@@ -0,0 +1,104 @@
+// internal/cli/migrate.go – Subcomando `migrate`
+// --------------------------------------------------------------------------------
+// Lleva un JSONL de una versión de esquema a otra con migrate.Stream, sin
+// volver a exportar desde la wiki.  La versión de origen se lee de
+// schema_version o se deduce de las claves de cada línea; -from la fija.
+// Las líneas que no se pueden migrar se listan en stderr y el comando termina
+// con código 3; los campos que el destino no guarda se avisan por campo.
+//
+//   openpages migrate -input viejo_v1.jsonl -output nuevo_v3.jsonl -to v3
+//   openpages migrate -list
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/migrate"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+func runMigrate(args []string) error {
+	fs := newFlagSet("migrate", "-to v1|v2|v3|hybrid [-from auto|v1|v2|v3|hybrid] [-input entrada.jsonl|-] [-output salida.jsonl|-] | -list", `
+Convierte un JSONL exportado con cualquier modo a otra versión de esquema
+(hacia arriba o hacia abajo) y escribe schema_version en cada registro.  Los
+registros que ya están en la versión de destino pasan intactos.  Las líneas
+que no se pueden migrar se informan en stderr y el comando termina con
+código 3.`)
+	in := fs.String("input", "", "Archivo JSONL de entrada (\"-\" = stdin)")
+	out := fs.String("output", "", "Archivo JSONL de salida (\"-\" = stdout, por defecto)")
+	to := fs.String("to", "", "Versión de destino: "+strings.Join(transform.SchemaVersions(), " | "))
+	from := fs.String("from", transform.SchemaAuto, "Versión de origen: auto (schema_version o claves de cada línea) | "+strings.Join(transform.SchemaVersions(), " | "))
+	list := fs.Bool("list", false, "Muestra el registro de esquemas y termina")
+	deterministic := fs.Bool("deterministic", false, "Salida reproducible byte a byte (sin fechas del reloj)")
+	fallback := fs.String("fallback-date", "", "Fecha si falta created/modified: now | omit | yyyymmddhhMMSS | RFC3339")
+	tz := fs.String("tz", "UTC", "Zona de las fechas: UTC | Local | nombre IANA | offset (-05:00)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *list {
+		for _, s := range transform.Schemas {
+			fmt.Printf("%-7s %-16s %s\n", s.Version, s.GoType, s.Description)
+		}
+		return nil
+	}
+	if *to == "" {
+		return usagef("falta -to (usa %s)", strings.Join(transform.SchemaVersions(), ", "))
+	}
+	conv, err := conversionOptions(*deterministic, *fallback, *tz)
+	if err != nil {
+		return err
+	}
+	opts := migrate.Options{From: *from, To: *to, Conv: conv}
+	if err := opts.Validate(); err != nil {
+		return usagef("%v", err)
+	}
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != importer.Stdio {
+		f, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("no se pudo abrir '%s': %w", *in, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	var w io.Writer = os.Stdout
+	if *out != exporter.Stdio {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("no se pudo crear '%s': %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	rep, err := migrate.Stream(r, w, opts)
+	for _, field := range rep.LostFields() {
+		fmt.Fprintf(os.Stderr, "⚠️  %s no conserva %s en %d registros\n", *to, field, rep.Lost[field])
+	}
+	var skipped *transform.SkippedError
+	if errors.As(err, &skipped) {
+		for _, l := range skipped.Lines {
+			fmt.Fprintf(os.Stderr, "  • %v\n", l)
+		}
+		return findingsError{msg: fmt.Sprintf("%d registros migrados a %s; %d de %d no se pudieron migrar",
+			rep.Records, *to, len(skipped.Lines), skipped.Total)}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Migración completada: %d registros a %s (%d convertidos, %d ya estaban en %s; destino: %s)\n",
+		rep.Records, *to, rep.Migrated, rep.Records-rep.Migrated, *to, *out)
+	return nil
+}
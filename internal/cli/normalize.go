@@ -0,0 +1,79 @@
+// internal/cli/normalize.go – Subcomando `normalize`
+// --------------------------------------------------------------------------------
+// Lleva cualquier entrada (array o mapa de TiddlyWiki, JSONL de cualquier modo)
+// a JSONL v3 con transform.NormalizeToV3.  Los registros ilegibles se listan
+// en stderr con su línea y el comando termina con código 3; el resto se
+// escribe igual.
+//
+//   openpages normalize -input mezcla.jsonl -output data/out/v3.jsonl
+//   cat wiki.json | openpages normalize | jq .title
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+func runNormalize(args []string) error {
+	fs := newFlagSet("normalize", "[-input entrada|-] [-output salida.jsonl|-] [flags]", `
+Convierte a JSONL v3 un export de TiddlyWiki (array u objeto) o un JSONL de
+cualquier modo.  Las líneas que no se pueden interpretar se informan en stderr
+y el comando termina con código 3.`)
+	in := fs.String("input", "", "Archivo de entrada (\"-\" = stdin)")
+	out := fs.String("output", "", "Archivo JSONL de salida (\"-\" = stdout, por defecto)")
+	deterministic := fs.Bool("deterministic", false, "Salida reproducible byte a byte (sin fechas del reloj)")
+	fallback := fs.String("fallback-date", "", "Fecha si falta created/modified: now | omit | yyyymmddhhMMSS | RFC3339")
+	tz := fs.String("tz", "UTC", "Zona de las fechas: UTC | Local | nombre IANA | offset (-05:00)")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+	opts, err := conversionOptions(*deterministic, *fallback, *tz)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != importer.Stdio {
+		f, err := os.Open(*in)
+		if err != nil {
+			return fmt.Errorf("no se pudo abrir '%s': %w", *in, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	var w io.Writer = os.Stdout
+	if *out != exporter.Stdio {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("no se pudo crear '%s': %w", *out, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := transform.NormalizeToV3(r, w, opts)
+	var skipped *transform.SkippedError
+	if errors.As(err, &skipped) {
+		for _, l := range skipped.Lines {
+			fmt.Fprintf(os.Stderr, "  • %v\n", l)
+		}
+		return findingsError{msg: fmt.Sprintf("%d registros escritos; %d de %d no se pudieron interpretar",
+			n, len(skipped.Lines), skipped.Total)}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Normalización completada: %d registros v3 (destino: %s)\n", n, *out)
+	return nil
+}
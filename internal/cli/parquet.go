@@ -0,0 +1,89 @@
+// internal/cli/parquet.go – Subcomando `parquet`
+// --------------------------------------------------------------------------------
+// Convierte un JSONL a Parquet con exporter.ConvertJSONLToParquet.  Sin -input,
+// conserva el flujo interactivo histórico: lista los .jsonl de -dir y pide
+// elegir uno por número.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+)
+
+func runParquet(args []string) error {
+	fs := newFlagSet("parquet", "[-input archivo.jsonl] [-output archivo.parquet] [-dir carpeta]", `
+Convierte un archivo JSONL a Parquet.  Si no se indica -input, muestra los
+.jsonl de -dir y permite elegir uno de forma interactiva.  Sin -output, el
+archivo Parquet se escribe junto al JSONL con la misma base.`)
+	in := fs.String("input", "", "Archivo .jsonl a convertir")
+	out := fs.String("output", "", "Archivo .parquet de salida")
+	dir := fs.String("dir", "data/out", "Carpeta donde buscar .jsonl en modo interactivo")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	inputPath := *in
+	if inputPath == "" {
+		fmt.Fprintln(os.Stderr, "--------------------------------------------------")
+		fmt.Fprintln(os.Stderr, "📦 Modo: Exportación interactiva de JSONL a Parquet")
+		selected, err := chooseJSONL(*dir)
+		if err != nil {
+			return err
+		}
+		inputPath = selected
+	}
+	outputPath := *out
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(inputPath, ".jsonl") + ".parquet"
+	}
+
+	fmt.Fprintf(os.Stderr, "Convirtiendo %s → %s ...\n", inputPath, outputPath)
+	if err := exporter.ConvertJSONLToParquet(inputPath, outputPath); err != nil {
+		return fmt.Errorf("exportando a Parquet: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Conversión a Parquet completada: %s\n", outputPath)
+	return nil
+}
+
+// chooseJSONL lista los .jsonl de dir y lee de stdin el número elegido.
+func chooseJSONL(dir string) (string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer el directorio %s: %w", dir, err)
+	}
+	var jsonlFiles []string
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".jsonl") {
+			jsonlFiles = append(jsonlFiles, f.Name())
+		}
+	}
+	if len(jsonlFiles) == 0 {
+		return "", fmt.Errorf("no se encontraron archivos .jsonl en %s", dir)
+	}
+
+	fmt.Fprintln(os.Stderr, "Seleccione el archivo .jsonl a convertir a .parquet:")
+	for i, name := range jsonlFiles {
+		fmt.Fprintf(os.Stderr, "  [%d] %s\n", i+1, name)
+	}
+	fmt.Fprint(os.Stderr, "Ingrese el número de archivo: ")
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		input, err := reader.ReadString('\n')
+		var idx int
+		fmt.Sscanf(strings.TrimSpace(input), "%d", &idx)
+		if idx >= 1 && idx <= len(jsonlFiles) {
+			return filepath.Join(dir, jsonlFiles[idx-1]), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("selección interrumpida: %w", err)
+		}
+		fmt.Fprint(os.Stderr, "Opción inválida. Intente de nuevo: ")
+	}
+}
@@ -0,0 +1,130 @@
+// internal/cli/plugin.go – Subcomando `plugin`
+// --------------------------------------------------------------------------------
+// Empaqueta un conjunto de tiddlers como plugin de TiddlyWiki para repartirlo
+// a otros equipos, o desempaqueta los plugins de una wiki en tiddlers sueltos
+// (ver internal/plugin).
+//
+//   openpages plugin -input wiki.json -title '$:/plugins/equipo/glosario' \
+//     -tag Glosario -version 1.2.0 -description "Glosario común" -output glosario.json
+//   openpages plugin -unpack -input wiki_con_plugins.json -output sueltos.json
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func runPlugin(args []string) error {
+	fs := newFlagSet("plugin", "[-input wiki.json|-] [-output plugin.json|-] (-title $:/plugins/autor/nombre [selección] | -unpack) [flags]", `
+Empaqueta tiddlers en un único tiddler plugin de TiddlyWiki (application/json
+con el mapa "tiddlers", plugin-type, version, description y dependents).  Se
+incluyen los tiddlers que cumplan -tag, -titles o -prefix (cualquiera de
+ellos); sin selección, todos los de la entrada salvo otros plugins.
+
+Con -unpack hace lo inverso: la salida es la entrada con los tiddlers de cada
+plugin añadidos como tiddlers sueltos (campo "plugin" = su paquete), para
+poder exportarlos y buscarlos.  -input puede ser un export de TiddlyWiki o
+un JSONL de cualquier modo.`)
+	in := fs.String("input", "", "Wiki de origen (\"-\" = stdin)")
+	out := fs.String("output", "", "Archivo JSON de salida (\"-\" = stdout, por defecto)")
+	title := fs.String("title", "", "Título del plugin: $:/plugins/<autor>/<nombre>")
+	name := fs.String("name", "", "Nombre visible del plugin (por defecto, el final del título)")
+	version := fs.String("version", "", "Versión del plugin (p.ej. 1.0.0)")
+	description := fs.String("description", "", "Descripción del plugin")
+	pluginType := fs.String("plugin-type", plugin.DefaultType, "plugin-type: plugin | theme | language…")
+	dependents := fs.String("dependents", "", "Plugins que deben instalarse junto a éste (lista TiddlyWiki)")
+	tag := fs.String("tag", "", "Incluir los tiddlers con esta etiqueta (y el tiddler de la etiqueta)")
+	titles := fs.String("titles", "", "Incluir estos títulos (lista TiddlyWiki: A [[B C]])")
+	prefix := fs.String("prefix", "", "Incluir los títulos que empiezan así")
+	unpack := fs.Bool("unpack", false, "Desempaquetar los plugins de la entrada en tiddlers sueltos")
+	pretty := fs.Bool("pretty", true, "Indentar el JSON de salida")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+	if *unpack && *title != "" {
+		return usagef("-unpack y -title son excluyentes")
+	}
+	if !*unpack && *title == "" {
+		return usagef("falta -title (o -unpack)")
+	}
+
+	ts, err := readAnyShape(*in)
+	if err != nil {
+		return err
+	}
+
+	if *unpack {
+		all, n, err := plugin.UnpackAll(ts)
+		if err != nil {
+			return err
+		}
+		if err := exporter.WriteJSON(*out, all, *pretty); err != nil {
+			return fmt.Errorf("escribiendo tiddlers: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ %d tiddlers desempaquetados de los plugins (destino: %s)\n", n, *out)
+		return nil
+	}
+
+	selected := selectForPlugin(ts, *tag, models.ParseList(*titles), *prefix)
+	if len(selected) == 0 {
+		return fmt.Errorf("la selección no incluye ningún tiddler")
+	}
+	p, err := plugin.Pack(selected, plugin.Info{
+		Title:       *title,
+		Name:        *name,
+		Description: *description,
+		Version:     *version,
+		Type:        *pluginType,
+		Dependents:  models.ParseList(*dependents),
+	})
+	if err != nil {
+		return err
+	}
+	if err := exporter.WriteJSON(*out, []models.Tiddler{p}, *pretty); err != nil {
+		return fmt.Errorf("escribiendo plugin: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Plugin %s: %d tiddlers (destino: %s)\n", *title, len(selected), *out)
+	return nil
+}
+
+// selectForPlugin devuelve, en orden de entrada, los tiddlers que cumplen
+// alguno de los criterios; sin criterios, todos salvo los plugins.
+func selectForPlugin(ts []models.Tiddler, tag string, titles []string, prefix string) []models.Tiddler {
+	wanted := make(map[string]bool, len(titles))
+	for _, t := range titles {
+		wanted[t] = true
+	}
+	all := tag == "" && len(titles) == 0 && prefix == ""
+
+	var out []models.Tiddler
+	for _, t := range ts {
+		if plugin.IsPlugin(t) {
+			continue
+		}
+		keep := all || wanted[t.Title] || (prefix != "" && strings.HasPrefix(t.Title, prefix)) ||
+			(tag != "" && (t.Title == tag || hasTag(t, tag)))
+		if keep {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func hasTag(t models.Tiddler, tag string) bool {
+	for _, tt := range t.TagsAsSlice() {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+// internal/cli/revert.go – Subcomando `revert`
+// --------------------------------------------------------------------------------
+// Tres caminos de reversión, elegidos según los flags:
+//
+//   1. -root-title     → exporter.RevertToSingleTiddler (sólo el tiddler raíz).
+//   2. -template       → aplica -input (JSONL de cualquier modo o JSON) sobre
+//                        la plantilla:
+//                          · con -base → fusión de tres vías (ver merge3.go)
+//                          · si no     → transform.UpdateFields con los campos
+//                                        de -fields y la regla -bump
+//   3. (por defecto)   → transform.ReverseJSONLToTiddlyJSONAs (JSONL de
+//                        cualquier modo → JSON TW; -schema fija el esquema).
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+func runRevert(args []string) error {
+	fs := newFlagSet("revert", "-input archivo.jsonl -output destino.json [-schema auto|v1|v2|v3|hybrid] [-template plantilla.json [-base export.json] | -root-title título]", `
+Revierte un JSONL enriquecido (de cualquier modo: el esquema se detecta por
+línea, o se fija con -schema) a JSON de TiddlyWiki.  Sin -template ni
+-root-title, "-" (o la omisión de -input/-output) significa stdin/stdout.
+Con -template, se aplican sobre la plantilla los campos de -fields (por
+defecto sólo el texto) y 'modified' cambia según -bump; el resto de la
+plantilla queda intacto.  Si además se indica -base (el export del que
+salió -input), se fusiona de tres vías y no se pierde lo editado en la wiki
+después del export.
+Con -root-title, -input es un array JSON de TiddlyWiki y se exporta sólo el
+tiddler raíz como objeto único (para el proyecto completo, ver "subtree").`)
+	in := fs.String("input", "", "JSONL (o JSON con -root-title) a revertir (requerido)")
+	out := fs.String("output", "", "Archivo JSON TiddlyWiki de salida (requerido)")
+	template := fs.String("template", "", "Plantilla JSON TiddlyWiki sobre la que aplicar los cambios")
+	rootTitle := fs.String("root-title", "", "Título del tiddler raíz a exportar como objeto único")
+	pretty := fs.Bool("pretty", false, "Indentar la salida al actualizar una plantilla JSON")
+	fields := fs.String("fields", "text", "Campos a aplicar con -template: text, markdown, type, color, path, tmap.id, source_wiki, tags, tags_list, relations, fields.<nombre>, fields.*")
+	bump := fs.String("bump", "text,markdown", "Campos cuyo cambio actualiza 'modified': lista, all o none")
+	report := fs.String("report", "", "Ruta del informe JSON de cambios aplicados (con -template)")
+	base := fs.String("base", "", "Export original (con -template): fusión de tres vías en lugar de sobrescribir")
+	schema := fs.String("schema", transform.SchemaAuto, "Esquema del JSONL: auto | v1 | v2 | v3 | hybrid")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *template != "" && *rootTitle != "" {
+		return usagef("-template y -root-title son excluyentes")
+	}
+	if *base != "" && *template == "" {
+		return usagef("-base requiere -template (la wiki actual)")
+	}
+	if *template == "" && *rootTitle == "" {
+		if err := defaultStdio(in, out); err != nil {
+			return err
+		}
+	}
+	if *in == "" || *out == "" {
+		return usagef("-input y -output son obligatorios")
+	}
+	switch *schema {
+	case transform.SchemaAuto, "v1", "v2", "v3", "hybrid":
+	default:
+		return usagef("esquema desconocido: %s (usa 'auto', 'v1', 'v2', 'v3' o 'hybrid')", *schema)
+	}
+
+	ctx := context.Background()
+
+	switch {
+	case *rootTitle != "":
+		if err := exporter.RevertToSingleTiddler(ctx, *in, *out, *rootTitle); err != nil {
+			return fmt.Errorf("reversa objeto único: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Reversión objeto único completada (destino: %s)\n", *out)
+
+	case *base != "":
+		return runThreeWay(threeWayJob{base: *base, ours: *in, theirs: *template,
+			output: *out, schema: *schema, pretty: *pretty})
+
+	case *template != "":
+		return updateTemplate(ctx, *template, *in, *out, *schema, *fields, *bump, *report, *pretty)
+
+	default:
+		if err := transform.ReverseJSONLToTiddlyJSONAs(*in, *out, *schema); err != nil {
+			return fmt.Errorf("reversa: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ Reversión completada (destino: %s)\n", *out)
+	}
+	return nil
+}
+
+// updateTemplate aplica los campos elegidos de input (JSONL de cualquier modo
+// o JSON TiddlyWiki) sobre la plantilla (ver transform.UpdateFields).
+func updateTemplate(ctx context.Context, template, input, output, schema, fieldList, bumpList, report string, pretty bool) error {
+	fields, err := transform.ParseFieldList(fieldList)
+	if err != nil {
+		return usagef("-fields: %v", err)
+	}
+	bump, err := transform.ParseBump(bumpList, fields)
+	if err != nil {
+		return usagef("-bump: %v", err)
+	}
+
+	tmpl, err := importer.Read(ctx, template)
+	if err != nil {
+		return fmt.Errorf("leyendo plantilla: %w", err)
+	}
+	f, err := os.Open(input)
+	if err != nil {
+		return fmt.Errorf("no se pudo abrir '%s': %w", input, err)
+	}
+	updates, detected, err := transform.ReadRecords(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("leyendo actualizaciones: %w", err)
+	}
+	if schema == transform.SchemaAuto {
+		schema = detected
+	}
+
+	result, rep, err := transform.UpdateFields(tmpl, updates,
+		transform.UpdateSpec{Fields: fields, Bump: bump, Schema: schema})
+	if err != nil {
+		return usagef("%v", err)
+	}
+	if err := exporter.WriteJSON(output, result, pretty); err != nil {
+		return fmt.Errorf("escribiendo resultado: %w", err)
+	}
+	if report != "" {
+		if err := exporter.WriteJSON(report, rep, true); err != nil {
+			return fmt.Errorf("escribiendo informe: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de cambios: %s\n", report)
+	}
+	for _, c := range rep.Changes {
+		mark := ""
+		if c.Bump {
+			mark = " (modified)"
+		}
+		fmt.Fprintf(os.Stderr, "  • %q %s: %s → %s%s\n", c.Title, c.Field, show(c.Old), show(c.New), mark)
+	}
+	if len(rep.Missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️  %d títulos no están en la plantilla: %s\n", len(rep.Missing), strings.Join(rep.Missing, ", "))
+	}
+	fmt.Fprintf(os.Stderr, "✅ Actualización completada: %d tiddlers actualizados, %d con 'modified' nuevo (destino: %s)\n",
+		rep.Updated, rep.Bumped, output)
+	return nil
+}
@@ -0,0 +1,104 @@
+// internal/cli/roundtrip.go – Subcomando `roundtrip`
+// --------------------------------------------------------------------------------
+// Comprueba la promesa de ida y vuelta TiddlyWiki JSON → JSONL → TiddlyWiki
+// JSON (ver roundtrip.Check): exporta con cada modo, revierte y compara con el
+// original tiddler por tiddler y campo por campo.
+//
+//   openpages roundtrip wiki.json
+//   openpages roundtrip -modes v3 -strict -report perdidas.json wiki.json
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+func runRoundtrip(args []string) error {
+	fs := newFlagSet("roundtrip", "[-modes v1,v2,v3,hybrid] [-details] [-strict] [-report informe.json] archivo.json", `
+Exporta el archivo con cada modo, lo revierte y lo compara con el original.
+Informa, por modo, cuántos tiddlers vuelven intactos y qué campos se pierden
+(los personalizados como fields.<nombre>).  Con -strict termina con código 3
+si algún modo pierde datos.`)
+	modes := fs.String("modes", strings.Join(transform.Modes, ","), "Modos a verificar, separados por comas")
+	details := fs.Bool("details", false, "Listar cada diferencia (tiddler, campo, original → restaurado)")
+	strict := fs.Bool("strict", false, "Terminar con código 3 si algún modo pierde datos")
+	report := fs.String("report", "", "Ruta del informe JSON con todas las diferencias")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("se necesita exactamente un archivo")
+	}
+	var selected []string
+	for _, m := range strings.Split(*modes, ",") {
+		m = strings.TrimSpace(m)
+		if _, ok := exportModes[m]; !ok {
+			return usagef("modo desconocido: %s (usa 'v1', 'v2', 'v3' o 'hybrid')", m)
+		}
+		selected = append(selected, m)
+	}
+
+	tiddlers, err := importer.Read(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	rep, err := roundtrip.Check(tiddlers, selected, transform.Options{})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔁 Ida y vuelta de %s (%d tiddlers)\n", fs.Arg(0), len(tiddlers))
+	lossy := 0
+	for _, m := range rep.Modes {
+		if m.Lossless() {
+			fmt.Printf("  %-7s %d/%d intactos  ✅ sin pérdidas\n", m.Mode, m.Intact, m.Tiddlers)
+			continue
+		}
+		lossy++
+		var lost []string
+		for _, f := range m.LostFields() {
+			lost = append(lost, fmt.Sprintf("%s (%d)", f, m.Lost[f]))
+		}
+		fmt.Printf("  %-7s %d/%d intactos  pierde: %s\n", m.Mode, m.Intact, m.Tiddlers, strings.Join(lost, ", "))
+		if *details {
+			for _, l := range m.Losses {
+				fmt.Printf("      • %q %s: %s → %s\n", l.Title, l.Field, show(l.Original), show(l.Restored))
+			}
+		}
+	}
+
+	if *report != "" {
+		if err := exporter.WriteJSON(*report, rep, true); err != nil {
+			return fmt.Errorf("escribiendo informe: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de ida y vuelta: %s\n", *report)
+	}
+	if *strict && lossy > 0 {
+		return findingsError{msg: fmt.Sprintf("%d de %d modos pierden datos", lossy, len(rep.Modes))}
+	}
+	return nil
+}
+
+// show resume un valor para el listado de -details.
+func show(v any) string {
+	if v == nil {
+		return "∅"
+	}
+	s := fmt.Sprintf("%v", v)
+	if str, ok := v.(string); ok {
+		s = fmt.Sprintf("%q", str)
+	}
+	if r := []rune(s); len(r) > 60 {
+		s = string(r[:57]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,292 @@
+// internal/cli/run.go – Subcomando `run`
+// --------------------------------------------------------------------------------
+// Ejecuta el pipeline declarado en openpages.yaml / openpages.toml:
+//
+//   1. Lee todas las entradas (importer.Read).  Si hay varias, las combina con
+//      merge.Merge: cada tiddler se etiqueta con su wiki de origen y los
+//      títulos repetidos se resuelven con merge.policy.
+//   2. Aplica los filtros (sistema, etiquetas, prefijo de título y la
+//      expresión de TiddlyWiki de filters.expression).
+//   3. Deduplica (hashes persistentes en dedup.state y, si se pide, casi-duplicados).
+//   4. Escribe cada salida con su modo y formato (jsonl | json | csv | parquet)
+//      mediante exporter.RecordWriter; con system_policy exclude o split lo
+//      que no es contenido se descarta o va a <salida>_system.<ext>.
+//
+// Los flags tienen prioridad sobre el archivo: p.ej. `-mode v2` cambia el modo
+// de todas las salidas convertidas y `-input` reemplaza la lista de entradas.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/chunk"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/config"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/filter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/merge"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// stringList es un flag repetible (-input a.json -input b.json).
+type stringList []string
+
+func (s *stringList) String() string     { return strings.Join(*s, ",") }
+func (s *stringList) Set(v string) error { *s = append(*s, v); return nil }
+
+func runRun(args []string) error {
+	fs := newFlagSet("run", "[-config openpages.yaml] [flags]", `
+Ejecuta todas las entradas, filtros, deduplicación y salidas declaradas en el
+archivo de proyecto.  Sin -config se busca openpages.yaml, openpages.yml u
+openpages.toml en la carpeta actual.  Los flags indicados reemplazan los
+valores del archivo.`)
+	cfgPath := fs.String("config", "", "Archivo de configuración (.yaml, .yml o .toml)")
+	var inputs stringList
+	fs.Var(&inputs, "input", "Entrada a procesar; reemplaza 'inputs' (repetible)")
+	mode := fs.String("mode", "", "Modo de todas las salidas convertidas: v1 | v2 | v3 | hybrid")
+	pretty := fs.Bool("pretty", false, "Indentar todas las salidas")
+	state := fs.String("state", "", "Archivo de hashes ya vistos; reemplaza 'dedup.state'")
+	near := fs.Bool("near", false, "Eliminar casi-duplicados; reemplaza 'dedup.near'")
+	workers := fs.Int("workers", 0, "Goroutines de conversión; reemplaza 'workers'")
+	deterministic := fs.Bool("deterministic", false, "Salida reproducible; reemplaza 'deterministic'")
+	sortBy := fs.String("sort", "", "Orden de salida (input | title); reemplaza 'sort'")
+	tz := fs.String("tz", "", "Zona de las fechas v2/v3; reemplaza 'timezone'")
+	filterExpr := fs.String("filter", "", "Filtro de TiddlyWiki; reemplaza 'filters.expression'")
+	policy := fs.String("system-policy", "", "include | exclude | split; reemplaza 'system_policy'")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	path := *cfgPath
+	if path == "" {
+		found, err := config.Find(".")
+		if err != nil {
+			return err
+		}
+		if found == "" {
+			return usagef("no se encontró %s en la carpeta actual; use -config", strings.Join(config.DefaultNames, ", "))
+		}
+		path = found
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	// Flags explícitos > archivo de configuración
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "input":
+			cfg.Inputs = cfg.Inputs[:0]
+			for _, p := range inputs {
+				cfg.Inputs = append(cfg.Inputs, config.Input{Path: p})
+			}
+		case "mode":
+			for i := range cfg.Outputs {
+				if cfg.Outputs[i].Mode != "tiddlywiki" {
+					cfg.Outputs[i].Mode = *mode
+				}
+			}
+		case "pretty":
+			for i := range cfg.Outputs {
+				cfg.Outputs[i].Pretty = *pretty
+			}
+		case "state":
+			cfg.Dedup.State = *state
+		case "near":
+			cfg.Dedup.Near = *near
+		case "workers":
+			cfg.Workers = *workers
+		case "deterministic":
+			cfg.Deterministic = *deterministic
+		case "sort":
+			cfg.Sort = *sortBy
+		case "tz":
+			cfg.Timezone = *tz
+		case "filter":
+			cfg.Filters.Expression = *filterExpr
+		case "system-policy":
+			cfg.SystemPolicy = *policy
+		}
+	})
+	if err := cfg.Validate(); err != nil {
+		return usagef("%v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "⚙️  Configuración: %s\n", path)
+	return runPipeline(context.Background(), cfg)
+}
+
+// runPipeline ejecuta una configuración ya validada.
+func runPipeline(ctx context.Context, cfg *config.Config) error {
+	var sources []merge.Source
+	for _, in := range cfg.Inputs {
+		ts, err := importer.Read(ctx, in.Path)
+		if err != nil {
+			return fmt.Errorf("leyendo %s: %w", in.Path, err)
+		}
+		fmt.Fprintf(os.Stderr, "📦 %d tiddlers cargados de %s\n", len(ts), in.Path)
+		if cfg.UnpackPlugins {
+			var n int
+			if ts, n, err = plugin.UnpackAll(ts); err != nil {
+				return fmt.Errorf("%s: %w", in.Path, err)
+			}
+			fmt.Fprintf(os.Stderr, "🧩 %d tiddlers desempaquetados de los plugins de %s\n", n, in.Path)
+		}
+		name := in.Name
+		if name == "" {
+			name, _ = splitSource(in.Path)
+		}
+		sources = append(sources, merge.Source{Name: name, Tiddlers: ts})
+	}
+
+	tiddlers, err := mergeSources(sources, cfg.Merge)
+	if err != nil {
+		return err
+	}
+
+	before := len(tiddlers)
+	if tiddlers, err = applyFilters(tiddlers, cfg.Filters); err != nil {
+		return err
+	}
+	if before != len(tiddlers) {
+		fmt.Fprintf(os.Stderr, "🔍 %d tiddlers descartados por los filtros\n", before-len(tiddlers))
+	}
+
+	var store dedup.Store
+	var fresh []string
+	if cfg.Dedup.State != "" {
+		fstore, err := dedup.NewFileStore(cfg.Dedup.State)
+		if err != nil {
+			return fmt.Errorf("abriendo estado %s: %w", cfg.Dedup.State, err)
+		}
+		store = fstore
+		defer func() {
+			if store != nil {
+				store.Close() // alguna salida falló: no se marca ningún hash
+			}
+		}()
+		var unique []models.Tiddler
+		unique, fresh = dropSeen(tiddlers, store)
+		fmt.Fprintf(os.Stderr, "🧹 %d tiddlers ya procesados en corridas anteriores\n", len(tiddlers)-len(unique))
+		tiddlers = unique
+	}
+	if cfg.Dedup.Near || cfg.Dedup.Report != "" {
+		var err error
+		tiddlers, err = applyNearDedup(tiddlers, dedup.NearOptions{
+			Method:    cfg.Dedup.Method,
+			Threshold: cfg.Dedup.Threshold,
+		}, cfg.Dedup.Report, cfg.Dedup.Near)
+		if err != nil {
+			return err
+		}
+	}
+
+	conv, err := conversionOptions(cfg.Deterministic, cfg.FallbackDate, cfg.Timezone)
+	if err != nil {
+		return err
+	}
+	base := exportOptions{workers: cfg.Workers, sortBy: cfg.Sort, conv: conv, policy: cfg.SystemPolicy, chunk: cfg.Chunk.Options()}
+	for _, out := range cfg.Outputs {
+		if err := writeOutput(ctx, tiddlers, out, base); err != nil {
+			return fmt.Errorf("salida %s: %w", out.Path, err)
+		}
+		fmt.Fprintf(os.Stderr, "✅ %s (%s, %s)\n", out.Path, out.Mode, out.Format)
+	}
+	if store != nil {
+		// Los hashes se marcan sólo cuando todas las salidas se escribieron.
+		err = markSeen(store, fresh)
+		store = nil
+		return err
+	}
+	return nil
+}
+
+// mergeSources combina las entradas según cfg.  Con una sola entrada no hay
+// nada que combinar y los tiddlers pasan sin etiqueta de procedencia.
+func mergeSources(sources []merge.Source, cfg config.Merge) ([]models.Tiddler, error) {
+	if len(sources) == 1 {
+		return sources[0].Tiddlers, nil
+	}
+	policy, err := merge.ParsePolicy(cfg.Policy)
+	if err != nil {
+		return nil, err
+	}
+	tiddlers, rep, mergeErr := merge.Merge(sources, policy)
+	if cfg.Report != "" {
+		if err := exporter.WriteJSON(cfg.Report, rep, true); err != nil {
+			return nil, fmt.Errorf("escribiendo informe de conflictos: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe de conflictos: %s\n", cfg.Report)
+	}
+	var cerr *merge.ConflictError
+	if errors.As(mergeErr, &cerr) {
+		return nil, findingsError{msg: cerr.Error()}
+	}
+	if mergeErr != nil {
+		return nil, mergeErr
+	}
+	fmt.Fprintf(os.Stderr, "🔀 %d wikis combinadas: %d tiddlers, %d títulos en conflicto (política %s)\n",
+		len(sources), len(tiddlers), len(rep.Conflicts), policy)
+	return tiddlers, nil
+}
+
+// applyFilters conserva los tiddlers que cumplen todos los criterios de f y,
+// por último, los que selecciona f.Expression (en el orden que ésta dé).
+func applyFilters(ts []models.Tiddler, f config.Filters) ([]models.Tiddler, error) {
+	hasAny := func(tags []string, want []string) bool {
+		for _, w := range want {
+			for _, tag := range tags {
+				if tag == w {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	out := make([]models.Tiddler, 0, len(ts))
+	for _, t := range ts {
+		if f.ExcludeSystem && strings.HasPrefix(t.Title, "$:/") {
+			continue
+		}
+		if f.TitlePrefix != "" && !strings.HasPrefix(t.Title, f.TitlePrefix) {
+			continue
+		}
+		tags := t.TagsAsSlice()
+		if len(f.IncludeTags) > 0 && !hasAny(tags, f.IncludeTags) {
+			continue
+		}
+		if hasAny(tags, f.ExcludeTags) {
+			continue
+		}
+		out = append(out, t)
+	}
+	if f.Expression != "" {
+		expr, err := filter.Parse(f.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("filters.expression: %w", err)
+		}
+		out = expr.Apply(out, filter.Options{})
+	}
+	return out, nil
+}
+
+// writeOutput escribe tiddlers según el modo y formato de out; opts aporta
+// los ajustes comunes a todas las salidas (workers, orden, fechas).
+func writeOutput(ctx context.Context, tiddlers []models.Tiddler, out config.Output, opts exportOptions) error {
+	opts.mode, opts.format, opts.pretty = out.Mode, out.Format, out.Pretty
+	if out.Mode != "v2" {
+		opts.chunk = chunk.Options{} // los fragmentos sólo existen en v2
+	}
+	_, err := exportTiddlers(ctx, tiddlers, out.Path, opts)
+	return err
+}
@@ -0,0 +1,103 @@
+// internal/cli/stats.go – Subcomando `stats`
+// --------------------------------------------------------------------------------
+// Resume un export de TiddlyWiki: cantidad de tiddlers, tipos, etiquetas más
+// usadas, rango de fechas y volumen de texto.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+)
+
+func runStats(args []string) error {
+	fs := newFlagSet("stats", "[-top N] archivo.json", `
+Muestra estadísticas de un export de TiddlyWiki.`)
+	top := fs.Int("top", 10, "Cantidad de etiquetas más usadas a mostrar")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("se necesita exactamente un archivo")
+	}
+
+	tiddlers, err := importer.Read(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	types := make(map[string]int)
+	tags := make(map[string]int)
+	var system, textBytes int
+	var oldest, newest string
+	for _, t := range tiddlers {
+		typ := t.Type
+		if typ == "" {
+			typ = "(sin tipo)"
+		}
+		types[typ]++
+		for _, tag := range t.TagsAsSlice() {
+			tags[tag]++
+		}
+		if strings.HasPrefix(t.Title, "$:/") {
+			system++
+		}
+		textBytes += len(t.Text)
+		if c := t.GetCreated(); c != "" && (oldest == "" || c < oldest) {
+			oldest = c
+		}
+		if m := t.GetModified(); m != "" && m > newest {
+			newest = m
+		}
+	}
+
+	fmt.Printf("📦 Tiddlers:          %d (%d de sistema)\n", len(tiddlers), system)
+	fmt.Printf("📝 Texto total:       %d bytes\n", textBytes)
+	fmt.Printf("📅 Creado más antiguo: %s\n", orDash(oldest))
+	fmt.Printf("📅 Última modificación: %s\n", orDash(newest))
+	fmt.Println("🗂️  Tipos:")
+	for _, kv := range sortedCounts(types, 0) {
+		fmt.Printf("  %-28s %d\n", kv.key, kv.n)
+	}
+	fmt.Printf("🏷️  Etiquetas (%d distintas):\n", len(tags))
+	for _, kv := range sortedCounts(tags, *top) {
+		fmt.Printf("  %-28s %d\n", kv.key, kv.n)
+	}
+	return nil
+}
+
+type keyCount struct {
+	key string
+	n   int
+}
+
+// sortedCounts ordena por frecuencia descendente (desempate alfabético) y
+// limita a limit entradas si limit > 0.
+func sortedCounts(m map[string]int, limit int) []keyCount {
+	out := make([]keyCount, 0, len(m))
+	for k, n := range m {
+		out = append(out, keyCount{k, n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].n != out[j].n {
+			return out[i].n > out[j].n
+		}
+		return out[i].key < out[j].key
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
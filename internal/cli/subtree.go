@@ -0,0 +1,123 @@
+// internal/cli/subtree.go – Subcomando `subtree`
+// --------------------------------------------------------------------------------
+// Exporta un proyecto completo: el tiddler raíz y todo lo alcanzable desde él
+// por jerarquía de tags, campos `list`, enlaces o relations, hasta -depth
+// saltos (ver internal/subtree).  La salida es un array JSON de TiddlyWiki o,
+// con -format plugin, un único tiddler plugin que se importa de una vez.
+//
+//   openpages subtree -input wiki.json -root "Mi Proyecto" -output proyecto.json
+//   openpages subtree -input wiki.json -root "Mi Proyecto" -depth 2 -follow tags,list \
+//     -format plugin -plugin-title '$:/plugins/equipo/mi-proyecto' -output plugin.json
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/exporter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/subtree"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// defaultRootTitle es el título con el que las plantillas de proyecto
+// nombran su tiddler raíz.
+const defaultRootTitle = "_____Nombre del Proyecto"
+
+func runSubtree(args []string) error {
+	fs := newFlagSet("subtree", "[-input wiki.json|-] [-output destino.json|-] [-root título] [flags]", `
+Exporta el tiddler raíz de un proyecto y todo lo que cuelga de él: los
+tiddlers etiquetados con él, los de su campo 'list', los enlazados o
+transcluidos en su texto y los de sus relations, recursivamente hasta -depth
+saltos.  -input puede ser un export de TiddlyWiki o un JSONL de cualquier
+modo.  La salida es un array JSON de TiddlyWiki o, con -format plugin, un
+tiddler plugin con todo el proyecto dentro.`)
+	in := fs.String("input", "", "Wiki de origen (\"-\" = stdin)")
+	out := fs.String("output", "", "Archivo JSON de salida (\"-\" = stdout, por defecto)")
+	root := fs.String("root", defaultRootTitle, "Título del tiddler raíz")
+	depth := fs.Int("depth", -1, "Saltos máximos desde la raíz (-1 = sin límite, 0 = sólo la raíz)")
+	follow := fs.String("follow", "all", "Aristas a seguir: "+strings.Join(subtree.Edges, ",")+" o all")
+	format := fs.String("format", "json", "Salida: json (array TiddlyWiki) | plugin")
+	pluginTitle := fs.String("plugin-title", "", "Título del plugin (por defecto $:/plugins/openpages/<raíz>)")
+	version := fs.String("plugin-version", "", "Versión del plugin")
+	description := fs.String("plugin-description", "", "Descripción del plugin")
+	dependents := fs.String("plugin-dependents", "", "Plugins que deben instalarse junto a éste (lista TiddlyWiki)")
+	report := fs.String("report", "", "Informe JSON: cada tiddler con su profundidad y arista, y los títulos ausentes")
+	pretty := fs.Bool("pretty", true, "Indentar el JSON de salida")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := defaultStdio(in, out); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "plugin" {
+		return usagef("formato desconocido: %s (usa 'json' o 'plugin')", *format)
+	}
+	edges, err := subtree.ParseEdges(*follow)
+	if err != nil {
+		return usagef("%v", err)
+	}
+
+	ts, err := readAnyShape(*in)
+	if err != nil {
+		return err
+	}
+	res, err := subtree.Collect(ts, *root, subtree.Options{Depth: *depth, Follow: edges})
+	if err != nil {
+		return err
+	}
+
+	output := res.Tiddlers
+	if *format == "plugin" {
+		title := *pluginTitle
+		if title == "" {
+			title = "$:/plugins/openpages/" + slug(*root)
+		}
+		p, err := plugin.Pack(res.Tiddlers, plugin.Info{Title: title, Version: *version, Description: *description,
+			Dependents: models.ParseList(*dependents)})
+		if err != nil {
+			return err
+		}
+		output = []models.Tiddler{p}
+	}
+	if err := exporter.WriteJSON(*out, output, *pretty); err != nil {
+		return fmt.Errorf("escribiendo subárbol: %w", err)
+	}
+	if *report != "" {
+		if err := exporter.WriteJSON(*report, res, true); err != nil {
+			return fmt.Errorf("escribiendo informe: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "📝 Informe del subárbol: %s\n", *report)
+	}
+	for _, title := range res.Missing {
+		fmt.Fprintf(os.Stderr, "  ⚠️  referenciado pero ausente: %q\n", title)
+	}
+	fmt.Fprintf(os.Stderr, "✅ Subárbol de %q: %d tiddlers (destino: %s)\n", *root, len(res.Tiddlers), *out)
+	return nil
+}
+
+// slug deriva un nombre de plugin de un título: minúsculas, sin signos y con
+// guiones en lugar de espacios ("_____Nombre del Proyecto" → "nombre-del-proyecto").
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	if b.Len() == 0 {
+		return "proyecto"
+	}
+	return b.String()
+}
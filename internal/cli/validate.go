@@ -0,0 +1,146 @@
+// internal/cli/validate.go – Subcomando `validate`
+// --------------------------------------------------------------------------------
+// Verifica la estructura de un export antes de procesarlo:
+//   • JSON de TiddlyWiki → títulos vacíos o repetidos y fechas mal formadas.
+//   • JSONL (.jsonl)     → líneas que no son objetos JSON, sin id/título o
+//                          con id repetido.
+// Si encuentra problemas los lista y termina con ExitFindings.
+// --------------------------------------------------------------------------------
+
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/importer"
+)
+
+// twDateRe acepta las fechas TiddlyWiki yyyymmdd, yyyymmddhhMMSS y yyyymmddhhMMSSmmm.
+var twDateRe = regexp.MustCompile(`^\d{8}(\d{6}(\d{3})?)?$`)
+
+func runValidate(args []string) error {
+	fs := newFlagSet("validate", "archivo.json|archivo.jsonl", `
+Verifica la estructura de un export de TiddlyWiki (JSON) o de un JSONL
+generado por 'export'.  Termina con código 3 si encuentra problemas.`)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usagef("se necesita exactamente un archivo a validar")
+	}
+	path := fs.Arg(0)
+
+	var (
+		issues []string
+		total  int
+		err    error
+	)
+	if filepath.Ext(path) == ".jsonl" {
+		total, issues, err = validateJSONL(path)
+	} else {
+		total, issues, err = validateTiddlyJSON(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		fmt.Printf("  • %s\n", issue)
+	}
+	if len(issues) > 0 {
+		return findingsError{msg: fmt.Sprintf("%d problemas en %d registros de %s", len(issues), total, path)}
+	}
+	fmt.Printf("✅ %s es válido (%d registros)\n", path, total)
+	return nil
+}
+
+func validateTiddlyJSON(path string) (int, []string, error) {
+	tiddlers, err := importer.Read(context.Background(), path)
+	if err != nil {
+		return 0, nil, err
+	}
+	var issues []string
+	seen := make(map[string]bool, len(tiddlers))
+	for i, t := range tiddlers {
+		if strings.TrimSpace(t.Title) == "" {
+			issues = append(issues, fmt.Sprintf("tiddler #%d: título vacío", i+1))
+			continue
+		}
+		if seen[t.Title] {
+			issues = append(issues, fmt.Sprintf("%q: título repetido", t.Title))
+		}
+		seen[t.Title] = true
+		if t.Created != "" && !twDateRe.MatchString(t.Created) {
+			issues = append(issues, fmt.Sprintf("%q: created con formato inválido (%s)", t.Title, t.Created))
+		}
+		if t.Modified != "" && !twDateRe.MatchString(t.Modified) {
+			issues = append(issues, fmt.Sprintf("%q: modified con formato inválido (%s)", t.Title, t.Modified))
+		}
+	}
+	return len(tiddlers), issues, nil
+}
+
+func validateJSONL(path string) (int, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, fmt.Errorf("no se pudo abrir '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	var issues []string
+	seen := make(map[string]int)
+	total := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		total++
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(line), &obj); err != nil {
+			issues = append(issues, fmt.Sprintf("línea %d: JSON inválido: %v", lineNumber, err))
+			continue
+		}
+		id := recordID(obj)
+		if id == "" {
+			issues = append(issues, fmt.Sprintf("línea %d: sin id ni título", lineNumber))
+			continue
+		}
+		if prev, ok := seen[id]; ok {
+			issues = append(issues, fmt.Sprintf("línea %d: id %q repetido (línea %d)", lineNumber, id, prev))
+			continue
+		}
+		seen[id] = lineNumber
+	}
+	if err := scanner.Err(); err != nil {
+		return total, issues, fmt.Errorf("leyendo JSONL: %w", err)
+	}
+	return total, issues, nil
+}
+
+// recordID obtiene el identificador de un registro JSONL de cualquier modo:
+// id (v1/v2/v3), title (v3/hybrid) o meta.title (v2).
+func recordID(obj map[string]any) string {
+	for _, key := range []string{"id", "title"} {
+		if s, ok := obj[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	if meta, ok := obj["meta"].(map[string]any); ok {
+		if s, ok := meta["title"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,309 @@
+// internal/config/config.go – Archivo de proyecto openpages.yaml / openpages.toml
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Cada corrida del pipeline necesita el mismo conjunto de flags (entrada,
+// salida, modo, estado de deduplicación…).  En lugar de recordarlos en scripts
+// interactivos, el proyecto los declara en un archivo versionable:
+//
+//   inputs:
+//     - path: data/in/tiddlers.json
+//     - path: data/in/equipo_b.json
+//       name: equipoB
+//   outputs:
+//     - path: data/out/tiddlers_v3.jsonl
+//       mode: v3
+//     - path: data/out/tiddlers_v2.parquet
+//       mode: v2
+//       format: parquet
+//   filters:
+//     exclude_system: true
+//     exclude_tags: [borrador]
+//     expression: "[days[-365]] +[sort[modified]]"
+//   dedup:
+//     state: data/state/hashes.txt
+//     near: true
+//   merge:
+//     policy: prefix
+//     report: data/out/conflictos.json
+//
+// El formato se elige por extensión (.yaml/.yml → YAML, .toml → TOML).  Las
+// rutas relativas se resuelven respecto de la carpeta del archivo, de modo que
+// `openpages run` funciona desde cualquier directorio.  "-" como ruta significa
+// stdin (en inputs) o stdout (en outputs).
+// --------------------------------------------------------------------------------
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/chunk"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/classify"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/filter"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+// DefaultNames son los archivos que Find busca, en orden de preferencia.
+var DefaultNames = []string{"openpages.yaml", "openpages.yml", "openpages.toml"}
+
+// Config es la descripción completa de un pipeline repetible.
+type Config struct {
+	Inputs  []Input  `yaml:"inputs" toml:"inputs"`
+	Outputs []Output `yaml:"outputs" toml:"outputs"`
+	Filters Filters  `yaml:"filters" toml:"filters"`
+	Dedup   Dedup    `yaml:"dedup" toml:"dedup"`
+	Merge   Merge    `yaml:"merge" toml:"merge"`
+	Chunk   Chunk    `yaml:"chunk" toml:"chunk"`
+	// Workers es la cantidad de goroutines de conversión (0 → una por CPU).
+	Workers int `yaml:"workers,omitempty" toml:"workers,omitempty"`
+	// Deterministic, Sort y FallbackDate controlan la salida reproducible
+	// (ver `openpages export -deterministic`).
+	Deterministic bool   `yaml:"deterministic,omitempty" toml:"deterministic,omitempty"`
+	Sort          string `yaml:"sort,omitempty" toml:"sort,omitempty"`
+	FallbackDate  string `yaml:"fallback_date,omitempty" toml:"fallback_date,omitempty"`
+	// Timezone es la zona de las fechas v2/v3 (UTC, Local, IANA o -05:00).
+	Timezone string `yaml:"timezone,omitempty" toml:"timezone,omitempty"`
+	// UnpackPlugins añade los tiddlers de cada plugin como tiddlers sueltos
+	// (ver `openpages export -unpack-plugins`).
+	UnpackPlugins bool `yaml:"unpack_plugins,omitempty" toml:"unpack_plugins,omitempty"`
+	// SystemPolicy decide qué hacer con los tiddlers de sistema, borradores,
+	// estado y plugins: include | exclude | split (cada salida <ruta>_system.<ext>;
+	// ver `openpages export -system-policy`).
+	SystemPolicy string `yaml:"system_policy,omitempty" toml:"system_policy,omitempty"`
+}
+
+// Input es un export de TiddlyWiki a leer.  Name identifica la wiki de origen.
+type Input struct {
+	Path string `yaml:"path" toml:"path"`
+	Name string `yaml:"name,omitempty" toml:"name,omitempty"`
+}
+
+// Output es un archivo a generar.
+//   - Mode:   v1 | v2 | v3 | hybrid | tiddlywiki (JSON de TiddlyWiki sin convertir).
+//   - Format: jsonl (por defecto) | json | csv | parquet.
+type Output struct {
+	Path   string `yaml:"path" toml:"path"`
+	Mode   string `yaml:"mode" toml:"mode"`
+	Format string `yaml:"format,omitempty" toml:"format,omitempty"`
+	Pretty bool   `yaml:"pretty,omitempty" toml:"pretty,omitempty"`
+}
+
+// Filters selecciona qué tiddlers pasan al resto del pipeline.  Expression
+// es un filtro de TiddlyWiki (ver internal/filter) que se aplica después de
+// los demás criterios.
+type Filters struct {
+	ExcludeSystem bool     `yaml:"exclude_system,omitempty" toml:"exclude_system,omitempty"`
+	IncludeTags   []string `yaml:"include_tags,omitempty" toml:"include_tags,omitempty"`
+	ExcludeTags   []string `yaml:"exclude_tags,omitempty" toml:"exclude_tags,omitempty"`
+	TitlePrefix   string   `yaml:"title_prefix,omitempty" toml:"title_prefix,omitempty"`
+	Expression    string   `yaml:"expression,omitempty" toml:"expression,omitempty"`
+}
+
+// Dedup configura la deduplicación exacta (State) y aproximada (Near*).
+type Dedup struct {
+	State     string  `yaml:"state,omitempty" toml:"state,omitempty"`
+	Near      bool    `yaml:"near,omitempty" toml:"near,omitempty"`
+	Method    string  `yaml:"method,omitempty" toml:"method,omitempty"`
+	Threshold float64 `yaml:"threshold,omitempty" toml:"threshold,omitempty"`
+	Report    string  `yaml:"report,omitempty" toml:"report,omitempty"`
+}
+
+// Chunk fragmenta los tiddlers de las salidas v2 en registros "fragment"
+// (ver `openpages export -chunk-tokens`).  MaxTokens 0 = sin fragmentar.
+type Chunk struct {
+	MaxTokens int    `yaml:"max_tokens,omitempty" toml:"max_tokens,omitempty"`
+	Overlap   int    `yaml:"overlap,omitempty" toml:"overlap,omitempty"`
+	Tokenizer string `yaml:"tokenizer,omitempty" toml:"tokenizer,omitempty"`
+}
+
+// Options devuelve la configuración como chunk.Options.
+func (c Chunk) Options() chunk.Options {
+	return chunk.Options{MaxTokens: c.MaxTokens, Overlap: c.Overlap, Tokenizer: c.Tokenizer}
+}
+
+// Merge configura cómo combinar varias entradas (ver merge.Merge).
+//   - Policy: newest (por defecto) | prefix | suffix | fail.
+//   - Report: ruta opcional del informe JSON de conflictos.
+type Merge struct {
+	Policy string `yaml:"policy,omitempty" toml:"policy,omitempty"`
+	Report string `yaml:"report,omitempty" toml:"report,omitempty"`
+}
+
+// Load lee y valida el archivo de configuración indicado.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer la configuración '%s': %w", path, err)
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("parsear YAML '%s': %w", path, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("parsear TOML '%s': %w", path, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("parsear TOML '%s': claves desconocidas: %v", path, undecoded)
+		}
+	default:
+		return nil, fmt.Errorf("extensión de configuración no soportada: %s (usa .yaml, .yml o .toml)", path)
+	}
+
+	cfg.resolvePaths(filepath.Dir(path))
+	cfg.applyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuración '%s': %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Find busca un archivo de configuración por defecto en dir.
+// Devuelve "" sin error si no existe ninguno.
+func Find(dir string) (string, error) {
+	for _, name := range DefaultNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// Validate comprueba que la configuración describa un pipeline ejecutable.
+func (c *Config) Validate() error {
+	if len(c.Inputs) == 0 {
+		return errors.New("se necesita al menos una entrada en 'inputs'")
+	}
+	for i, in := range c.Inputs {
+		if in.Path == "" {
+			return fmt.Errorf("inputs[%d]: falta 'path'", i)
+		}
+	}
+	switch c.Sort {
+	case "input", "title":
+	default:
+		return fmt.Errorf("sort: orden desconocido %q (usa input o title)", c.Sort)
+	}
+	opts, err := transform.ParseFallback(c.FallbackDate)
+	if err != nil {
+		return fmt.Errorf("fallback_date: %w", err)
+	}
+	if c.Deterministic && c.FallbackDate != "" && !opts.Reproducible() {
+		return errors.New("fallback_date: 'now' no es compatible con deterministic")
+	}
+	if _, err := transform.ParseLocation(c.Timezone); err != nil {
+		return fmt.Errorf("timezone: %w", err)
+	}
+	if _, err := classify.ParsePolicy(c.SystemPolicy); err != nil {
+		return fmt.Errorf("system_policy: %w", err)
+	}
+	if c.Filters.Expression != "" {
+		if _, err := filter.Parse(c.Filters.Expression); err != nil {
+			return fmt.Errorf("filters.expression: %w", err)
+		}
+	}
+	switch c.Merge.Policy {
+	case "newest", "prefix", "suffix", "fail":
+	default:
+		return fmt.Errorf("merge: política desconocida %q", c.Merge.Policy)
+	}
+	if len(c.Outputs) == 0 {
+		return errors.New("se necesita al menos una salida en 'outputs'")
+	}
+	for i, out := range c.Outputs {
+		if out.Path == "" {
+			return fmt.Errorf("outputs[%d]: falta 'path'", i)
+		}
+		switch out.Mode {
+		case "v1", "v2", "v3", "hybrid", "tiddlywiki":
+		default:
+			return fmt.Errorf("outputs[%d]: modo desconocido %q", i, out.Mode)
+		}
+		switch out.Format {
+		case "jsonl", "json", "csv", "parquet":
+		default:
+			return fmt.Errorf("outputs[%d]: formato desconocido %q", i, out.Format)
+		}
+		if out.Path == "-" && out.Format == "parquet" {
+			return fmt.Errorf("outputs[%d]: el formato parquet no puede escribirse en stdout", i)
+		}
+		if out.Mode == "tiddlywiki" && out.Format != "json" {
+			return fmt.Errorf("outputs[%d]: el modo tiddlywiki sólo admite formato json", i)
+		}
+	}
+	if c.Chunk != (Chunk{}) {
+		if err := c.Chunk.Options().Validate(); err != nil {
+			return fmt.Errorf("chunk: %w", err)
+		}
+		hasV2 := false
+		for _, out := range c.Outputs {
+			hasV2 = hasV2 || out.Mode == "v2"
+		}
+		if !hasV2 {
+			return errors.New("chunk: los fragmentos son registros v2 y no hay ninguna salida v2")
+		}
+	}
+	return nil
+}
+
+func (c *Config) applyDefaults() {
+	if c.Workers <= 0 {
+		c.Workers = runtime.NumCPU()
+	}
+	if c.Sort == "" {
+		c.Sort = "input"
+	}
+	if c.Merge.Policy == "" {
+		c.Merge.Policy = "newest"
+	}
+	for i := range c.Outputs {
+		if c.Outputs[i].Mode == "" {
+			c.Outputs[i].Mode = "v3"
+		}
+		if c.Outputs[i].Format == "" {
+			c.Outputs[i].Format = "jsonl"
+			if c.Outputs[i].Mode == "tiddlywiki" {
+				c.Outputs[i].Format = "json"
+			}
+		}
+	}
+}
+
+// resolvePaths vuelve absolutas las rutas relativas usando base.  "-"
+// (stdin/stdout) se conserva tal cual.
+func (c *Config) resolvePaths(base string) {
+	resolve := func(p string) string {
+		if p == "" || p == "-" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(base, p)
+	}
+	for i := range c.Inputs {
+		c.Inputs[i].Path = resolve(c.Inputs[i].Path)
+	}
+	for i := range c.Outputs {
+		c.Outputs[i].Path = resolve(c.Outputs[i].Path)
+	}
+	c.Dedup.State = resolve(c.Dedup.State)
+	c.Dedup.Report = resolve(c.Dedup.Report)
+	c.Merge.Report = resolve(c.Merge.Report)
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_YAML(t *testing.T) {
+	path := writeConfig(t, "openpages.yaml", `
+inputs:
+  - path: data/in/tiddlers.json
+outputs:
+  - path: data/out/t.jsonl
+  - path: data/out/t.json
+    mode: tiddlywiki
+filters:
+  exclude_system: true
+  exclude_tags: [borrador]
+dedup:
+  state: state/hashes.txt
+  near: true
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	base := filepath.Dir(path)
+	if got := cfg.Inputs[0].Path; got != filepath.Join(base, "data/in/tiddlers.json") {
+		t.Errorf("ruta de entrada no resuelta: %s", got)
+	}
+	if cfg.Outputs[0].Mode != "v3" || cfg.Outputs[0].Format != "jsonl" {
+		t.Errorf("defaults de salida = %+v", cfg.Outputs[0])
+	}
+	if cfg.Outputs[1].Format != "json" {
+		t.Errorf("tiddlywiki debería usar formato json, got %q", cfg.Outputs[1].Format)
+	}
+	if cfg.Merge.Policy != "newest" {
+		t.Errorf("política por defecto = %q, want newest", cfg.Merge.Policy)
+	}
+	if !cfg.Filters.ExcludeSystem || len(cfg.Filters.ExcludeTags) != 1 || !cfg.Dedup.Near {
+		t.Errorf("filtros/dedup mal parseados: %+v %+v", cfg.Filters, cfg.Dedup)
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeConfig(t, "openpages.toml", `
+[[inputs]]
+path = "/abs/tiddlers.json"
+
+[[outputs]]
+path = "out.parquet"
+mode = "v2"
+format = "parquet"
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Inputs[0].Path != "/abs/tiddlers.json" {
+		t.Errorf("ruta absoluta modificada: %s", cfg.Inputs[0].Path)
+	}
+	if cfg.Outputs[0].Format != "parquet" || cfg.Outputs[0].Mode != "v2" {
+		t.Errorf("salida = %+v", cfg.Outputs[0])
+	}
+}
+
+func TestLoad_Errores(t *testing.T) {
+	cases := map[string]string{
+		"clave desconocida":            "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nmodo: v3\n",
+		"sin salidas":                  "inputs: [{path: a.json}]\n",
+		"modo inválido":                "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl, mode: v9}]\n",
+		"política inválida":            "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nmerge: {policy: random}\n",
+		"orden inválido":               "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nsort: fecha\n",
+		"fecha inválida":               "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nfallback_date: mañana\n",
+		"now determinista":             "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\ndeterministic: true\nfallback_date: now\n",
+		"zona inválida":                "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\ntimezone: Marte/Olympus\n",
+		"política inválida de sistema": "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nsystem_policy: ocultar\n",
+		"chunk sin v2":                 "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl, mode: v3}]\nchunk: {max_tokens: 256}\n",
+		"solapamiento":                 "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl, mode: v2}]\nchunk: {max_tokens: 256, overlap: 300}\n",
+		"filtro inválido":              "inputs: [{path: a.json}]\noutputs: [{path: b.jsonl}]\nfilters: {expression: '[tag[x]'}\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(writeConfig(t, "openpages.yaml", content)); err == nil {
+				t.Errorf("esperaba error")
+			}
+		})
+	}
+	if _, err := Load(writeConfig(t, "openpages.ini", "")); err == nil || !strings.Contains(err.Error(), "extensión") {
+		t.Errorf("esperaba error de extensión, got %v", err)
+	}
+}
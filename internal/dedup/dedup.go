@@ -29,3 +29,18 @@ func HashTiddler(t models.Tiddler) string {
 	h.Write([]byte(t.Text))
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+// HashContent genera un SHA-256 del contenido de un tiddler sin su título ni
+// sus fechas (Type, Text y etiquetas): dos tiddlers con el mismo HashContent
+// y distinto título son un renombrado.
+func HashContent(t models.Tiddler) string {
+	h := sha256.New()
+	h.Write([]byte(t.Type))
+	h.Write([]byte{0})
+	h.Write([]byte(t.Text))
+	for _, tag := range t.TagsAsSlice() {
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
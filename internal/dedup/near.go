@@ -0,0 +1,438 @@
+// internal/dedup/near.go – Detección de casi-duplicados (MinHash / SimHash + LSH)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// HashTiddler sólo detecta copias exactas.  En las wikis reales abundan los
+// tiddlers copiados y pegados con ediciones mínimas, que contaminan la
+// recuperación en pipelines RAG.  Este archivo agrega un detector aproximado:
+//
+//   1. Normaliza el texto plano (minúsculas, sólo letras y dígitos) y lo
+//      divide en *shingles* de k palabras.
+//   2. Calcula una firma por tiddler:
+//        • MinHash → NumHashes mínimos de funciones hash independientes.
+//        • SimHash → huella de 64 bits ponderada por shingle.
+//   3. Usa LSH por bandas para encontrar pares candidatos sin comparar todos
+//      contra todos.
+//   4. Verifica cada candidato (Jaccard exacto o distancia de Hamming) y
+//      agrupa los pares aceptados en clusters mediante union-find.
+//
+// El resultado es un NearReport serializable a JSON, y KeepRepresentatives
+// permite exportar un solo tiddler por cluster.
+// --------------------------------------------------------------------------------
+
+package dedup
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Métodos soportados por FindNearDuplicates.
+const (
+	MethodMinHash = "minhash"
+	MethodSimHash = "simhash"
+)
+
+// NearOptions configura la detección de casi-duplicados.
+// Los valores cero se sustituyen por los de DefaultNearOptions.
+type NearOptions struct {
+	Method     string  // "minhash" | "simhash"
+	Shingle    int     // tamaño del shingle en palabras
+	NumHashes  int     // funciones hash de la firma MinHash
+	Bands      int     // bandas LSH (MinHash); debe dividir a NumHashes
+	Threshold  float64 // Jaccard mínimo para considerar dos tiddlers casi iguales
+	MaxHamming int     // distancia de Hamming máxima entre huellas SimHash
+}
+
+// DefaultNearOptions devuelve una configuración razonable para textos de wiki.
+func DefaultNearOptions() NearOptions {
+	return NearOptions{
+		Method:     MethodMinHash,
+		Shingle:    5,
+		NumHashes:  128,
+		Bands:      32,
+		Threshold:  0.8,
+		MaxHamming: 3,
+	}
+}
+
+func (o NearOptions) withDefaults() NearOptions {
+	def := DefaultNearOptions()
+	if o.Method == "" {
+		o.Method = def.Method
+	}
+	if o.Shingle <= 0 {
+		o.Shingle = def.Shingle
+	}
+	if o.NumHashes <= 0 {
+		o.NumHashes = def.NumHashes
+	}
+	if o.Bands <= 0 || o.Bands > o.NumHashes || o.NumHashes%o.Bands != 0 {
+		o.Bands = def.Bands
+		if o.NumHashes%o.Bands != 0 {
+			o.Bands = o.NumHashes
+		}
+	}
+	if o.Threshold <= 0 || o.Threshold > 1 {
+		o.Threshold = def.Threshold
+	}
+	if o.MaxHamming < 0 || o.MaxHamming >= 64 {
+		o.MaxHamming = def.MaxHamming
+	}
+	return o
+}
+
+// ClusterMember es un tiddler dentro de un cluster y su similitud Jaccard
+// (sobre shingles) con el representante.  Index es su posición en la lista
+// analizada: los títulos pueden repetirse (p.ej. al juntar varios archivos).
+type ClusterMember struct {
+	Title      string  `json:"title"`
+	Index      int     `json:"index"`
+	Similarity float64 `json:"similarity"`
+}
+
+// Cluster agrupa tiddlers casi idénticos.  Representative (en la posición
+// RepresentativeIndex) es el que se conserva con KeepRepresentatives.
+type Cluster struct {
+	Representative      string          `json:"representative"`
+	RepresentativeIndex int             `json:"representative_index"`
+	Members             []ClusterMember `json:"members"`
+}
+
+// NearReport es el informe de clusters que se escribe a disco.
+type NearReport struct {
+	Method    string    `json:"method"`
+	Threshold float64   `json:"threshold"`
+	Total     int       `json:"total"`
+	Clusters  []Cluster `json:"clusters"`
+}
+
+// Duplicates devuelve cuántos tiddlers se descartarían conservando un
+// representante por cluster.
+func (r NearReport) Duplicates() int {
+	n := 0
+	for _, c := range r.Clusters {
+		n += len(c.Members) - 1
+	}
+	return n
+}
+
+// FindNearDuplicates agrupa los tiddlers cuyo texto es casi idéntico.
+// Los tiddlers sin texto se ignoran.  El orden de clusters y miembros es
+// determinista (por título) para que el informe sea comparable entre corridas.
+func FindNearDuplicates(ts []models.Tiddler, opts NearOptions) NearReport {
+	opts = opts.withDefaults()
+
+	docs := make([]nearDoc, 0, len(ts))
+	for i, t := range ts {
+		sh := shingles(transform.GetTextContent(t.Text), opts.Shingle)
+		if len(sh) == 0 {
+			continue
+		}
+		docs = append(docs, nearDoc{index: i, shingles: sh})
+	}
+
+	var pairs []nearPair
+	switch opts.Method {
+	case MethodSimHash:
+		pairs = simhashPairs(docs, opts)
+	default:
+		opts.Method = MethodMinHash
+		pairs = minhashPairs(docs, opts)
+	}
+
+	report := NearReport{Method: opts.Method, Threshold: opts.Threshold, Total: len(ts)}
+	if opts.Method == MethodSimHash {
+		report.Threshold = 1 - float64(opts.MaxHamming)/64
+	}
+	report.Clusters = buildClusters(ts, docs, pairs)
+	return report
+}
+
+// KeepRepresentatives devuelve ts sin los miembros no representativos de
+// cada cluster, preservando el orden original.  report debe venir de
+// FindNearDuplicates sobre el mismo ts: los miembros se identifican por
+// posición, no por título.
+func KeepRepresentatives(ts []models.Tiddler, report NearReport) []models.Tiddler {
+	drop := make(map[int]struct{})
+	for _, c := range report.Clusters {
+		for _, m := range c.Members {
+			if m.Index != c.RepresentativeIndex {
+				drop[m.Index] = struct{}{}
+			}
+		}
+	}
+	out := make([]models.Tiddler, 0, len(ts))
+	for i, t := range ts {
+		if _, ok := drop[i]; ok {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// -----------------------------------------------------------------------------
+// Shingles
+// -----------------------------------------------------------------------------
+
+type nearDoc struct {
+	index    int
+	shingles map[uint64]struct{}
+}
+
+type nearPair struct {
+	a, b       int // posiciones en docs
+	similarity float64
+}
+
+// shingles normaliza el texto y devuelve el conjunto de hashes de k-gramas
+// de palabras.  Un texto más corto que k produce un único shingle.
+func shingles(text string, k int) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	set := make(map[uint64]struct{})
+	if len(words) < k {
+		set[hashString(strings.Join(words, " "))] = struct{}{}
+		return set
+	}
+	for i := 0; i+k <= len(words); i++ {
+		set[hashString(strings.Join(words[i:i+k], " "))] = struct{}{}
+	}
+	return set
+}
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix64 es el finalizador de splitmix64: dispersa bien valores consecutivos
+// y permite derivar tantas funciones hash como semillas.
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func jaccard(a, b map[uint64]struct{}) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	inter := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			inter++
+		}
+	}
+	union := len(a) + len(b) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
+
+// -----------------------------------------------------------------------------
+// MinHash + LSH
+// -----------------------------------------------------------------------------
+
+func minhashSignature(set map[uint64]struct{}, n int) []uint64 {
+	sig := make([]uint64, n)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for h := range set {
+		for i := range sig {
+			if v := mix64(h ^ uint64(i)*0x9e3779b97f4a7c15); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+func minhashPairs(docs []nearDoc, opts NearOptions) []nearPair {
+	rows := opts.NumHashes / opts.Bands
+	buckets := make(map[uint64][]int)
+	buf := make([]byte, 8)
+
+	for d, doc := range docs {
+		sig := minhashSignature(doc.shingles, opts.NumHashes)
+		for b := 0; b < opts.Bands; b++ {
+			h := fnv.New64a()
+			binary.LittleEndian.PutUint64(buf, uint64(b))
+			h.Write(buf)
+			for _, v := range sig[b*rows : (b+1)*rows] {
+				binary.LittleEndian.PutUint64(buf, v)
+				h.Write(buf)
+			}
+			key := h.Sum64()
+			buckets[key] = append(buckets[key], d)
+		}
+	}
+
+	return verifyCandidates(buckets, func(a, b int) (float64, bool) {
+		sim := jaccard(docs[a].shingles, docs[b].shingles)
+		return sim, sim >= opts.Threshold
+	})
+}
+
+// -----------------------------------------------------------------------------
+// SimHash + LSH
+// -----------------------------------------------------------------------------
+
+func simhash(set map[uint64]struct{}) uint64 {
+	var acc [64]int
+	for h := range set {
+		v := mix64(h)
+		for i := 0; i < 64; i++ {
+			if v&(1<<uint(i)) != 0 {
+				acc[i]++
+			} else {
+				acc[i]--
+			}
+		}
+	}
+	var fp uint64
+	for i := 0; i < 64; i++ {
+		if acc[i] > 0 {
+			fp |= 1 << uint(i)
+		}
+	}
+	return fp
+}
+
+// simhashPairs divide la huella en MaxHamming+1 bandas: por el principio del
+// palomar, dos huellas a distancia ≤ MaxHamming coinciden en al menos una.
+func simhashPairs(docs []nearDoc, opts NearOptions) []nearPair {
+	fps := make([]uint64, len(docs))
+	for i, doc := range docs {
+		fps[i] = simhash(doc.shingles)
+	}
+
+	bands := opts.MaxHamming + 1
+	width := 64 / bands
+	buckets := make(map[uint64][]int)
+	for d, fp := range fps {
+		for b := 0; b < bands; b++ {
+			lo := uint(b * width)
+			hi := uint((b + 1) * width)
+			if b == bands-1 {
+				hi = 64
+			}
+			part := (fp >> lo) & (1<<(hi-lo) - 1)
+			key := mix64(part ^ uint64(b)<<56)
+			buckets[key] = append(buckets[key], d)
+		}
+	}
+
+	return verifyCandidates(buckets, func(a, b int) (float64, bool) {
+		dist := bits.OnesCount64(fps[a] ^ fps[b])
+		return 1 - float64(dist)/64, dist <= opts.MaxHamming
+	})
+}
+
+// verifyCandidates recorre cada bucket LSH y aplica check una sola vez por par.
+func verifyCandidates(buckets map[uint64][]int, check func(a, b int) (float64, bool)) []nearPair {
+	seen := make(map[[2]int]struct{})
+	var pairs []nearPair
+	for _, members := range buckets {
+		for i := 0; i < len(members); i++ {
+			for j := i + 1; j < len(members); j++ {
+				a, b := members[i], members[j]
+				if a > b {
+					a, b = b, a
+				}
+				key := [2]int{a, b}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+				if sim, ok := check(a, b); ok {
+					pairs = append(pairs, nearPair{a: a, b: b, similarity: sim})
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// -----------------------------------------------------------------------------
+// Clusters
+// -----------------------------------------------------------------------------
+
+// buildClusters une los pares aceptados con union-find y elige como
+// representante el tiddler modificado más recientemente (desempate por título).
+func buildClusters(ts []models.Tiddler, docs []nearDoc, pairs []nearPair) []Cluster {
+	parent := make([]int, len(docs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	for _, p := range pairs {
+		ra, rb := find(p.a), find(p.b)
+		if ra != rb {
+			parent[ra] = rb
+		}
+	}
+
+	groups := make(map[int][]int)
+	for d := range docs {
+		r := find(d)
+		groups[r] = append(groups[r], d)
+	}
+
+	var clusters []Cluster
+	for _, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		rep := members[0]
+		for _, d := range members[1:] {
+			a, b := ts[docs[d].index], ts[docs[rep].index]
+			if a.GetModified() > b.GetModified() ||
+				(a.GetModified() == b.GetModified() && a.Title < b.Title) {
+				rep = d
+			}
+		}
+		c := Cluster{Representative: ts[docs[rep].index].Title, RepresentativeIndex: docs[rep].index}
+		for _, d := range members {
+			sim := 1.0
+			if d != rep {
+				sim = math.Round(jaccard(docs[d].shingles, docs[rep].shingles)*1000) / 1000
+			}
+			c.Members = append(c.Members, ClusterMember{Title: ts[docs[d].index].Title, Index: docs[d].index, Similarity: sim})
+		}
+		sort.Slice(c.Members, func(i, j int) bool {
+			a, b := c.Members[i], c.Members[j]
+			return a.Title < b.Title || (a.Title == b.Title && a.Index < b.Index)
+		})
+		clusters = append(clusters, c)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		return a.Representative < b.Representative ||
+			(a.Representative == b.Representative && a.RepresentativeIndex < b.RepresentativeIndex)
+	})
+	return clusters
+}
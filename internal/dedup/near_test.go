@@ -0,0 +1,93 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func nearFixture() []models.Tiddler {
+	base := "El sistema de riego automatizado controla válvulas, sensores de humedad y " +
+		"bombas de agua para mantener el cultivo dentro del rango óptimo durante todo el año. " +
+		"Cada sensor reporta lecturas cada quince minutos y el controlador decide cuándo regar."
+	return []models.Tiddler{
+		{Title: "Riego", Text: base, Modified: "20250101000000"},
+		{Title: "Riego (copia)", Text: base + " Revisado.", Modified: "20250301000000"},
+		{Title: "Cosecha", Text: "La cosecha se planifica según la madurez del fruto y la demanda del mercado local."},
+		{Title: "Vacío", Text: ""},
+	}
+}
+
+func TestFindNearDuplicates_MinHash(t *testing.T) {
+	ts := nearFixture()
+	rep := FindNearDuplicates(ts, NearOptions{Method: MethodMinHash, Threshold: 0.7})
+
+	if len(rep.Clusters) != 1 {
+		t.Fatalf("clusters = %d, want 1 (%+v)", len(rep.Clusters), rep.Clusters)
+	}
+	c := rep.Clusters[0]
+	if c.Representative != "Riego (copia)" {
+		t.Errorf("representante = %q, want el modificado más reciente", c.Representative)
+	}
+	if len(c.Members) != 2 {
+		t.Errorf("miembros = %d, want 2", len(c.Members))
+	}
+	if rep.Duplicates() != 1 {
+		t.Errorf("Duplicates() = %d, want 1", rep.Duplicates())
+	}
+}
+
+func TestFindNearDuplicates_SimHash(t *testing.T) {
+	ts := nearFixture()
+	ts[1].Text = ts[0].Text // idénticos: distancia de Hamming 0
+	rep := FindNearDuplicates(ts, NearOptions{Method: MethodSimHash, MaxHamming: 3})
+
+	if rep.Method != MethodSimHash {
+		t.Errorf("método = %q", rep.Method)
+	}
+	if len(rep.Clusters) != 1 || len(rep.Clusters[0].Members) != 2 {
+		t.Fatalf("clusters inesperados: %+v", rep.Clusters)
+	}
+}
+
+func TestKeepRepresentatives(t *testing.T) {
+	ts := nearFixture()
+	rep := FindNearDuplicates(ts, NearOptions{Threshold: 0.7})
+	kept := KeepRepresentatives(ts, rep)
+
+	if len(kept) != len(ts)-1 {
+		t.Fatalf("conservados = %d, want %d", len(kept), len(ts)-1)
+	}
+	for _, k := range kept {
+		if k.Title == "Riego" {
+			t.Errorf("el duplicado no representativo no debió conservarse")
+		}
+	}
+}
+
+// Los títulos repetidos (p.ej. export -merge de varios archivos) no deben
+// confundir qué se descarta: cuenta la posición, no el título.
+func TestKeepRepresentatives_RepeatedTitles(t *testing.T) {
+	ts := nearFixture()
+	ts[1].Title = "Riego"           // dos casi-duplicados con el mismo título
+	ts[2].Title = "Riego"           // y un tiddler sin relación que también se llama así
+	ts[1].Modified = ts[0].Modified // desempate por posición
+	rep := FindNearDuplicates(ts, NearOptions{Threshold: 0.7})
+	if len(rep.Clusters) != 1 {
+		t.Fatalf("clusters = %+v, want 1", rep.Clusters)
+	}
+	kept := KeepRepresentatives(ts, rep)
+	if len(kept) != len(ts)-1 {
+		t.Fatalf("conservados = %d, want %d", len(kept), len(ts)-1)
+	}
+	texts := map[string]bool{}
+	for _, k := range kept {
+		texts[k.Text] = true
+	}
+	if !texts[ts[2].Text] {
+		t.Error("se descartó un tiddler sin relación sólo por compartir título")
+	}
+	if texts[ts[0].Text] && texts[ts[1].Text] {
+		t.Error("se conservaron los dos casi-duplicados")
+	}
+}
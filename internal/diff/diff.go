@@ -0,0 +1,186 @@
+// internal/diff/diff.go – Diferencias entre dos exports o snapshots
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// "¿Qué cambió desde el export de la semana pasada?"  Compare recibe dos
+// conjuntos de tiddlers (de cualquier forma de entrada: JSON de TiddlyWiki o
+// JSONL de cualquier modo, ya revertidos) y los empareja por título (el id de
+// los registros exportados):
+//
+//   added     → título sólo en after.
+//   removed   → título sólo en before.
+//   renamed   → un removed y un added con el mismo contenido
+//               (dedup.HashContent: tipo, texto y etiquetas).
+//   modified  → mismo título con algún campo distinto (roundtrip.Compare:
+//               etiquetas como lista, vacío = ausente, campos personalizados
+//               como fields.<nombre>).  El texto trae además un diff unificado.
+//
+// El Result se presenta en texto, JSON o Markdown (ver format.go).
+// --------------------------------------------------------------------------------
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Tipos de entrada del diff.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Renamed  = "renamed"
+	Modified = "modified"
+)
+
+// Change es un campo que cambió.
+type Change struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+	Diff   string `json:"diff,omitempty"` // diff unificado (sólo text)
+}
+
+// Entry es un tiddler añadido, eliminado, renombrado o modificado.
+type Entry struct {
+	Kind    string   `json:"kind"`
+	Title   string   `json:"title"`
+	From    string   `json:"from,omitempty"` // título anterior (renamed)
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Result es el diff completo.  Entries va agrupado por tipo (added, removed,
+// renamed, modified) y ordenado por título dentro de cada grupo.
+type Result struct {
+	Before    string  `json:"before"`
+	After     string  `json:"after"`
+	Added     int     `json:"added"`
+	Removed   int     `json:"removed"`
+	Renamed   int     `json:"renamed"`
+	Modified  int     `json:"modified"`
+	Unchanged int     `json:"unchanged"`
+	Entries   []Entry `json:"entries"`
+}
+
+// Empty indica si no hubo ninguna diferencia.
+func (r Result) Empty() bool { return len(r.Entries) == 0 }
+
+// Options ajusta Compare.
+type Options struct {
+	Before, After string // nombres de las entradas (cabeceras del diff unificado)
+	Context       int    // líneas de contexto del diff unificado
+}
+
+// Compare calcula las diferencias de before a after.
+func Compare(before, after []models.Tiddler, opts Options) (Result, error) {
+	res := Result{Before: opts.Before, After: opts.After, Entries: []Entry{}}
+	old, cur := index(before), index(after)
+
+	var added, removed, common []string
+	for title := range cur {
+		if _, ok := old[title]; ok {
+			common = append(common, title)
+		} else {
+			added = append(added, title)
+		}
+	}
+	for title := range old {
+		if _, ok := cur[title]; !ok {
+			removed = append(removed, title)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(common)
+
+	// Renombrados: cada eliminado se empareja con el primer añadido (en
+	// orden de título) que tenga el mismo contenido.
+	byHash := make(map[string][]string)
+	for _, title := range added {
+		h := dedup.HashContent(cur[title])
+		byHash[h] = append(byHash[h], title)
+	}
+	var renamed []Entry
+	renamedFrom := make(map[string]bool)
+	renamedTo := make(map[string]bool)
+	for _, title := range removed {
+		h := dedup.HashContent(old[title])
+		if len(byHash[h]) == 0 {
+			continue
+		}
+		to := byHash[h][0]
+		byHash[h] = byHash[h][1:]
+		changes, err := fieldChanges(old[title], cur[to], opts)
+		if err != nil {
+			return res, err
+		}
+		renamed = append(renamed, Entry{Kind: Renamed, Title: to, From: title, Changes: changes})
+		renamedFrom[title] = true
+		renamedTo[to] = true
+	}
+
+	for _, title := range added {
+		if !renamedTo[title] {
+			res.Entries = append(res.Entries, Entry{Kind: Added, Title: title})
+			res.Added++
+		}
+	}
+	for _, title := range removed {
+		if !renamedFrom[title] {
+			res.Entries = append(res.Entries, Entry{Kind: Removed, Title: title})
+			res.Removed++
+		}
+	}
+	res.Entries = append(res.Entries, renamed...)
+	res.Renamed = len(renamed)
+
+	for _, title := range common {
+		changes, err := fieldChanges(old[title], cur[title], opts)
+		if err != nil {
+			return res, err
+		}
+		if len(changes) == 0 {
+			res.Unchanged++
+			continue
+		}
+		res.Entries = append(res.Entries, Entry{Kind: Modified, Title: title, Changes: changes})
+		res.Modified++
+	}
+	return res, nil
+}
+
+// fieldChanges devuelve los campos distintos entre a y b (sin contar el
+// título) y el diff unificado del texto.
+func fieldChanges(a, b models.Tiddler, opts Options) ([]Change, error) {
+	losses, err := roundtrip.Compare(a, b)
+	if err != nil {
+		return nil, fmt.Errorf("comparando %q: %w", a.Title, err)
+	}
+	var changes []Change
+	for _, l := range losses {
+		if l.Field == "title" {
+			continue
+		}
+		c := Change{Field: l.Field, Before: l.Original, After: l.Restored}
+		if l.Field == "text" {
+			c.Diff = Unified(a.Text, b.Text, opts.Before+"/"+a.Title, opts.After+"/"+b.Title, opts.Context)
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// index indexa por título; ante títulos repetidos gana el primero.
+func index(ts []models.Tiddler) map[string]models.Tiddler {
+	m := make(map[string]models.Tiddler, len(ts))
+	for _, t := range ts {
+		if _, dup := m[t.Title]; !dup {
+			m[t.Title] = t
+		}
+	}
+	return m
+}
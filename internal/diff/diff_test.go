@@ -0,0 +1,124 @@
+// internal/diff/diff_test.go – Tests de Unified, Compare y los formatos
+// --------------------------------------------------------------------------------
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestUnified(t *testing.T) {
+	a := "uno\ndos\ntres\ncuatro\ncinco\nseis\nsiete\nocho"
+	b := "uno\nDOS\ntres\ncuatro\ncinco\nseis\nsiete\nocho\nnueve"
+	got := Unified(a, b, "antes", "después", 1)
+	want := "--- antes\n+++ después\n" +
+		"@@ -1,3 +1,3 @@\n uno\n-dos\n+DOS\n tres\n" +
+		"@@ -8 +8,2 @@\n ocho\n+nueve\n"
+	if got != want {
+		t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+	}
+	if got := Unified(a, a, "x", "y", 3); got != "" {
+		t.Errorf("textos iguales: %q, want vacío", got)
+	}
+	if got := Unified("", "hola", "x", "y", 3); !strings.Contains(got, "@@ -0,0 +1 @@\n+hola\n") {
+		t.Errorf("texto nuevo: %q", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := []models.Tiddler{
+		{Title: "Igual", Text: "x", Type: "text/plain"},
+		{Title: "Borrado", Text: "adiós", Type: "text/plain"},
+		{Title: "Viejo", Text: "contenido movido", Type: "text/plain", Tags: "[[a]]"},
+		{Title: "Cambiado", Text: "uno\ndos", Type: "text/plain", Tags: "[[a]]", Color: "red"},
+	}
+	after := []models.Tiddler{
+		{Title: "Igual", Text: "x", Type: "text/plain"},
+		{Title: "Nuevo", Text: "hola", Type: "text/plain"},
+		{Title: "Renombrado", Text: "contenido movido", Type: "text/plain", Tags: "[[a]]", Modified: "20250101000000"},
+		{Title: "Cambiado", Text: "uno\nDOS", Type: "text/plain", Tags: "[[a]] [[b]]", Color: "blue"},
+	}
+	res, err := Compare(before, after, Options{Before: "a", After: "b", Context: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Added != 1 || res.Removed != 1 || res.Renamed != 1 || res.Modified != 1 || res.Unchanged != 1 {
+		t.Fatalf("contadores = %+v", res)
+	}
+	kinds := []string{}
+	for _, e := range res.Entries {
+		kinds = append(kinds, e.Kind+":"+e.Title)
+	}
+	want := "added:Nuevo removed:Borrado renamed:Renombrado modified:Cambiado"
+	if got := strings.Join(kinds, " "); got != want {
+		t.Errorf("entradas = %s, want %s", got, want)
+	}
+
+	ren := res.Entries[2]
+	if ren.From != "Viejo" || len(ren.Changes) != 1 || ren.Changes[0].Field != "modified" {
+		t.Errorf("renombrado = %+v", ren)
+	}
+	fields := map[string]Change{}
+	for _, c := range res.Entries[3].Changes {
+		fields[c.Field] = c
+	}
+	if len(fields) != 3 {
+		t.Errorf("campos cambiados = %v, want color, tags y text", fields)
+	}
+	if c := fields["color"]; c.Before != "red" || c.After != "blue" {
+		t.Errorf("color = %+v", c)
+	}
+	if c := fields["text"]; !strings.Contains(c.Diff, "-dos\n+DOS\n") || !strings.HasPrefix(c.Diff, "--- a/Cambiado\n+++ b/Cambiado\n") {
+		t.Errorf("diff de text = %q", c.Diff)
+	}
+	if _, ok := fields["tags"]; !ok {
+		t.Errorf("falta el cambio de tags")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	before := []models.Tiddler{{Title: "A", Text: "uno"}, {Title: "B", Text: "b"}}
+	after := []models.Tiddler{{Title: "A", Text: "dos"}, {Title: "C", Text: "c"}}
+	res, err := Compare(before, after, Options{Before: "antes", After: "despues"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var text bytes.Buffer
+	if err := Write(&text, res, "text"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"+ C\n", "- B\n", "~ A\n", "      -uno\n", "📊 1 añadidos, 1 eliminados, 0 renombrados, 1 modificados"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("texto sin %q:\n%s", want, text.String())
+		}
+	}
+
+	var md bytes.Buffer
+	if err := Write(&md, res, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Añadidos\n\n- `C`\n", "### `A`\n", "```diff\n--- antes/A\n"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown sin %q:\n%s", want, md.String())
+		}
+	}
+
+	var js bytes.Buffer
+	if err := Write(&js, res, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var back Result
+	if err := json.Unmarshal(js.Bytes(), &back); err != nil || len(back.Entries) != 3 {
+		t.Errorf("json = %s (%v)", js.String(), err)
+	}
+
+	if err := Write(&js, res, "html"); err == nil {
+		t.Error("formato desconocido sin error")
+	}
+}
@@ -0,0 +1,146 @@
+// internal/diff/format.go – Presentación del diff: texto, JSON y Markdown
+// --------------------------------------------------------------------------------
+//   text      → una línea por tiddler (+ - → ~), los campos cambiados debajo y
+//               el diff unificado del texto; pensado para la terminal.
+//   json      → el Result tal cual, para scripts y pipelines.
+//   markdown  → resumen en tabla y una sección por tiddler, para pegar en un
+//               issue o en un PR.
+// --------------------------------------------------------------------------------
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats son los formatos admitidos por Write.
+var Formats = []string{"text", "json", "markdown"}
+
+// Write escribe res en w con el formato indicado.
+func Write(w io.Writer, res Result, format string) error {
+	switch format {
+	case "text":
+		return writeText(w, res)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(res)
+	case "markdown":
+		return writeMarkdown(w, res)
+	}
+	return fmt.Errorf("formato desconocido: %q (usa %s)", format, strings.Join(Formats, ", "))
+}
+
+var textMarks = map[string]string{Added: "+", Removed: "-", Renamed: "→", Modified: "~"}
+
+func writeText(w io.Writer, res Result) error {
+	var sb strings.Builder
+	for _, e := range res.Entries {
+		if e.Kind == Renamed {
+			fmt.Fprintf(&sb, "→ %s → %s\n", e.From, e.Title)
+		} else {
+			fmt.Fprintf(&sb, "%s %s\n", textMarks[e.Kind], e.Title)
+		}
+		for _, c := range e.Changes {
+			if c.Diff != "" {
+				fmt.Fprintf(&sb, "    %s:\n", c.Field)
+				for _, line := range strings.Split(strings.TrimSuffix(c.Diff, "\n"), "\n") {
+					fmt.Fprintf(&sb, "      %s\n", line)
+				}
+				continue
+			}
+			fmt.Fprintf(&sb, "    %s: %s → %s\n", c.Field, value(c.Before), value(c.After))
+		}
+	}
+	fmt.Fprintf(&sb, "📊 %d añadidos, %d eliminados, %d renombrados, %d modificados, %d sin cambios\n",
+		res.Added, res.Removed, res.Renamed, res.Modified, res.Unchanged)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMarkdown(w io.Writer, res Result) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Diferencias: `%s` → `%s`\n\n", res.Before, res.After)
+	sb.WriteString("| Añadidos | Eliminados | Renombrados | Modificados | Sin cambios |\n")
+	sb.WriteString("|---------:|-----------:|------------:|------------:|------------:|\n")
+	fmt.Fprintf(&sb, "| %d | %d | %d | %d | %d |\n", res.Added, res.Removed, res.Renamed, res.Modified, res.Unchanged)
+
+	// Cada bloque siguiente empieza con una línea en blanco: así nunca quedan
+	// dos seguidas.
+	sections := []struct{ kind, heading string }{
+		{Added, "Añadidos"}, {Removed, "Eliminados"}, {Renamed, "Renombrados"}, {Modified, "Modificados"},
+	}
+	for _, s := range sections {
+		var entries []Entry
+		for _, e := range res.Entries {
+			if e.Kind == s.kind {
+				entries = append(entries, e)
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n", s.heading)
+		if s.kind == Added || s.kind == Removed {
+			sb.WriteString("\n")
+			for _, e := range entries {
+				fmt.Fprintf(&sb, "- `%s`\n", e.Title)
+			}
+			continue
+		}
+		for _, e := range entries {
+			if e.Kind == Renamed {
+				fmt.Fprintf(&sb, "\n### `%s` → `%s`\n", e.From, e.Title)
+			} else {
+				fmt.Fprintf(&sb, "\n### `%s`\n", e.Title)
+			}
+			writeMarkdownChanges(&sb, e.Changes)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMarkdownChanges(sb *strings.Builder, changes []Change) {
+	var diffs []Change
+	rows := 0
+	for _, c := range changes {
+		if c.Diff != "" {
+			diffs = append(diffs, c)
+			continue
+		}
+		if rows == 0 {
+			sb.WriteString("\n| Campo | Antes | Después |\n|-------|-------|---------|\n")
+		}
+		rows++
+		fmt.Fprintf(sb, "| `%s` | %s | %s |\n", c.Field, cell(c.Before), cell(c.After))
+	}
+	for _, c := range diffs {
+		fmt.Fprintf(sb, "\n```diff\n%s```\n", c.Diff)
+	}
+}
+
+// value presenta un valor de campo en una línea: strings entre comillas, el
+// resto como JSON y ∅ para un campo ausente.
+func value(v any) string {
+	if v == nil {
+		return "∅"
+	}
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// cell es value escapado para una celda de tabla Markdown.
+func cell(v any) string {
+	s := strings.ReplaceAll(value(v), "|", `\|`)
+	return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+}
@@ -0,0 +1,184 @@
+// internal/diff/lines.go – Comparación de textos línea a línea
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Tanto `diff` (diff unificado de los textos) como merge.Diff3 (fusión de tres
+// vías) necesitan lo mismo: alinear dos versiones de un texto por líneas.
+// Match calcula una subsecuencia común más larga (LCS) y dice con qué línea
+// de b se empareja cada línea de a; Unified la presenta al estilo `diff -u`:
+//
+//   --- antes
+//   +++ después
+//   @@ -1,3 +1,3 @@
+//    uno
+//   -dos
+//   +DOS
+//    tres
+// --------------------------------------------------------------------------------
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxLCSCells limita la tabla de la subsecuencia común (n×m celdas): más allá
+// el bloque central se trata como un único cambio.
+const maxLCSCells = 4_000_000
+
+// SplitLines separa s en líneas; "" es un texto sin líneas.
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Match devuelve, para cada línea de a, el índice de la línea de b con la que
+// se empareja en una subsecuencia común más larga, o -1.
+func Match(a, b []string) []int {
+	m := make([]int, len(a))
+	for i := range m {
+		m[i] = -1
+	}
+	// Prefijo y sufijo comunes no necesitan la tabla.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		m[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		m[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(ma) == 0 || len(mb) == 0 || len(ma)*len(mb) > maxLCSCells {
+		return m
+	}
+
+	// lcs[x][y] = longitud de la subsecuencia común de ma[x:] y mb[y:].
+	w := len(mb) + 1
+	lcs := make([]int32, (len(ma)+1)*w)
+	for x := len(ma) - 1; x >= 0; x-- {
+		for y := len(mb) - 1; y >= 0; y-- {
+			if ma[x] == mb[y] {
+				lcs[x*w+y] = lcs[(x+1)*w+y+1] + 1
+			} else if lcs[(x+1)*w+y] >= lcs[x*w+y+1] {
+				lcs[x*w+y] = lcs[(x+1)*w+y]
+			} else {
+				lcs[x*w+y] = lcs[x*w+y+1]
+			}
+		}
+	}
+	for x, y := 0, 0; x < len(ma) && y < len(mb); {
+		switch {
+		case ma[x] == mb[y]:
+			m[pre+x] = pre + y
+			x, y = x+1, y+1
+		case lcs[(x+1)*w+y] >= lcs[x*w+y+1]:
+			x++
+		default:
+			y++
+		}
+	}
+	return m
+}
+
+// op es una línea del diff: ' ' igual, '-' sólo en a, '+' sólo en b.
+type op struct {
+	kind byte
+	line string
+	a, b int // posición (desde 0) en a y en b antes de esta línea
+}
+
+// ops alinea a y b con Match y devuelve la secuencia de operaciones.
+func ops(a, b []string) []op {
+	m := Match(a, b)
+	var out []op
+	j := 0
+	for i, line := range a {
+		if m[i] < 0 {
+			out = append(out, op{'-', line, i, j})
+			continue
+		}
+		for ; j < m[i]; j++ {
+			out = append(out, op{'+', b[j], i, j})
+		}
+		out = append(out, op{' ', line, i, j})
+		j++
+	}
+	for ; j < len(b); j++ {
+		out = append(out, op{'+', b[j], len(a), j})
+	}
+	return out
+}
+
+// Unified devuelve el diff unificado de a → b con context líneas de
+// contexto, o "" si los textos son iguales.
+func Unified(a, b, nameA, nameB string, context int) string {
+	if a == b {
+		return ""
+	}
+	all := ops(SplitLines(a), SplitLines(b))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+	for start := 0; start < len(all); {
+		// Siguiente cambio.
+		first := start
+		for first < len(all) && all[first].kind == ' ' {
+			first++
+		}
+		if first == len(all) {
+			break
+		}
+		// El hunk se extiende mientras los cambios estén a menos de
+		// 2*context líneas iguales entre sí.
+		end, equal := first, 0
+		for n := first; n < len(all); n++ {
+			if all[n].kind == ' ' {
+				equal++
+				if equal > 2*context {
+					break
+				}
+				continue
+			}
+			equal = 0
+			end = n + 1
+		}
+		from := max(first-context, start)
+		to := min(end+context, len(all))
+
+		lenA, lenB := 0, 0
+		for _, o := range all[from:to] {
+			if o.kind != '+' {
+				lenA++
+			}
+			if o.kind != '-' {
+				lenB++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(all[from].a, lenA), hunkRange(all[from].b, lenB))
+		for _, o := range all[from:to] {
+			sb.WriteByte(o.kind)
+			sb.WriteString(o.line)
+			sb.WriteByte('\n')
+		}
+		start = to
+	}
+	return sb.String()
+}
+
+// hunkRange escribe "inicio,largo" como diff -u (inicio desde 1; un rango
+// vacío se indica con la línea anterior).
+func hunkRange(pos, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, n)
+}
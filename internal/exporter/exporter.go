@@ -6,7 +6,10 @@ import (
 	"os"
 )
 
-// WriteJSON vuelca “v” a disco en outputPath como JSON.
+// Stdio es la ruta de salida que WriteJSON y WriteJSONL interpretan como stdout.
+const Stdio = "-"
+
+// WriteJSON vuelca “v” a disco en outputPath como JSON ("-" → stdout).
 // Si pretty es true, usa indentación de 2 espacios; si no, JSON compacto.
 func WriteJSON(outputPath string, v any, pretty bool) error {
 	var (
@@ -21,6 +24,10 @@ func WriteJSON(outputPath string, v any, pretty bool) error {
 	if err != nil {
 		return fmt.Errorf("marshal JSON: %w", err)
 	}
+	if outputPath == Stdio {
+		_, err = os.Stdout.Write(append(data, '\n'))
+		return err
+	}
 	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
 		return fmt.Errorf("write file %s: %w", outputPath, err)
 	}
@@ -6,11 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/xitongsys/parquet-go-source/local"
-	"github.com/xitongsys/parquet-go/parquet"
-	"github.com/xitongsys/parquet-go/writer"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
 )
 
 // ParquetNode representa el esquema Parquet alineado al diseño semántico.
@@ -23,6 +22,44 @@ type ParquetNode struct {
 	Requiere     string `parquet:"name=requiere, type=BYTE_ARRAY, convertedtype=UTF8"`
 	IsAIReady    bool   `parquet:"name=is_ai_ready, type=BOOLEAN"`
 	HasRelations bool   `parquet:"name=has_relations, type=BOOLEAN"`
+	Fields       string `parquet:"name=fields, type=BYTE_ARRAY, convertedtype=UTF8"` // campos personalizados (objeto JSON)
+	Kind         string `parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	IsSystem     bool   `parquet:"name=is_system, type=BOOLEAN"`
+	Schema       string `parquet:"name=schema_version, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+}
+
+// ParquetSection es una fila de la tabla de secciones (<base>_sections.parquet):
+// una por models.Section de cada registro v2, enlazada al nodo por id.
+type ParquetSection struct {
+	ID    string `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Index int32  `parquet:"name=index, type=INT32"`
+	Name  string `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Value string `parquet:"name=value, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// SectionsPath devuelve la ruta de la tabla de secciones que acompaña al
+// Parquet path: "out.parquet" → "out_sections.parquet".
+func SectionsPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "_sections.parquet"
+}
+
+// MapRecordToSections devuelve las filas de content.sections del registro
+// (sólo v2 las trae), en su orden.
+func MapRecordToSections(m map[string]interface{}) []ParquetSection {
+	content, _ := m["content"].(map[string]interface{})
+	list, _ := content["sections"].([]interface{})
+	id := fmt.Sprint(m["id"])
+	var out []ParquetSection
+	for i, item := range list {
+		s, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := s["name"].(string)
+		value, _ := s["value"].(string)
+		out = append(out, ParquetSection{ID: id, Index: int32(i), Name: name, Value: value})
+	}
+	return out
 }
 
 // MapRecordToParquet convierte un registro JSONL genérico a ParquetNode.
@@ -72,6 +109,15 @@ func MapRecordToParquet(m map[string]interface{}) ParquetNode {
 	isAIReady := getStr("id") != "" && getStr("rol") != "" && getStr("contentPlain") != ""
 	// has_relations: define o requiere no vacío
 	hasRelations := define != "" || requiere != ""
+	// schema_version: el declarado o, en JSONL anteriores, el deducido
+	schema := transform.DetectSchema(m)
+	// kind e is_system: en la raíz (v1, v3, híbrido) o en meta (v2)
+	kind, _ := m["kind"].(string)
+	isSystem, _ := m["is_system"].(bool)
+	if meta, ok := m["meta"].(map[string]interface{}); ok && kind == "" {
+		kind, _ = meta["kind"].(string)
+		isSystem, _ = meta["is_system"].(bool)
+	}
 
 	return ParquetNode{
 		ID:           getStr("id"),
@@ -82,7 +128,38 @@ func MapRecordToParquet(m map[string]interface{}) ParquetNode {
 		Requiere:     requiere,
 		IsAIReady:    isAIReady,
 		HasRelations: hasRelations,
+		Fields:       customFieldsJSON(m),
+		Kind:         kind,
+		IsSystem:     isSystem,
+		Schema:       schema,
+	}
+}
+
+// customFieldsJSON devuelve los campos personalizados del registro como objeto
+// JSON (claves ordenadas), o "" si no hay.  v1, v3 e híbrido los traen en
+// "fields"; v2, en meta.extra junto a tmap.id y source_wiki.
+func customFieldsJSON(m map[string]interface{}) string {
+	fields, _ := m["fields"].(map[string]interface{})
+	if fields == nil {
+		if meta, ok := m["meta"].(map[string]interface{}); ok {
+			if extra, ok := meta["extra"].(map[string]interface{}); ok {
+				fields = make(map[string]interface{}, len(extra))
+				for k, v := range extra {
+					if k != "tmap.id" && k != "source_wiki" {
+						fields[k] = v
+					}
+				}
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ""
 	}
+	return string(data)
 }
 
 // flattenListFromAny convierte cualquier lista a string separado por coma.
@@ -101,28 +178,27 @@ func flattenListFromAny(val interface{}) string {
 }
 
 // ConvertJSONLToParquet convierte un archivo .jsonl a .parquet alineado al diseño semántico.
-func ConvertJSONLToParquet(inputPath string, outputPath string) error {
+// Si los registros traen content.sections (v2), escribe además la tabla
+// SectionsPath(outputPath).
+func ConvertJSONLToParquet(inputPath string, outputPath string) (err error) {
 	f, err := os.Open(inputPath)
 	if err != nil {
 		return fmt.Errorf("abrir input: %w", err)
 	}
 	defer f.Close()
 
-	// Crear archivo Parquet
-	fw, err := local.NewLocalFileWriter(outputPath)
+	pw, err := NewParquetWriter(outputPath)
 	if err != nil {
-		return fmt.Errorf("crear parquet: %w", err)
+		return err
 	}
-	defer fw.Close()
-
-	pw, err := writer.NewParquetWriter(fw, new(ParquetNode), 4)
-	if err != nil {
-		return fmt.Errorf("parquet writer: %w", err)
-	}
-	pw.RowGroupSize = 128 * 1024 * 1024 // 128MB
-	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	defer func() {
+		if cerr := pw.Close(); err == nil {
+			err = cerr
+		}
+	}()
 
 	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
 	count := 0
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -131,18 +207,12 @@ func ConvertJSONLToParquet(inputPath string, outputPath string) error {
 		}
 		count++
 
-		// DEBUG: Imprimir línea problemática
-		if count >= 30 && count <= 40 {
-			fmt.Printf("DEBUG línea %d: %s\n", count, line[:min(100, len(line))])
-		}
-
 		var m map[string]interface{}
 		if err := json.Unmarshal([]byte(line), &m); err != nil {
-			fmt.Printf("ERROR línea %d: %s\n", count, line)
+			fmt.Fprintf(os.Stderr, "ERROR línea %d: %s\n", count, line)
 			return fmt.Errorf("jsonl línea %d: %w", count, err)
 		}
-		node := MapRecordToParquet(m)
-		if err := pw.Write(node); err != nil {
+		if err := pw.Write(m); err != nil {
 			return fmt.Errorf("escribiendo parquet línea %d: %w", count, err)
 		}
 	}
@@ -152,16 +222,9 @@ func ConvertJSONLToParquet(inputPath string, outputPath string) error {
 	if count == 0 {
 		return errors.New("no se encontraron registros en el JSONL")
 	}
-	if err := pw.WriteStop(); err != nil {
-		return fmt.Errorf("cerrar parquet: %w", err)
+	fmt.Fprintf(os.Stderr, "✅ Exportación Parquet completada: %d registros → %s\n", count, outputPath)
+	if pw.sections != nil {
+		fmt.Fprintf(os.Stderr, "📑 Secciones → %s\n", SectionsPath(outputPath))
 	}
-	fmt.Printf("✅ Exportación Parquet completada: %d registros → %s\n", count, outputPath)
 	return nil
 }
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,429 @@
+// internal/exporter/recordwriter.go – Escritura de registros de a uno
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// WriteJSONL recibe el slice completo de registros (via reflect), por lo que
+// todo el export debe estar en memoria antes de escribir la primera línea.
+// RecordWriter invierte el flujo: el pipeline entrega cada registro en cuanto
+// lo convierte y el writer lo serializa de inmediato.
+//
+//   w, _ := exporter.NewRecordWriter("out.csv", exporter.FormatCSV, false)
+//   for _, t := range tiddlers { w.Write(transform.ConvertTiddlerV3(t)) }
+//   w.Close()   // siempre: completa el formato (']' del array, pie Parquet…)
+//
+// Implementaciones:
+//   • JSONLWriter      → un objeto por línea (o indentado con pretty).
+//   • JSONArrayWriter  → array JSON `[ … ]` escrito incrementalmente.
+//   • CSVWriter        → una fila por registro; columnas del primer registro.
+//   • ParquetWriter    → esquema ParquetNode (ver parquet.go), más la tabla
+//                        ParquetSection si los registros traen secciones.
+// --------------------------------------------------------------------------------
+
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// RecordWriter serializa registros (models.Record, models.RecordV2,
+// map[string]any…) de a uno.  Close debe llamarse siempre para completar
+// el formato y liberar el destino.
+type RecordWriter interface {
+	Write(rec any) error
+	Close() error
+}
+
+// Formatos de salida admitidos por NewRecordWriter.
+const (
+	FormatJSONL   = "jsonl"
+	FormatJSON    = "json"
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// NewRecordWriter crea el archivo path (o usa stdout si path es "-") y
+// devuelve el writer del formato indicado.  pretty sólo afecta a JSONL y JSON.
+func NewRecordWriter(path, format string, pretty bool) (RecordWriter, error) {
+	switch format {
+	case FormatJSONL, FormatJSON, FormatCSV:
+	case FormatParquet:
+		if path == Stdio {
+			return nil, fmt.Errorf("el formato parquet no puede escribirse en stdout")
+		}
+		if err := ensureDir(path); err != nil {
+			return nil, err
+		}
+		return NewParquetWriter(path)
+	default:
+		return nil, fmt.Errorf("formato de salida desconocido: %q (usa jsonl, json, csv o parquet)", format)
+	}
+
+	var out io.Writer = os.Stdout
+	var closer io.Closer
+	if path != Stdio {
+		if err := ensureDir(path); err != nil {
+			return nil, err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("crear '%s': %w", path, err)
+		}
+		out, closer = f, f
+	}
+
+	switch format {
+	case FormatJSON:
+		return &JSONArrayWriter{w: bufio.NewWriter(out), closer: closer, pretty: pretty}, nil
+	case FormatCSV:
+		return &CSVWriter{w: csv.NewWriter(out), closer: closer}, nil
+	default:
+		return &JSONLWriter{w: bufio.NewWriter(out), closer: closer, pretty: pretty}, nil
+	}
+}
+
+func ensureDir(path string) error {
+	dir := filepath.Dir(path)
+	if dir == "" || dir == "." {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("mkdirall '%s': %w", dir, err)
+	}
+	return nil
+}
+
+// closeAll cierra closer (si existe) conservando el primer error.
+func closeAll(err error, closer io.Closer) error {
+	if closer == nil {
+		return err
+	}
+	if cerr := closer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// -----------------------------------------------------------------------------
+// JSONL
+// -----------------------------------------------------------------------------
+
+// JSONLWriter escribe un objeto JSON por línea.
+type JSONLWriter struct {
+	w      *bufio.Writer
+	closer io.Closer
+	pretty bool
+}
+
+// NewJSONLWriter escribe en w; Close vacía el buffer pero no cierra w.
+func NewJSONLWriter(w io.Writer, pretty bool) *JSONLWriter {
+	return &JSONLWriter{w: bufio.NewWriter(w), pretty: pretty}
+}
+
+func (j *JSONLWriter) Write(rec any) error {
+	line, err := marshal(rec, j.pretty)
+	if err != nil {
+		return err
+	}
+	if _, err := j.w.Write(line); err != nil {
+		return err
+	}
+	return j.w.WriteByte('\n')
+}
+
+func (j *JSONLWriter) Close() error {
+	return closeAll(j.w.Flush(), j.closer)
+}
+
+// -----------------------------------------------------------------------------
+// Array JSON
+// -----------------------------------------------------------------------------
+
+// JSONArrayWriter escribe `[`, los registros separados por comas y `]` al cerrar.
+type JSONArrayWriter struct {
+	w      *bufio.Writer
+	closer io.Closer
+	pretty bool
+	n      int
+}
+
+// NewJSONArrayWriter escribe en w; Close cierra el array pero no cierra w.
+func NewJSONArrayWriter(w io.Writer, pretty bool) *JSONArrayWriter {
+	return &JSONArrayWriter{w: bufio.NewWriter(w), pretty: pretty}
+}
+
+func (j *JSONArrayWriter) Write(rec any) error {
+	data, err := marshal(rec, j.pretty)
+	if err != nil {
+		return err
+	}
+	sep := ","
+	if j.n == 0 {
+		sep = "["
+	}
+	if j.pretty {
+		sep += "\n  "
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\n  "))
+	}
+	if _, err := j.w.WriteString(sep); err != nil {
+		return err
+	}
+	j.n++
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *JSONArrayWriter) Close() error {
+	end := "]\n"
+	switch {
+	case j.n == 0:
+		end = "[]\n"
+	case j.pretty:
+		end = "\n]\n"
+	}
+	_, err := j.w.WriteString(end)
+	if ferr := j.w.Flush(); err == nil {
+		err = ferr
+	}
+	return closeAll(err, j.closer)
+}
+
+// -----------------------------------------------------------------------------
+// CSV
+// -----------------------------------------------------------------------------
+
+// CSVWriter escribe una fila por registro.  Las columnas salen del primer
+// registro: los campos JSON de un struct (en orden de declaración), todas las
+// claves posibles de v3 (transform.V3Keys) o, en otro mapa, sus claves
+// ordenadas.  Los valores anidados (listas, objetos) se escriben como JSON
+// dentro de la celda y las claves ausentes quedan vacías.  Un registro con
+// una clave que no tiene columna es un error: no se descarta en silencio.
+type CSVWriter struct {
+	w       *csv.Writer
+	closer  io.Closer
+	columns []string
+	known   map[string]bool // columns, para comprobar cada registro
+}
+
+// NewCSVWriter escribe en w; Close vacía el buffer pero no cierra w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+func (c *CSVWriter) Write(rec any) error {
+	m, err := toMap(rec)
+	if err != nil {
+		return err
+	}
+	if c.columns == nil {
+		c.columns = columnsOf(rec, m)
+		c.known = make(map[string]bool, len(c.columns))
+		for _, col := range c.columns {
+			c.known[col] = true
+		}
+		if err := c.w.Write(c.columns); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		row[i], err = cell(m[col])
+		if err != nil {
+			return fmt.Errorf("columna %s: %w", col, err)
+		}
+	}
+	for k := range m {
+		if !c.known[k] {
+			return fmt.Errorf("la clave %q no tiene columna en el CSV (columnas: %s)", k, strings.Join(c.columns, ","))
+		}
+	}
+	return c.w.Write(row)
+}
+
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return closeAll(c.w.Error(), c.closer)
+}
+
+// columnsOf devuelve los nombres JSON de los campos de un struct,
+// transform.V3Keys si m declara schema_version v3, o las claves ordenadas de
+// m en otro caso.
+func columnsOf(rec any, m map[string]any) []string {
+	t := reflect.TypeOf(rec)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var cols []string
+	if t != nil && t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			switch name {
+			case "-":
+				continue
+			case "":
+				name = f.Name
+			}
+			cols = append(cols, name)
+		}
+		return cols
+	}
+	if version, _ := transform.DeclaredSchema(m); version == models.SchemaV3 {
+		return append(cols, transform.V3Keys...)
+	}
+	for k := range m {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+func cell(v any) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case json.Number:
+		return vv.String(), nil
+	case bool:
+		return fmt.Sprint(vv), nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// -----------------------------------------------------------------------------
+// Parquet
+// -----------------------------------------------------------------------------
+
+// ParquetWriter convierte cada registro a ParquetNode (MapRecordToParquet) y
+// lo agrega al archivo.  Los row groups se vuelcan a disco a medida que se
+// llenan, por lo que la memoria queda acotada por RowGroupSize.
+//
+// Las secciones (content.sections de v2) van a una segunda tabla,
+// SectionsPath(path), que se crea con la primera sección: un export sin
+// secciones no deja un archivo vacío.
+type ParquetWriter struct {
+	path     string
+	nodes    *parquetTable
+	sections *parquetTable
+}
+
+// parquetTable es un archivo Parquet abierto con su writer.
+type parquetTable struct {
+	file source.ParquetFile
+	pw   *writer.ParquetWriter
+}
+
+// NewParquetWriter crea el archivo Parquet en path.
+func NewParquetWriter(path string) (*ParquetWriter, error) {
+	t, err := newParquetTable(path, new(ParquetNode))
+	if err != nil {
+		return nil, err
+	}
+	return &ParquetWriter{path: path, nodes: t}, nil
+}
+
+func newParquetTable(path string, schema any) (*parquetTable, error) {
+	fw, err := local.NewLocalFileWriter(path)
+	if err != nil {
+		return nil, fmt.Errorf("crear parquet: %w", err)
+	}
+	pw, err := writer.NewParquetWriter(fw, schema, 4)
+	if err != nil {
+		fw.Close()
+		return nil, fmt.Errorf("parquet writer: %w", err)
+	}
+	pw.RowGroupSize = 128 * 1024 * 1024 // 128MB
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+	return &parquetTable{file: fw, pw: pw}, nil
+}
+
+func (p *ParquetWriter) Write(rec any) error {
+	m, err := toMap(rec)
+	if err != nil {
+		return err
+	}
+	if err := p.nodes.pw.Write(MapRecordToParquet(m)); err != nil {
+		return err
+	}
+	for _, s := range MapRecordToSections(m) {
+		if p.sections == nil {
+			if p.sections, err = newParquetTable(SectionsPath(p.path), new(ParquetSection)); err != nil {
+				return err
+			}
+		}
+		if err := p.sections.pw.Write(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ParquetWriter) Close() error {
+	err := p.nodes.close()
+	if p.sections != nil {
+		if serr := p.sections.close(); err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+func (t *parquetTable) close() error {
+	err := t.pw.WriteStop()
+	if err != nil {
+		err = fmt.Errorf("cerrar parquet: %w", err)
+	}
+	return closeAll(err, t.file)
+}
+
+// -----------------------------------------------------------------------------
+// Utilidades
+// -----------------------------------------------------------------------------
+
+func marshal(rec any, pretty bool) ([]byte, error) {
+	if pretty {
+		return json.MarshalIndent(rec, "", "  ")
+	}
+	return json.Marshal(rec)
+}
+
+// toMap devuelve rec como map[string]any; los structs pasan por JSON para
+// respetar sus tags.  Los números se conservan como json.Number.
+func toMap(rec any) (map[string]any, error) {
+	if m, ok := rec.(map[string]any); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("el registro no es un objeto JSON: %w", err)
+	}
+	return m, nil
+}
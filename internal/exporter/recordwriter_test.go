@@ -0,0 +1,210 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestJSONArrayWriter(t *testing.T) {
+	for _, pretty := range []bool{false, true} {
+		var buf bytes.Buffer
+		w := NewJSONArrayWriter(&buf, pretty)
+		for _, id := range []string{"A", "B"} {
+			if err := w.Write(models.Record{ID: id, Tags: []string{"x"}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		var got []models.Record
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("pretty=%v: array inválido: %v\n%s", pretty, err, buf.String())
+		}
+		if len(got) != 2 || got[1].ID != "B" {
+			t.Errorf("pretty=%v: got %+v", pretty, got)
+		}
+	}
+
+	var empty bytes.Buffer
+	w := NewJSONArrayWriter(&empty, false)
+	if err := w.Close(); err != nil || strings.TrimSpace(empty.String()) != "[]" {
+		t.Errorf("array vacío = %q (%v)", empty.String(), err)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf)
+	recs := []models.Record{
+		{SchemaVersion: "v1", ID: "A", Tags: []string{"x", "y"}, TextPlain: "hola, \"mundo\"", Kind: "content"},
+		{SchemaVersion: "v1", ID: "B", Color: "red", Kind: "content", Fields: map[string]any{"status": "draft"}},
+	}
+	for _, r := range recs {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "schema_version,id,tags,type,textMarkdown,textPlain,createdAt,modifiedAt,color,source_wiki,kind,is_system,fields" {
+		t.Errorf("encabezado = %q", lines[0])
+	}
+	if lines[1] != `v1,A,"[""x"",""y""]",,,"hola, ""mundo""",,,,,content,false,` {
+		t.Errorf("fila A = %q", lines[1])
+	}
+	if lines[2] != `v1,B,,,,,,,red,,content,false,"{""status"":""draft""}"` {
+		t.Errorf("fila B = %q (los campos omitempty deben quedar en su columna)", lines[2])
+	}
+
+	// Con mapas las columnas son las claves ordenadas del primer registro.
+	buf.Reset()
+	w = NewCSVWriter(&buf)
+	w.Write(map[string]any{"title": "T", "id": "T", "n": 3})
+	w.Close()
+	if got := buf.String(); got != "id,n,title\nT,3,T\n" {
+		t.Errorf("csv de mapa = %q", got)
+	}
+	// …y una clave nueva en un registro posterior es un error, no una pérdida.
+	w = NewCSVWriter(&bytes.Buffer{})
+	w.Write(map[string]any{"id": "T"})
+	if err := w.Write(map[string]any{"id": "U", "extra": 1}); err == nil {
+		t.Error("una clave sin columna debe ser un error")
+	}
+
+	// v3: registros con claves distintas comparten las columnas de V3Keys.
+	buf.Reset()
+	w = NewCSVWriter(&buf)
+	opts := transform.Options{Fallback: transform.FallbackOmit}
+	var b models.Tiddler
+	if err := json.Unmarshal([]byte(`{"title":"B","created":"20250101120000000","modified":"20250102120000000","manual":"sí"}`), &b); err != nil {
+		t.Fatal(err)
+	}
+	b.Source = "equipoB"
+	for _, td := range []models.Tiddler{{Title: "A"}, b} {
+		if err := w.Write(transform.ConvertTiddlerV3With(td, opts)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows[0], transform.V3Keys) {
+		t.Errorf("encabezado v3 = %q, want %q", rows[0], transform.V3Keys)
+	}
+	got := map[string]string{}
+	for i, col := range rows[0] {
+		got[col] = rows[2][i]
+	}
+	for col, want := range map[string]string{
+		"created": "2025-01-01T12:00:00+00:00", "modified": "2025-01-02T12:00:00+00:00",
+		"fields": `{"manual":"sí"}`, "source_wiki": "equipoB",
+	} {
+		if got[col] != want {
+			t.Errorf("fila B, %s = %q, want %q", col, got[col], want)
+		}
+	}
+}
+
+func TestMapRecordToParquet_Fields(t *testing.T) {
+	v3 := map[string]any{"id": "A", "fields": map[string]any{"status": "draft", "priority": 3.0}}
+	if got := MapRecordToParquet(v3).Fields; got != `{"priority":3,"status":"draft"}` {
+		t.Errorf("fields v3 = %q", got)
+	}
+	// v2: meta.extra sin tmap.id ni source_wiki.
+	v2 := map[string]any{"id": "A", "meta": map[string]any{"extra": map[string]any{"tmap.id": "x", "source_wiki": "w", "caption": "C"}}}
+	if got := MapRecordToParquet(v2).Fields; got != `{"caption":"C"}` {
+		t.Errorf("fields v2 = %q", got)
+	}
+	if got := MapRecordToParquet(map[string]any{"id": "A"}).Fields; got != "" {
+		t.Errorf("sin campos personalizados = %q, want vacío", got)
+	}
+	// schema_version: el declarado o, si falta, el deducido de las claves.
+	if got := MapRecordToParquet(map[string]any{"id": "A", "schema_version": "hybrid", "textPlain": "x"}).Schema; got != "hybrid" {
+		t.Errorf("schema_version declarado = %q", got)
+	}
+	if got := MapRecordToParquet(map[string]any{"id": "A", "textPlain": "x"}).Schema; got != "v1" {
+		t.Errorf("schema_version deducido = %q, want v1", got)
+	}
+}
+
+func TestNewRecordWriter(t *testing.T) {
+	dir := t.TempDir()
+	for _, format := range []string{FormatJSONL, FormatJSON, FormatCSV, FormatParquet} {
+		path := filepath.Join(dir, "sub", "out."+format)
+		w, err := NewRecordWriter(path, format, false)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if err := w.Write(map[string]any{"id": "A", "tags": []any{"x"}, "contentPlain": "hola"}); err != nil {
+			t.Fatalf("%s: Write: %v", format, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: Close: %v", format, err)
+		}
+		if fi, err := os.Stat(path); err != nil || fi.Size() == 0 {
+			t.Errorf("%s: archivo vacío o ausente (%v)", format, err)
+		}
+	}
+	if _, err := NewRecordWriter(Stdio, FormatParquet, false); err == nil {
+		t.Error("parquet en stdout debería fallar")
+	}
+	if _, err := NewRecordWriter(filepath.Join(dir, "x"), "xml", false); err == nil {
+		t.Error("formato desconocido debería fallar")
+	}
+}
+
+func TestParquetWriter_Sections(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out.parquet")
+	w, err := NewParquetWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2 := map[string]any{"id": "A", "content": map[string]any{"sections": []any{
+		map[string]any{"name": "Resumen", "value": "R"},
+		map[string]any{"name": "Requisitos", "value": "- a"},
+	}}}
+	if got := MapRecordToSections(v2); len(got) != 2 || got[1] != (ParquetSection{ID: "A", Index: 1, Name: "Requisitos", Value: "- a"}) {
+		t.Errorf("secciones = %+v", got)
+	}
+	for _, rec := range []map[string]any{{"id": "B"}, v2} {
+		if err := w.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(filepath.Join(dir, "out_sections.parquet")); err != nil || fi.Size() == 0 {
+		t.Errorf("tabla de secciones vacía o ausente (%v)", err)
+	}
+
+	// Sin secciones no se crea la tabla.
+	path = filepath.Join(dir, "plano.parquet")
+	if w, err = NewParquetWriter(path); err != nil {
+		t.Fatal(err)
+	}
+	w.Write(map[string]any{"id": "B"})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(SectionsPath(path)); !os.IsNotExist(err) {
+		t.Errorf("tabla de secciones inesperada (%v)", err)
+	}
+}
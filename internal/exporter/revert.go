@@ -11,6 +11,8 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
 )
 
 // RevertToSingleTiddler exporta solo el tiddler raíz como objeto único.
@@ -58,7 +60,7 @@ func RevertToSingleTiddler(ctx context.Context, inputPath, outputPath, rootTitle
 		return fmt.Errorf("serializar objeto único: %w", err)
 	}
 
-	fmt.Printf("✅ Tiddler raíz exportado como objeto único en '%s'\n", outputPath)
+	fmt.Fprintf(os.Stderr, "✅ Tiddler raíz exportado como objeto único en '%s'\n", outputPath)
 	return nil
 }
 
@@ -74,7 +76,10 @@ type Tiddler struct {
 	TmapID   string `json:"tmap.id,omitempty"`
 }
 
-// ExportAllFromJSONL exporta todos los tiddlers desde un archivo JSONL a un archivo de salida
+// ExportAllFromJSONL exporta todos los tiddlers desde un archivo JSONL a un
+// archivo de salida.  Cada línea puede venir de cualquier modo (v1, v2, v3,
+// hybrid): transform.RecordToTiddler detecta el esquema y restaura texto,
+// tipo, fechas y campos personalizados como campos del tiddler.
 func ExportAllFromJSONL(jsonlPath, outPath string) error {
 	file, err := os.Open(jsonlPath)
 	if err != nil {
@@ -82,195 +87,25 @@ func ExportAllFromJSONL(jsonlPath, outPath string) error {
 	}
 	defer file.Close()
 
-	var resultArr []map[string]any
+	resultArr := []map[string]any{}
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	lineNumber := 0
 	for scanner.Scan() {
+		lineNumber++
 		var obj map[string]any
 		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
-			log.Printf("invalid JSONL line: %v", err)
+			log.Printf("invalid JSONL line %d: %v", lineNumber, err)
 			continue
 		}
-		title := getString(obj, "title")
-		if title == "" || title == "<nil>" {
+		t, err := transform.RecordToTiddler(obj)
+		if err != nil || t.Title == "" {
+			log.Printf("JSONL line %d: registro sin título o ilegible", lineNumber)
 			continue
 		}
 
-		// tags: array de strings (si es string, parsear)
-		var tagsArr []string
-		if arr, ok := obj["tags"].([]interface{}); ok {
-			for _, tag := range arr {
-				if s, ok := tag.(string); ok {
-					tagsArr = append(tagsArr, s)
-				}
-			}
-		} else if s, ok := obj["tags"].(string); ok {
-			tagsArr = parseTags(s)
-		} else {
-			tagsArr = []string{}
-		}
-
-		// relations
-		relations := map[string]any{}
-		if v, ok := obj["relations"].(map[string]any); ok {
-			relations = v
-		}
-
-		// tags_list: copiar tal cual si existe, si no, array vacío
-		var tagsList []string
-		if arr, ok := obj["tags_list"].([]interface{}); ok {
-			for _, tag := range arr {
-				if s, ok := tag.(string); ok {
-					tagsList = append(tagsList, s)
-				}
-			}
-		} else if arr, ok := obj["tags_list"].([]string); ok {
-			tagsList = arr
-		} else {
-			tagsList = []string{}
-		}
-
-		// text: todo lo que no sea campo estándar, serializado como JSON (MOVER AQUÍ)
-		standardFields := map[string]bool{
-			"title": true, "type": true, "tags": true, "tags_list": true,
-			"hash": true, "path": true, "color": true, "tmap.id": true, "relations": true,
-			"created": true, "modified": true, "id": true, "created_rfc": true, "modified_rfc": true,
-		}
-		textMap := make(map[string]any)
-		for k, v := range obj {
-			if !standardFields[k] {
-				textMap[k] = v
-			}
-		}
-		textBytes, _ := json.Marshal(textMap)
-		text := string(textBytes)
-		if len(textMap) == 0 {
-			text = "{}"
-		}
-
-		// color, created, modified, path, tmap.id: siempre string ("" si no existe)
-		color := getString(obj, "color")
-		created := getString(obj, "created")
-		modified := getString(obj, "modified")
-		path := getString(obj, "path")
-		tmapid := getString(obj, "tmap.id")
-
-		// --- Poblar desde meta y extra si están vacíos ---
-		if meta, ok := obj["meta"].(map[string]interface{}); ok {
-			if color == "" {
-				color = getString(meta, "color")
-			}
-			if created == "" {
-				created = getString(meta, "created")
-			}
-			if modified == "" {
-				modified = getString(meta, "modified")
-			}
-			if tmapid == "" {
-				tmapid = getString(meta, "tmap.id")
-			}
-			if path == "" {
-				path = getString(meta, "path")
-			}
-			// Buscar en meta.extra
-			if extra, ok := meta["extra"].(map[string]interface{}); ok {
-				if tmapid == "" {
-					tmapid = getString(extra, "tmap.id")
-				}
-				if color == "" {
-					color = getString(extra, "color")
-				}
-				if path == "" {
-					path = getString(extra, "path")
-				}
-			}
-		}
-
-		// Si aún están vacíos, intentar extraer desde el campo "text" (JSON serializado)
-		if looksLikeJSON(text) {
-			var inner map[string]interface{}
-			if err := json.Unmarshal([]byte(text), &inner); err == nil {
-				if created == "" {
-					created = getString(inner, "created")
-				}
-				if modified == "" {
-					modified = getString(inner, "modified")
-				}
-				if color == "" {
-					color = getString(inner, "color")
-				}
-				if tmapid == "" {
-					tmapid = getString(inner, "tmap.id")
-				}
-				if path == "" {
-					path = getString(inner, "path")
-				}
-				// Buscar en inner.meta.extra
-				if meta, ok := inner["meta"].(map[string]interface{}); ok {
-					if color == "" {
-						color = getString(meta, "color")
-					}
-					if created == "" {
-						created = getString(meta, "created")
-					}
-					if modified == "" {
-						modified = getString(meta, "modified")
-					}
-					if tmapid == "" {
-						tmapid = getString(meta, "tmap.id")
-					}
-					if path == "" {
-						path = getString(meta, "path")
-					}
-					if extra, ok := meta["extra"].(map[string]interface{}); ok {
-						if tmapid == "" {
-							tmapid = getString(extra, "tmap.id")
-						}
-						if color == "" {
-							color = getString(extra, "color")
-						}
-						if path == "" {
-							path = getString(extra, "path")
-						}
-					}
-				}
-			}
-		}
-
-		// hash
-		hash := hashSHA256(text)
-
-		// Si tagsArr y tagsList están vacíos, intenta extraerlos desde meta.tags en el campo "text"
-		if len(tagsArr) == 0 && len(tagsList) == 0 && looksLikeJSON(text) {
-			var inner map[string]interface{}
-			if err := json.Unmarshal([]byte(text), &inner); err == nil {
-				// Buscar en inner["meta"]["tags"]
-				if meta, ok := inner["meta"].(map[string]interface{}); ok {
-					if tagsRaw, ok := meta["tags"]; ok {
-						switch tags := tagsRaw.(type) {
-						case []interface{}:
-							for _, tag := range tags {
-								if s, ok := tag.(string); ok {
-									tagsArr = append(tagsArr, s)
-									tagsList = append(tagsList, s)
-								}
-							}
-						case []string:
-							tagsArr = append(tagsArr, tags...)
-							tagsList = append(tagsList, tags...)
-						}
-					}
-				}
-			}
-		}
-
-		// --- Unificación y sincronización final de tags ---
-		if len(tagsArr) == 0 && len(tagsList) > 0 {
-			tagsArr = append(tagsArr, tagsList...)
-		}
-		if len(tagsList) == 0 && len(tagsArr) > 0 {
-			// (No action needed: tagsList is set to uniqueTags below)
-		}
-		// Eliminar duplicados y mantener orden
+		// tags y tags_list: una sola lista sin duplicados, en orden
+		tagsArr := append(t.TagsAsSlice(), t.TagsList...)
 		tagsSeen := make(map[string]struct{})
 		uniqueTags := make([]string, 0, len(tagsArr))
 		for _, tag := range tagsArr {
@@ -279,25 +114,34 @@ func ExportAllFromJSONL(jsonlPath, outPath string) error {
 				uniqueTags = append(uniqueTags, tag)
 			}
 		}
-		tagsArr = uniqueTags
-		tagsList = uniqueTags
-
-		// Construir tagsTW usando helper
-		tagsTW := buildTagsTW(tagsList, tagsArr)
-
-		tiddler := map[string]any{
-			"title":     title,
-			"text":      text,
-			"type":      "application/json",
-			"tags":      tagsTW,   // <-- string TiddlyWiki
-			"tags_list": tagsList, // <-- array
-			"created":   created,
-			"modified":  modified,
-			"hash":      hash,
-			"path":      path,
-			"color":     color,
-			"tmap.id":   tmapid,
+
+		relations := t.Relations
+		if relations == nil {
+			relations = map[string]any{}
+		}
+
+		tiddler := map[string]any{}
+		for k, v := range t.ExtraFields {
+			tiddler[k] = v
+		}
+		for k, v := range map[string]any{
+			"title":     t.Title,
+			"text":      t.Text,
+			"type":      t.Type,
+			"tags":      buildTagsTW(uniqueTags, nil), // <-- string TiddlyWiki
+			"tags_list": uniqueTags,                   // <-- array
+			"created":   t.Created,
+			"modified":  t.Modified,
+			"hash":      hashSHA256(t.Text),
+			"path":      t.Path,
+			"color":     t.Color,
+			"tmap.id":   t.TmapID,
 			"relations": relations,
+		} {
+			tiddler[k] = v
+		}
+		if t.Source != "" {
+			tiddler["source_wiki"] = t.Source
 		}
 		resultArr = append(resultArr, tiddler)
 	}
@@ -365,6 +209,7 @@ func CloneAndUpdateTexts(plantillaPath, jsonlPath, outPath string) error {
 	defer jsonlFile.Close()
 
 	scanner := bufio.NewScanner(jsonlFile)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
 	for scanner.Scan() {
 		var obj map[string]interface{}
 		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
@@ -392,7 +237,7 @@ func CloneAndUpdateTexts(plantillaPath, jsonlPath, outPath string) error {
 	}
 
 	// 3. Actualizar los textos en la plantilla preservando estructura
-	now := time.Now().Format("20060102150405")
+	now := time.Now().UTC().Format("20060102150405") // TiddlyWiki guarda UTC
 	var resultArr []Tiddler
 	applied := 0 // Contador de actualizaciones aplicadas
 
@@ -450,7 +295,7 @@ func CloneAndUpdateTexts(plantillaPath, jsonlPath, outPath string) error {
 		return fmt.Errorf("codificar resultado: %w", err)
 	}
 
-	fmt.Printf("✅ Revertido completado: %d tiddlers procesados, %d actualizaciones aplicadas\n",
+	fmt.Fprintf(os.Stderr, "✅ Revertido completado: %d tiddlers procesados, %d actualizaciones aplicadas\n",
 		len(resultArr), applied)
 	return nil
 }
@@ -594,20 +439,6 @@ func UpdateTiddlerWrapper(original Tiddler, newPlain, newMarkdown string) Tiddle
 	return original
 }
 
-// parseTags extrae etiquetas [[tag]] como array de strings
-func parseTags(raw string) []string {
-	var tags []string
-	parts := strings.Fields(raw)
-	for _, p := range parts {
-		if strings.HasPrefix(p, "[[") && strings.HasSuffix(p, "]]") {
-			tag := strings.TrimPrefix(p, "[[")
-			tag = strings.TrimSuffix(tag, "]]")
-			tags = append(tags, tag)
-		}
-	}
-	return tags
-}
-
 // hashSHA256 calcula el hash SHA-256 de un string y lo devuelve en hex
 func hashSHA256(s string) string {
 	h := sha256.Sum256([]byte(s))
@@ -0,0 +1,65 @@
+package exporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Un JSONL v2 vuelve con el texto en "text", no con el registro serializado.
+func TestExportAllFromJSONL_V2(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "v2.jsonl")
+	line := `{"id":"Nota","type":"tiddler","meta":{"title":"Nota","tags":["a b"],"created":"2025-06-05T15:10:00Z","modified":"0001-01-01T00:00:00Z","extra":{"tmap.id":"uuid","caption":"N"}},"content":{"plain":"hola"}}`
+	if err := os.WriteFile(in, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.json")
+	if err := ExportAllFromJSONL(in, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(data, &got); err != nil || len(got) != 1 {
+		t.Fatalf("salida inesperada (%v): %s", err, data)
+	}
+	want := map[string]any{"title": "Nota", "text": "hola", "tags": "[[a b]]",
+		"created": "20250605151000", "modified": "", "tmap.id": "uuid", "caption": "N"}
+	for k, v := range want {
+		if got[0][k] != v {
+			t.Errorf("%s = %v, want %v", k, got[0][k], v)
+		}
+	}
+}
+
+// Las líneas de más de 64 KB (el límite por defecto de bufio.Scanner) se leen
+// enteras al revertir y al pasar a Parquet.
+func TestExportAllFromJSONL_LineaLarga(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "largo.jsonl")
+	text := strings.Repeat("x", 200*1024)
+	line := `{"id":"Largo","title":"Largo","type":"text/plain","text":"` + text + `"}`
+	if err := os.WriteFile(in, []byte(line+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.json")
+	if err := ExportAllFromJSONL(in, out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(data, &got); err != nil || len(got) != 1 || got[0]["text"] != text {
+		t.Fatalf("salida inesperada (%v): %d bytes", err, len(data))
+	}
+	if err := ConvertJSONLToParquet(in, filepath.Join(dir, "out.parquet")); err != nil {
+		t.Errorf("parquet: %v", err)
+	}
+}
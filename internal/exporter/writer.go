@@ -6,7 +6,8 @@
 //
 //   1. Creación del directorio padre si no existe.
 //   2. Named return para capturar errores al cerrar.
-//   3. Impresión en consola de la cantidad de objetos que se escribirán.
+//   3. Impresión en stderr de la cantidad de objetos que se escribirán
+//      (stdout queda reservado para los datos cuando path es "-").
 //   4. Serialización de cualquier slice (p.ej. []map[string]any de v3) a JSONL estricto.
 //   5. Opción “pretty” para inspección humana: aunque genere multilínea,
 //      siempre agrega un solo '\n' al final de cada objeto.
@@ -14,19 +15,22 @@
 // Firma:
 //   WriteJSONL(ctx, path, records any, pretty bool) error
 //     - ctx: contexto para cancelaciones futuras.
-//     - path: ruta al archivo de salida (se crea su carpeta si falta).
+//     - path: ruta al archivo de salida (se crea su carpeta si falta);
+//       "-" escribe en la salida estándar.
 //     - records: debe ser un slice (p.ej. []models.Record, []models.RecordV2 o []map[string]any).
 //     - pretty: si true, MarshalIndent (multilínea); si false, Marshal compacto (una línea por objeto).
+//
+//   WriteJSONLTo(ctx, w, records any, pretty bool) error
+//     - Igual que WriteJSONL pero sobre cualquier io.Writer, sin mensajes.
 // --------------------------------------------------------------------------------
 
 package exporter
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -47,8 +51,6 @@ import (
 //	recsV2 := transform.ConvertTiddlersV2(tiddlers)  // []models.RecordV2
 //	WriteJSONL(ctx, "out_pretty.json", recsV2, true)
 func WriteJSONL(ctx context.Context, path string, records any, pretty bool) (err error) {
-	_ = ctx // reservado para cancelaciones futuras
-
 	// 1) Verificar que 'records' sea un slice
 	v := reflect.ValueOf(records)
 	if v.Kind() != reflect.Slice {
@@ -56,6 +58,11 @@ func WriteJSONL(ctx context.Context, path string, records any, pretty bool) (err
 	}
 	count := v.Len()
 
+	if path == Stdio {
+		fmt.Fprintf(os.Stderr, "💾 Escribiendo %d registros en stdout...\n", count)
+		return WriteJSONLTo(ctx, os.Stdout, records, pretty)
+	}
+
 	// 2) Asegurarnos de que el directorio padre exista
 	dir := filepath.Dir(path)
 	if dir != "" && dir != "." {
@@ -76,45 +83,35 @@ func WriteJSONL(ctx context.Context, path string, records any, pretty bool) (err
 		}
 	}()
 
-	// 4) Preparar buffer para escritura
-	w := bufio.NewWriter(file)
+	// 4) Informar cuántos registros se escribirán
+	fmt.Fprintf(os.Stderr, "💾 Escribiendo %d registros en '%s'...\n", count, path)
 
-	// 5) Informar cuántos registros se escribirán
-	fmt.Printf("💾 Escribiendo %d registros en '%s'...\n", count, path)
+	return WriteJSONLTo(ctx, file, records, pretty)
+}
+
+// WriteJSONLTo serializa el slice records en out, un objeto por línea.
+func WriteJSONLTo(ctx context.Context, out io.Writer, records any, pretty bool) (err error) {
+	v := reflect.ValueOf(records)
+	if v.Kind() != reflect.Slice {
+		return errors.New("records debe ser un slice")
+	}
+	count := v.Len()
 
-	// 6) Iterar sobre cada elemento del slice
+	// 5) Escribir cada elemento con un JSONLWriter (ver recordwriter.go)
+	w := NewJSONLWriter(out, pretty)
 	for i := 0; i < count; i++ {
 		// Permitir cancelación si ctx se ha cancelado
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-
-		elem := v.Index(i).Interface()
-
-		// 6.1) Serializar a JSON
-		var line []byte
-		if pretty {
-			line, err = json.MarshalIndent(elem, "", "  ")
-		} else {
-			line, err = json.Marshal(elem)
-		}
-		if err != nil {
-			return fmt.Errorf("marshal elemento %d: %w", i, err)
-		}
-
-		// 6.2) Escribir el JSON y un solo '\n'
-		if _, err = w.Write(line); err != nil {
+		if err = w.Write(v.Index(i).Interface()); err != nil {
 			return fmt.Errorf("escribir elemento %d: %w", i, err)
 		}
-		if err = w.WriteByte('\n'); err != nil {
-			return fmt.Errorf("newline elemento %d: %w", i, err)
-		}
 	}
 
-	// 7) Forzar escritura en disco
-	if err = w.Flush(); err != nil {
+	// 6) Forzar escritura en el destino
+	if err = w.Close(); err != nil {
 		return fmt.Errorf("flush: %w", err)
 	}
-
 	return
 }
@@ -217,3 +217,15 @@ func verifyLines(t *testing.T, path string, wantSlice any) {
 		}
 	}
 }
+
+// ----------------------------- escritura a io.Writer -----------------------------
+func TestWriteJSONLTo(t *testing.T) {
+	var buf bytes.Buffer
+	recs := []map[string]any{{"id": "A"}, {"id": "B"}}
+	if err := WriteJSONLTo(context.Background(), &buf, recs, false); err != nil {
+		t.Fatalf("WriteJSONLTo err: %v", err)
+	}
+	if got, want := buf.String(), "{\"id\":\"A\"}\n{\"id\":\"B\"}\n"; got != want {
+		t.Errorf("salida = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,234 @@
+// internal/filter/filter.go – Expresiones de filtro de TiddlyWiki
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// TiddlyWiki selecciona tiddlers con un pequeño lenguaje de filtros:
+//
+//   [tag[Glosario]!is[system]] +[sort[modified]] -[[Borrador]]
+//
+// Una expresión es una secuencia de "runs" separados por espacios.  Cada run
+// es una cadena de pasos entre corchetes ([op[operando]op2[…]]), un título
+// suelto ([[Mi tiddler]], "comillas" o una palabra) y puede llevar un prefijo
+// que dice cómo se combina con el resultado acumulado:
+//
+//   (ninguno) | :or      → unión: añade sus títulos (los repetidos pasan al final)
+//   +         | :and     → el run se evalúa sobre el resultado y lo reemplaza
+//   -         | :except  → quita sus títulos del resultado
+//   ~         | :else    → sólo se usa si el resultado está vacío
+//
+// Este paquete implementa en Go un subconjunto suficiente para decidir qué se
+// exporta, sin abrir TiddlyWiki: los operadores de ops.go.  Un operador
+// desconocido es un error (TiddlyWiki lo trataría como nombre de campo; aquí
+// eso se escribe field:<campo>[valor]), igual que los operandos {referencia}
+// y <variable>, que necesitan una wiki viva.
+// --------------------------------------------------------------------------------
+
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefijos de run.
+const (
+	runOr     = ""
+	runAnd    = "+"
+	runExcept = "-"
+	runElse   = "~"
+)
+
+// namedPrefixes traduce los prefijos con nombre a su forma corta.
+var namedPrefixes = map[string]string{":or": runOr, ":and": runAnd, ":except": runExcept, ":else": runElse}
+
+// Filter es una expresión ya analizada, lista para Apply.
+type Filter struct {
+	src  string
+	runs []run
+}
+
+type run struct {
+	prefix string
+	steps  []step
+}
+
+// step es un paso de un run: [!]op[:suffix][operand].
+type step struct {
+	op      string
+	suffix  string
+	negate  bool
+	operand string
+	num     int // operando numérico ya validado (days, limit)
+}
+
+// String devuelve la expresión original.
+func (f *Filter) String() string { return f.src }
+
+// Parse analiza expr.  Los errores indican la posición (en caracteres) del
+// problema.
+func Parse(expr string) (*Filter, error) {
+	p := parser{src: []rune(expr)}
+	f := &Filter{src: expr}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			break
+		}
+		r, err := p.run()
+		if err != nil {
+			return nil, fmt.Errorf("filtro %q, posición %d: %w", expr, p.pos+1, err)
+		}
+		f.runs = append(f.runs, r)
+	}
+	if len(f.runs) == 0 {
+		return nil, fmt.Errorf("filtro vacío")
+	}
+	return f, nil
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && isSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func isSpace(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' }
+
+// until consume hasta el delimitador (sin incluirlo) y lo salta.
+func (p *parser) until(delim rune, what string) (string, error) {
+	start := p.pos
+	for !p.eof() && p.peek() != delim {
+		p.pos++
+	}
+	if p.eof() {
+		p.pos = start
+		return "", fmt.Errorf("falta %q que cierre %s", delim, what)
+	}
+	s := string(p.src[start:p.pos])
+	p.pos++
+	return s, nil
+}
+
+func (p *parser) run() (run, error) {
+	var r run
+	switch c := p.peek(); {
+	case c == '+' || c == '-' || c == '~':
+		r.prefix = string(c)
+		p.pos++
+	case c == ':':
+		start := p.pos
+		for !p.eof() && p.peek() != '[' && !isSpace(p.peek()) {
+			p.pos++
+		}
+		name := string(p.src[start:p.pos])
+		prefix, ok := namedPrefixes[name]
+		if !ok {
+			p.pos = start
+			return r, fmt.Errorf("prefijo de run no soportado: %s", name)
+		}
+		r.prefix = prefix
+	}
+
+	switch c := p.peek(); {
+	case c == '[' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '[':
+		p.pos += 2
+		title, err := p.until(']', "el título")
+		if err != nil {
+			return r, err
+		}
+		if p.peek() != ']' {
+			return r, fmt.Errorf("falta \"]]\" tras el título %q", title)
+		}
+		p.pos++
+		r.steps = []step{{op: "title", operand: title}}
+	case c == '[':
+		p.pos++
+		steps, err := p.steps()
+		if err != nil {
+			return r, err
+		}
+		r.steps = steps
+	case c == '"' || c == '\'':
+		p.pos++
+		title, err := p.until(c, "las comillas")
+		if err != nil {
+			return r, err
+		}
+		r.steps = []step{{op: "title", operand: title}}
+	case c == 0 || isSpace(c):
+		return r, fmt.Errorf("falta el run tras el prefijo %q", r.prefix)
+	default:
+		start := p.pos
+		for !p.eof() && !isSpace(p.peek()) && p.peek() != '[' {
+			p.pos++
+		}
+		r.steps = []step{{op: "title", operand: string(p.src[start:p.pos])}}
+	}
+	return r, nil
+}
+
+// steps analiza los pasos de un run hasta su "]" final.
+func (p *parser) steps() ([]step, error) {
+	var steps []step
+	for {
+		if p.eof() {
+			return nil, fmt.Errorf("falta \"]\" que cierre el run")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			if len(steps) == 0 {
+				return nil, fmt.Errorf("run vacío")
+			}
+			return steps, nil
+		}
+		start := p.pos
+		var s step
+		if p.peek() == '!' {
+			s.negate = true
+			p.pos++
+		}
+		nameStart := p.pos
+		for !p.eof() && !strings.ContainsRune("[{<]", p.peek()) {
+			p.pos++
+		}
+		name := string(p.src[nameStart:p.pos])
+		s.op, s.suffix, _ = strings.Cut(name, ":")
+		if s.op == "" {
+			s.op = "title"
+		}
+
+		switch p.peek() {
+		case '[':
+			p.pos++
+			operand, err := p.until(']', "el operando de "+s.op)
+			if err != nil {
+				return nil, err
+			}
+			s.operand = operand
+		case '{', '<':
+			return nil, fmt.Errorf("%s: los operandos {referencia} y <variable> no están soportados", s.op)
+		default:
+			return nil, fmt.Errorf("%s: falta el operando [...]", s.op)
+		}
+
+		if err := check(&s); err != nil {
+			p.pos = start
+			return nil, err
+		}
+		steps = append(steps, s)
+	}
+}
@@ -0,0 +1,93 @@
+// internal/filter/filter_test.go – Tests de Parse y Apply
+// --------------------------------------------------------------------------------
+
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+var now = time.Date(2025, 6, 30, 15, 0, 0, 0, time.UTC)
+
+func wiki() []models.Tiddler {
+	return []models.Tiddler{
+		{Title: "Glosario", Modified: "20250629100000000"},
+		{Title: "Término B", Tags: "Glosario", Modified: "20250501100000000", ExtraFields: map[string]any{"color": "red"}},
+		{Title: "término a", Tags: "[[Glosario]] borrador", Modified: "20250625100000000"},
+		{Title: "$:/config/Tema", Modified: "20250630080000000"},
+		{Title: "Diario", Created: "20250101000000000"},
+		{Title: "Término C", ExtraFields: map[string]any{"plugin": "$:/plugins/equipo/glosario"}},
+	}
+}
+
+func titles(ts []models.Tiddler) []string {
+	out := []string{}
+	for _, t := range ts {
+		out = append(out, t.Title)
+	}
+	return out
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		expr string
+		want []string
+	}{
+		{"[tag[Glosario]]", []string{"Término B", "término a"}},
+		{"[!is[system]!tag[Glosario]]", []string{"Glosario", "Diario", "Término C"}},
+		{"[prefix[Tér]] [[Glosario]]", []string{"Término B", "Término C", "Glosario"}},
+		{"[prefix[Tér]] [tag[Glosario]]", []string{"Término C", "Término B", "término a"}},
+		{"[has[color]]", []string{"Término B"}},
+		{"[field:color[red]] [field:color[]suffix[C]]", []string{"Término B", "Término C"}},
+		{"[is[shadow]]", []string{"Término C"}},
+		{"[days[-7]]", []string{"Glosario", "término a", "$:/config/Tema"}},
+		{"[!days[-7]]", []string{"Término B"}},
+		{"[days[0]]", []string{"$:/config/Tema"}},
+		{"[days:created[-365]]", []string{"Diario"}},
+		{"[tag[Glosario]] +[sort[title]]", []string{"término a", "Término B"}},
+		{"[tag[Glosario]] +[!sort[modified]]", []string{"término a", "Término B"}},
+		{"[!is[system]] -[tag[borrador]] +[limit[2]]", []string{"Glosario", "Término B"}},
+		{"[!is[system]] :except[tag[borrador]] :and[!limit[1]]", []string{"Término C"}},
+		{"[tag[nada]] ~[[Diario]]", []string{"Diario"}},
+		{"\"término a\" 'Diario' Glosario", []string{"término a", "Diario", "Glosario"}},
+		{"[[No existe]]", []string{}},
+	}
+	for _, c := range cases {
+		f, err := Parse(c.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.expr, err)
+			continue
+		}
+		if got := titles(f.Apply(wiki(), Options{Now: now})); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s = %q, want %q", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := map[string]string{
+		"":                    "vacío",
+		"[tag[x]":             "falta",
+		"[tag[x":              "falta",
+		"[backlinks[x]]":      "operador no soportado",
+		"[tag{x}]":            "no están soportados",
+		"[days[ayer]]":        "número de días",
+		"[is[draft]]":         "is[draft]",
+		"[prefix:ci[x]]":      "sufijo",
+		"[field[x]]":          "field:<campo>",
+		":despues[tag[x]]":    "prefijo de run",
+		"[[sin cerrar]":       "]]",
+		"[tag[x]] + [tag[y]]": "falta el run",
+	}
+	for expr, want := range cases {
+		_, err := Parse(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) = %v, want error con %q", expr, err, want)
+		}
+	}
+}
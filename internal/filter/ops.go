@@ -0,0 +1,276 @@
+// internal/filter/ops.go – Operadores de filtro y evaluación
+// --------------------------------------------------------------------------------
+// Operadores soportados (con ! se niegan):
+//
+//   title[T]          el tiddler T                 ([[T]] es la forma corta)
+//   field:F[V]        campo F igual a V            ("" = campo vacío o ausente)
+//   tag[E]            etiquetados con E
+//   is[system]        título "$:/…"; is[shadow]: desempaquetados de un plugin;
+//                     is[tiddler]: todos
+//   prefix[P]         título que empieza por P;  suffix[S]: que termina en S
+//   has[F]            campo F no vacío
+//   days[N]           days:F[N] (F = modified por defecto): fecha en los
+//                     últimos |N| días si N < 0, hasta N días adelante si N > 0
+//   sort[F]           orden por F sin distinguir mayúsculas (!sort: inverso)
+//   limit[N]          los N primeros (!limit: los N últimos)
+//
+// Los campos se leen como los guarda TiddlyWiki (plugin.Fields): etiquetas
+// como lista de TiddlyWiki y valores estructurados como JSON.
+// --------------------------------------------------------------------------------
+
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/plugin"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Operators son los operadores que entiende Parse.
+var Operators = []string{"title", "field", "tag", "is", "prefix", "suffix", "has", "days", "sort", "limit"}
+
+// isValues son los operandos admitidos por is[…].
+var isValues = map[string]bool{"system": true, "shadow": true, "tiddler": true}
+
+// Options ajusta la evaluación.
+type Options struct {
+	Now time.Time // referencia de days[]; cero = time.Now()
+}
+
+// check valida sufijo y operando de s al analizarlo.
+func check(s *step) error {
+	switch s.op {
+	case "field":
+		if s.suffix == "" {
+			return fmt.Errorf("field necesita el nombre del campo: field:<campo>[valor]")
+		}
+		return nil
+	case "days":
+		n, err := strconv.Atoi(strings.TrimSpace(s.operand))
+		if err != nil {
+			return fmt.Errorf("days: %q no es un número de días", s.operand)
+		}
+		s.num = n
+		return nil
+	case "limit":
+		n, err := strconv.Atoi(strings.TrimSpace(s.operand))
+		if err != nil || n < 0 {
+			return fmt.Errorf("limit: %q no es un número natural", s.operand)
+		}
+		s.num = n
+	case "is":
+		if !isValues[s.operand] {
+			return fmt.Errorf("is[%s] no está soportado (usa system, shadow o tiddler)", s.operand)
+		}
+	case "title", "tag", "prefix", "suffix", "has", "sort":
+	default:
+		return fmt.Errorf("operador no soportado: %s (disponibles: %s; para un campo usa field:%s[valor])",
+			s.op, strings.Join(Operators, ", "), s.op)
+	}
+	if s.suffix != "" {
+		return fmt.Errorf("%s no admite sufijo (:%s)", s.op, s.suffix)
+	}
+	return nil
+}
+
+// Apply devuelve los tiddlers de ts que selecciona f, en el orden del
+// resultado (el de ts salvo que un run lo cambie con sort o limit).
+func (f *Filter) Apply(ts []models.Tiddler, opts Options) []models.Tiddler {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	e := evaluator{ts: ts, now: opts.Now, fields: make([]map[string]string, len(ts))}
+	all := make([]int, len(ts))
+	for i := range ts {
+		all[i] = i
+	}
+
+	var result []int
+	for _, r := range f.runs {
+		input := all
+		if r.prefix == runAnd {
+			input = result
+		}
+		out := input
+		for _, s := range r.steps {
+			out = e.step(s, out)
+		}
+		switch r.prefix {
+		case runOr:
+			result = union(result, out)
+		case runAnd:
+			result = out
+		case runExcept:
+			result = except(result, out)
+		case runElse:
+			if len(result) == 0 {
+				result = out
+			}
+		}
+	}
+
+	selected := make([]models.Tiddler, len(result))
+	for k, i := range result {
+		selected[k] = ts[i]
+	}
+	return selected
+}
+
+// union añade b a a; los que ya estaban pasan al final, como en TiddlyWiki.
+func union(a, b []int) []int {
+	return append(except(a, b), b...)
+}
+
+// except devuelve a sin los elementos de b.
+func except(a, b []int) []int {
+	drop := make(map[int]bool, len(b))
+	for _, i := range b {
+		drop[i] = true
+	}
+	out := make([]int, 0, len(a))
+	for _, i := range a {
+		if !drop[i] {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// evaluator recorre los tiddlers por índice y guarda sus campos ya leídos.
+type evaluator struct {
+	ts     []models.Tiddler
+	now    time.Time
+	fields []map[string]string
+}
+
+func (e *evaluator) field(i int, name string) string {
+	if e.fields[i] == nil {
+		fields, err := plugin.Fields(e.ts[i])
+		if err != nil {
+			fields = map[string]string{"title": e.ts[i].Title}
+		}
+		e.fields[i] = fields
+	}
+	return e.fields[i][name]
+}
+
+func (e *evaluator) step(s step, in []int) []int {
+	switch s.op {
+	case "sort":
+		return e.sort(s, in)
+	case "limit":
+		if s.num >= len(in) {
+			return in
+		}
+		if s.negate {
+			return in[len(in)-s.num:]
+		}
+		return in[:s.num]
+	case "days":
+		return e.days(s, in)
+	}
+
+	out := make([]int, 0, len(in))
+	for _, i := range in {
+		if e.match(s, i) != s.negate {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// match evalúa los operadores que sólo filtran (sin negación).
+func (e *evaluator) match(s step, i int) bool {
+	t := &e.ts[i]
+	switch s.op {
+	case "title":
+		return t.Title == s.operand
+	case "field":
+		return e.field(i, s.suffix) == s.operand
+	case "tag":
+		for _, tag := range t.TagsAsSlice() {
+			if tag == s.operand {
+				return true
+			}
+		}
+		return false
+	case "is":
+		switch s.operand {
+		case "system":
+			return strings.HasPrefix(t.Title, "$:/")
+		case "shadow":
+			return e.field(i, plugin.ShadowField) != ""
+		}
+		return true
+	case "prefix":
+		return strings.HasPrefix(t.Title, s.operand)
+	case "suffix":
+		return strings.HasSuffix(t.Title, s.operand)
+	case "has":
+		return e.field(i, s.operand) != ""
+	}
+	return false
+}
+
+// sort ordena por el campo del operando (title por defecto) sin distinguir
+// mayúsculas; el orden es estable.
+func (e *evaluator) sort(s step, in []int) []int {
+	name := s.operand
+	if name == "" {
+		name = "title"
+	}
+	out := append([]int(nil), in...)
+	sort.SliceStable(out, func(a, b int) bool {
+		x, y := strings.ToLower(e.field(out[a], name)), strings.ToLower(e.field(out[b], name))
+		if s.negate {
+			return x > y
+		}
+		return x < y
+	})
+	return out
+}
+
+// days reproduce el operador de TiddlyWiki: con N < 0 selecciona las fechas
+// desde hoy-|N| días, con N > 0 hasta hoy+N y con 0 sólo las de hoy (días
+// completos en la zona de Options.Now).  Los tiddlers sin fecha nunca pasan.
+func (e *evaluator) days(s step, in []int) []int {
+	name := s.suffix
+	if name == "" {
+		name = "modified"
+	}
+	sign := 0
+	switch {
+	case s.num > 0:
+		sign = 1
+	case s.num < 0:
+		sign = -1
+	}
+	loc := e.now.Location()
+	today := time.Date(e.now.Year(), e.now.Month(), e.now.Day(), 0, 0, 0, 0, loc)
+	target := today.AddDate(0, 0, s.num)
+	if s.negate {
+		target = target.AddDate(0, 0, -sign)
+	}
+
+	out := make([]int, 0, len(in))
+	for _, i := range in {
+		date, ok := transform.ParseTWDate(e.field(i, name))
+		if !ok {
+			continue
+		}
+		date = date.In(loc)
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		cmp := target.Compare(day)
+		within := cmp == 0 || cmp == sign
+		if within != s.negate {
+			out = append(out, i)
+		}
+	}
+	return out
+}
@@ -8,9 +8,13 @@
 //
 // Firma pública:
 //   Read(ctx context.Context, path string) ([]models.Tiddler, error)
+//   ReadFrom(ctx context.Context, r io.Reader) ([]models.Tiddler, error)
 //
 // · `ctx` permite, en una futura versión streaming, cancelar la operación.
-// · `path` es la ruta del archivo a leer.
+// · `path` es la ruta del archivo a leer; "-" significa la entrada estándar,
+//   para que el importador pueda ir al final de un pipe (`curl … | openpages`).
+//
+// Los avisos se escriben en stderr: stdout queda libre para los datos.
 //
 // El algoritmo detecta automáticamente dos formatos de exportación:
 //   1. Array JSON   → `[ {...}, {...} ]`
@@ -20,15 +24,20 @@
 package importer
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/diegoabeltran16/OpenPages-Source/models"
 )
 
-// Read abre y deserializa el archivo indicado en `path`.
+// Stdio es la ruta que Read interpreta como entrada estándar.
+const Stdio = "-"
+
+// Read abre y deserializa el archivo indicado en `path` (o stdin si es "-").
 //
 // Valores de retorno
 // ------------------
@@ -40,12 +49,30 @@ func Read(ctx context.Context, path string) ([]models.Tiddler, error) {
 	// el modo streaming con json.Decoder.
 	_ = ctx
 
+	if path == Stdio {
+		return ReadFrom(ctx, os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el archivo '%s': %w", path, err)
+	}
+	defer f.Close()
+	tiddlers, err := ReadFrom(ctx, f)
+	if err != nil {
+		return nil, fmt.Errorf("'%s': %w", path, err)
+	}
+	return tiddlers, nil
+}
+
+// ReadFrom deserializa un export de TiddlyWiki leído completo desde r.
+func ReadFrom(ctx context.Context, r io.Reader) ([]models.Tiddler, error) {
 	// ---------------------------------------------------------------------
-	// 1) Lectura de archivo completo
+	// 1) Lectura completa
 	// ---------------------------------------------------------------------
-	data, err := os.ReadFile(path)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("no se pudo leer el archivo '%s': %w", path, err)
+		return nil, fmt.Errorf("no se pudo leer la entrada: %w", err)
 	}
 
 	// ---------------------------------------------------------------------
@@ -54,22 +81,24 @@ func Read(ctx context.Context, path string) ([]models.Tiddler, error) {
 	var list []models.Tiddler
 	if err := json.Unmarshal(data, &list); err == nil {
 		if len(list) == 0 {
-			fmt.Println("⚠️  Archivo válido, pero el array de tiddlers está vacío.")
+			fmt.Fprintln(os.Stderr, "⚠️  Archivo válido, pero el array de tiddlers está vacío.")
 		}
 		return list, nil
 	}
 
 	// ---------------------------------------------------------------------
-	// 3) Intento: objeto plano (map)
+	// 3) Intento: objeto plano (map), conservando el orden del archivo:
+	//    un map de Go se recorre en orden aleatorio y la salida cambiaría
+	//    de una corrida a otra.
 	// ---------------------------------------------------------------------
-	var mp map[string]models.Tiddler
-	if err := json.Unmarshal(data, &mp); err == nil {
-		tiddlers := make([]models.Tiddler, 0, len(mp))
-		for _, t := range mp {
-			tiddlers = append(tiddlers, t)
-		}
+	var tiddlers []models.Tiddler
+	err = Stream(ctx, bytes.NewReader(data), func(t models.Tiddler) error {
+		tiddlers = append(tiddlers, t)
+		return nil
+	})
+	if err == nil {
 		if len(tiddlers) == 0 {
-			fmt.Println("⚠️  Archivo válido, pero el mapa de tiddlers está vacío.")
+			fmt.Fprintln(os.Stderr, "⚠️  Archivo válido, pero el mapa de tiddlers está vacío.")
 		}
 		return tiddlers, nil
 	}
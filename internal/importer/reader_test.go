@@ -18,6 +18,7 @@ import (
 	"context"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/diegoabeltran16/OpenPages-Source/models"
@@ -123,3 +124,47 @@ func TestRead_Map(t *testing.T) {
 		t.Errorf("Read(map) = %+v, want %+v", got, want)
 	}
 }
+
+// TestReadFrom verifica la lectura desde un io.Reader (p.ej. stdin) y que los
+// errores de formato no mencionen una ruta inexistente.
+func TestReadFrom(t *testing.T) {
+	got, err := ReadFrom(context.Background(), strings.NewReader(`[{"title":"Foo"},{"title":"Bar"}]`))
+	if err != nil {
+		t.Fatalf("ReadFrom devolvió error: %v", err)
+	}
+	if len(got) != 2 || got[0].Title != "Foo" {
+		t.Errorf("ReadFrom = %+v", got)
+	}
+
+	if _, err := ReadFrom(context.Background(), strings.NewReader("no es json")); err == nil {
+		t.Error("esperaba error de parseo")
+	}
+}
+
+// TestStream verifica la lectura incremental de ambos formatos y que el objeto
+// plano conserve el orden del archivo.
+func TestStream(t *testing.T) {
+	cases := map[string]string{
+		"array": `[{"title":"B"},{"title":"A"},{"title":"C"}]`,
+		"mapa":  `{"B":{"title":"B"},"A":{"title":"A"},"C":{"title":"C"}}`,
+	}
+	for name, data := range cases {
+		var titles []string
+		err := Stream(context.Background(), strings.NewReader(data), func(td models.Tiddler) error {
+			titles = append(titles, td.Title)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: Stream devolvió error: %v", name, err)
+		}
+		if strings.Join(titles, ",") != "B,A,C" {
+			t.Errorf("%s: orden = %v, want [B A C]", name, titles)
+		}
+	}
+
+	for _, bad := range []string{`"texto"`, `[{"title":"A"},`, ``} {
+		if err := Stream(context.Background(), strings.NewReader(bad), func(models.Tiddler) error { return nil }); err == nil {
+			t.Errorf("Stream(%q) esperaba error", bad)
+		}
+	}
+}
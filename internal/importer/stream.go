@@ -0,0 +1,81 @@
+// internal/importer/stream.go – Lectura de tiddlers de a uno (streaming)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// `Read` carga el archivo completo y devuelve un slice: simple, pero la memoria
+// crece con el tamaño del export.  `Stream` recorre el JSON con json.Decoder
+// token a token y entrega cada tiddler a un callback en cuanto lo decodifica,
+// así el pipeline importer → transform → exporter mantiene en memoria un solo
+// tiddler a la vez.
+//
+// Admite los mismos dos formatos que Read:
+//   1. Array JSON   → `[ {...}, {...} ]`
+//   2. Objeto plano → `{ "id": {...}, "id2": {...} }`  (en el orden del archivo)
+//
+// Si el callback devuelve error, Stream se detiene y lo devuelve tal cual.
+// --------------------------------------------------------------------------------
+
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// StreamFile abre path ("-" = stdin) y llama a fn por cada tiddler.
+func StreamFile(ctx context.Context, path string, fn func(models.Tiddler) error) error {
+	if path == Stdio {
+		return Stream(ctx, os.Stdin, fn)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("no se pudo leer el archivo '%s': %w", path, err)
+	}
+	defer f.Close()
+	if err := Stream(ctx, f, fn); err != nil {
+		return fmt.Errorf("'%s': %w", path, err)
+	}
+	return nil
+}
+
+// Stream decodifica un export de TiddlyWiki desde r y llama a fn por cada
+// tiddler, sin cargar el documento completo en memoria.
+func Stream(ctx context.Context, r io.Reader, fn func(models.Tiddler) error) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("error al parsear JSON de tiddlers: %w", err)
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok || (delim != '[' && delim != '{') {
+		return fmt.Errorf("error al parsear JSON de tiddlers: no es ni array ni objeto plano válido")
+	}
+
+	for i := 0; dec.More(); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if delim == '{' {
+			// Objeto plano: la clave es el título; el valor, el tiddler.
+			if _, err := dec.Token(); err != nil {
+				return fmt.Errorf("clave del tiddler %d: %w", i, err)
+			}
+		}
+		var t models.Tiddler
+		if err := dec.Decode(&t); err != nil {
+			return fmt.Errorf("tiddler %d: %w", i, err)
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("cierre del JSON de tiddlers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// internal/merge/diff3.go – Fusión de textos línea a línea (diff3)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Cuando el texto de un tiddler cambió en los dos lados (ours: el JSONL
+// editado; theirs: la wiki actual) no basta con elegir uno.  Diff3 alinea
+// ambas versiones con la base común (subsecuencia común más larga por líneas,
+// diff.Match) y recorre los bloques entre líneas "estables" (presentes en
+// las tres):
+//
+//   base == ours    → el bloque sólo cambió en theirs: se toma theirs.
+//   base == theirs  → sólo cambió en ours: se toma ours.
+//   ours == theirs  → ambos hicieron el mismo cambio: se toma una vez.
+//   otro caso       → conflicto, con marcadores al estilo git:
+//
+//     <<<<<<< ours
+//     …
+//     ||||||| base
+//     …
+//     =======
+//     …
+//     >>>>>>> theirs
+//
+// Cada conflicto se devuelve además como Hunk para el informe.
+// --------------------------------------------------------------------------------
+
+package merge
+
+import (
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/diff"
+)
+
+// Marcadores de conflicto en el texto fusionado.
+const (
+	MarkerOurs   = "<<<<<<< ours"
+	MarkerBase   = "||||||| base"
+	MarkerSep    = "======="
+	MarkerTheirs = ">>>>>>> theirs"
+)
+
+// Hunk es un bloque de texto en conflicto.
+type Hunk struct {
+	Line   int      `json:"line"` // línea del marcador <<<<<<< en el texto fusionado (desde 1)
+	Base   []string `json:"base"`
+	Ours   []string `json:"ours"`
+	Theirs []string `json:"theirs"`
+}
+
+// Diff3 fusiona ours y theirs respecto de base, línea a línea.  Devuelve el
+// texto fusionado (con marcadores si hubo conflictos) y los conflictos.
+func Diff3(base, ours, theirs string) (string, []Hunk) {
+	b, o, t := diff.SplitLines(base), diff.SplitLines(ours), diff.SplitLines(theirs)
+	mo := diff.Match(b, o)
+	mt := diff.Match(b, t)
+
+	var out []string
+	var hunks []Hunk
+	i, j, k := 0, 0, 0
+	for {
+		// Líneas estables: iguales en las tres versiones.
+		for i < len(b) && mo[i] == j && mt[i] == k {
+			out = append(out, b[i])
+			i, j, k = i+1, j+1, k+1
+		}
+		// Siguiente punto de sincronía.
+		i2, j2, k2 := len(b), len(o), len(t)
+		for n := i; n < len(b); n++ {
+			if mo[n] >= 0 && mt[n] >= 0 {
+				i2, j2, k2 = n, mo[n], mt[n]
+				break
+			}
+		}
+		cb, co, ct := b[i:i2], o[j:j2], t[k:k2]
+		switch {
+		case equalLines(cb, co):
+			out = append(out, ct...)
+		case equalLines(cb, ct), equalLines(co, ct):
+			out = append(out, co...)
+		default:
+			hunks = append(hunks, Hunk{Line: len(out) + 1, Base: cb, Ours: co, Theirs: ct})
+			out = append(out, MarkerOurs)
+			out = append(out, co...)
+			out = append(out, MarkerBase)
+			out = append(out, cb...)
+			out = append(out, MarkerSep)
+			out = append(out, ct...)
+			out = append(out, MarkerTheirs)
+		}
+		i, j, k = i2, j2, k2
+		if i == len(b) && j == len(o) && k == len(t) {
+			break
+		}
+	}
+	return strings.Join(out, "\n"), hunks
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
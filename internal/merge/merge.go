@@ -0,0 +1,230 @@
+// internal/merge/merge.go – Combinación de varias wikis con políticas de conflicto
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Al unir las wikis de varios equipos en un solo corpus aparecen dos problemas:
+//
+//   1. Procedencia: no se sabe de qué wiki viene cada registro.
+//      → Merge etiqueta cada tiddler con `Source` (campo "source_wiki" en
+//      el JSON, para no pisar un campo "source" propio de la wiki).
+//   2. Colisiones de título: dos wikis usan el mismo título con contenido
+//      distinto.  → Se resuelven con una Policy:
+//
+//        newest  → gana la versión con `modified` más reciente.
+//        prefix  → todas las versiones en conflicto se renombran "<wiki>/<título>".
+//        suffix  → la primera conserva el título; las demás reciben " (<wiki>)".
+//        fail    → Merge devuelve *ConflictError sin producir salida.
+//
+// Si dos wikis traen el mismo tiddler (mismo texto, tipo y etiquetas) no hay
+// conflicto real: se conserva la primera copia y el caso se informa como
+// "identical".  Todas las decisiones quedan en el Report para auditarlas.
+// --------------------------------------------------------------------------------
+
+package merge
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Policy decide cómo resolver títulos repetidos entre wikis.
+type Policy string
+
+const (
+	PolicyNewest Policy = "newest"
+	PolicyPrefix Policy = "prefix"
+	PolicySuffix Policy = "suffix"
+	PolicyFail   Policy = "fail"
+)
+
+// ParsePolicy valida el nombre de una política.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyNewest, PolicyPrefix, PolicySuffix, PolicyFail:
+		return p, nil
+	}
+	return "", fmt.Errorf("política de conflicto desconocida: %q (usa newest, prefix, suffix o fail)", s)
+}
+
+// Source es una wiki de entrada con su nombre de procedencia.
+type Source struct {
+	Name     string
+	Tiddlers []models.Tiddler
+}
+
+// Conflict describe un título presente en más de una wiki.
+type Conflict struct {
+	Title      string   `json:"title"`
+	Sources    []string `json:"sources"`
+	Resolution string   `json:"resolution"` // identical | newest | prefix | suffix | fail
+	Kept       []string `json:"kept"`       // títulos resultantes en la salida
+	Winner     string   `json:"winner,omitempty"`
+}
+
+// Report resume la combinación y se serializa como informe de conflictos.
+type Report struct {
+	Policy    Policy         `json:"policy"`
+	Sources   map[string]int `json:"sources"` // tiddlers leídos por wiki
+	Total     int            `json:"total"`   // tiddlers en la salida
+	Conflicts []Conflict     `json:"conflicts"`
+}
+
+// ConflictError se devuelve con PolicyFail cuando hay títulos en conflicto.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	titles := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		titles = append(titles, c.Title)
+	}
+	return fmt.Sprintf("%d títulos en conflicto: %s", len(titles), strings.Join(titles, ", "))
+}
+
+// candidate es una versión de un título procedente de una wiki.
+type candidate struct {
+	source  string
+	tiddler models.Tiddler
+}
+
+// Merge combina las wikis en orden, etiqueta cada tiddler con su procedencia y
+// resuelve los títulos repetidos según policy.  El orden de salida sigue el
+// orden de aparición del título (primera wiki que lo define).
+func Merge(sources []Source, policy Policy) ([]models.Tiddler, Report, error) {
+	report := Report{Policy: policy, Sources: make(map[string]int, len(sources))}
+
+	var order []string
+	byTitle := make(map[string][]candidate)
+	for _, src := range sources {
+		report.Sources[src.Name] += len(src.Tiddlers)
+		for _, t := range src.Tiddlers {
+			t.Source = src.Name
+			if _, ok := byTitle[t.Title]; !ok {
+				order = append(order, t.Title)
+			}
+			byTitle[t.Title] = append(byTitle[t.Title], candidate{source: src.Name, tiddler: t})
+		}
+	}
+
+	used := make(map[string]bool, len(order))
+	for _, title := range order {
+		used[title] = true
+	}
+
+	var out []models.Tiddler
+	var failed []Conflict
+	for _, title := range order {
+		cands := distinct(byTitle[title])
+		if len(cands) == 1 {
+			out = append(out, cands[0].tiddler)
+			if n := len(byTitle[title]); n > 1 {
+				report.Conflicts = append(report.Conflicts, Conflict{
+					Title:      title,
+					Sources:    sourcesOf(byTitle[title]),
+					Resolution: "identical",
+					Kept:       []string{title},
+					Winner:     cands[0].source,
+				})
+			}
+			continue
+		}
+
+		c := Conflict{Title: title, Sources: sourcesOf(cands), Resolution: string(policy)}
+		switch policy {
+		case PolicyNewest:
+			win := cands[0]
+			for _, cand := range cands[1:] {
+				if normalizeTW(cand.tiddler.GetModified()) >= normalizeTW(win.tiddler.GetModified()) {
+					win = cand
+				}
+			}
+			out = append(out, win.tiddler)
+			c.Kept = []string{title}
+			c.Winner = win.source
+
+		case PolicyPrefix:
+			for _, cand := range cands {
+				t := cand.tiddler
+				t.Title = uniqueTitle(cand.source+"/"+title, used)
+				out = append(out, t)
+				c.Kept = append(c.Kept, t.Title)
+			}
+
+		case PolicySuffix:
+			out = append(out, cands[0].tiddler)
+			c.Kept = []string{title}
+			for _, cand := range cands[1:] {
+				t := cand.tiddler
+				t.Title = uniqueTitle(fmt.Sprintf("%s (%s)", title, cand.source), used)
+				out = append(out, t)
+				c.Kept = append(c.Kept, t.Title)
+			}
+
+		default: // PolicyFail
+			failed = append(failed, c)
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, c)
+	}
+
+	if len(failed) > 0 {
+		report.Conflicts = append(report.Conflicts, failed...)
+		return nil, report, &ConflictError{Conflicts: failed}
+	}
+	report.Total = len(out)
+	return out, report, nil
+}
+
+// distinct elimina las versiones idénticas a una anterior (mismo texto, tipo
+// y etiquetas), conservando la primera aparición.
+func distinct(cands []candidate) []candidate {
+	out := make([]candidate, 0, len(cands))
+	for _, c := range cands {
+		dup := false
+		for _, prev := range out {
+			if sameContent(prev.tiddler, c.tiddler) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func sameContent(a, b models.Tiddler) bool {
+	return a.Text == b.Text && a.Type == b.Type &&
+		strings.Join(a.TagsAsSlice(), "\x00") == strings.Join(b.TagsAsSlice(), "\x00")
+}
+
+func sourcesOf(cands []candidate) []string {
+	out := make([]string, 0, len(cands))
+	for _, c := range cands {
+		out = append(out, c.source)
+	}
+	return out
+}
+
+// uniqueTitle devuelve title, o title con un contador si ya está en uso, y lo reserva.
+func uniqueTitle(title string, used map[string]bool) string {
+	candidate := title
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s %d", title, i)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// normalizeTW rellena una fecha TiddlyWiki (8, 14 o 17 dígitos) a 17 dígitos
+// para que la comparación lexicográfica equivalga a la cronológica.
+func normalizeTW(s string) string {
+	if len(s) >= 17 {
+		return s
+	}
+	return s + strings.Repeat("0", 17-len(s))
+}
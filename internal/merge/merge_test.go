@@ -0,0 +1,153 @@
+package merge
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func mergeFixture() []Source {
+	return []Source{
+		{Name: "a", Tiddlers: []models.Tiddler{
+			{Title: "Inicio", Text: "versión A", Modified: "20250101120000"},
+			{Title: "Común", Text: "igual"},
+			{Title: "Sólo A", Text: "x"},
+		}},
+		{Name: "b", Tiddlers: []models.Tiddler{
+			{Title: "Inicio", Text: "versión B", Modified: "20250301120000000"},
+			{Title: "Común", Text: "igual"},
+		}},
+	}
+}
+
+func titles(ts []models.Tiddler) []string {
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		out = append(out, t.Title)
+	}
+	return out
+}
+
+func TestMerge_Policies(t *testing.T) {
+	cases := []struct {
+		policy Policy
+		want   []string
+	}{
+		{PolicyNewest, []string{"Inicio", "Común", "Sólo A"}},
+		{PolicyPrefix, []string{"a/Inicio", "b/Inicio", "Común", "Sólo A"}},
+		{PolicySuffix, []string{"Inicio", "Inicio (b)", "Común", "Sólo A"}},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			out, rep, err := Merge(mergeFixture(), tc.policy)
+			if err != nil {
+				t.Fatalf("Merge: %v", err)
+			}
+			got := titles(out)
+			if len(got) != len(tc.want) {
+				t.Fatalf("títulos = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("títulos = %v, want %v", got, tc.want)
+				}
+			}
+			if rep.Total != len(out) || rep.Sources["a"] != 3 || rep.Sources["b"] != 2 {
+				t.Errorf("report = %+v", rep)
+			}
+			if len(rep.Conflicts) != 2 || rep.Conflicts[1].Resolution != "identical" {
+				t.Errorf("conflictos = %+v", rep.Conflicts)
+			}
+		})
+	}
+}
+
+func TestMerge_NewestAndSource(t *testing.T) {
+	out, rep, err := Merge(mergeFixture(), PolicyNewest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Text != "versión B" || out[0].Source != "b" {
+		t.Errorf("ganador = %+v, want la versión de b (17 dígitos, más reciente)", out[0])
+	}
+	if rep.Conflicts[0].Winner != "b" {
+		t.Errorf("winner = %q", rep.Conflicts[0].Winner)
+	}
+	if out[2].Source != "a" {
+		t.Errorf("source de 'Sólo A' = %q", out[2].Source)
+	}
+}
+
+// Un campo "source" propio de la wiki es un campo personalizado más: la
+// procedencia va aparte, en "source_wiki", y ninguno pisa al otro.
+func TestMerge_KeepsCustomSource(t *testing.T) {
+	var cita models.Tiddler
+	if err := json.Unmarshal([]byte(`{"title":"Cita","text":"x","source":"Libro de Borges"}`), &cita); err != nil {
+		t.Fatal(err)
+	}
+	srcs := []Source{
+		{Name: "a", Tiddlers: []models.Tiddler{cita}},
+		{Name: "b", Tiddlers: []models.Tiddler{{Title: "Otro", Text: "z"}}},
+	}
+	out, _, err := Merge(srcs, PolicyNewest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Source != "a" || out[0].ExtraFields["source"] != "Libro de Borges" {
+		t.Errorf("Cita: Source = %q, fields = %v", out[0].Source, out[0].ExtraFields)
+	}
+	data, err := json.Marshal(&out[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["source"] != "Libro de Borges" || m["source_wiki"] != "a" {
+		t.Errorf("JSON = %s", data)
+	}
+}
+
+func TestMerge_Fail(t *testing.T) {
+	out, rep, err := Merge(mergeFixture(), PolicyFail)
+	var cerr *ConflictError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("esperaba *ConflictError, got %v", err)
+	}
+	if out != nil || len(cerr.Conflicts) != 1 || cerr.Conflicts[0].Title != "Inicio" {
+		t.Errorf("out=%v conflictos=%+v", out, cerr.Conflicts)
+	}
+	if len(rep.Conflicts) != 2 {
+		t.Errorf("el informe debe incluir también los idénticos: %+v", rep.Conflicts)
+	}
+}
+
+func TestMerge_PrefixAvoidsExistingTitle(t *testing.T) {
+	srcs := []Source{
+		{Name: "a", Tiddlers: []models.Tiddler{{Title: "X", Text: "1"}, {Title: "a/X", Text: "ya existe"}}},
+		{Name: "b", Tiddlers: []models.Tiddler{{Title: "X", Text: "2"}}},
+	}
+	out, _, err := Merge(srcs, PolicyPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := titles(out)
+	want := []string{"a/X 2", "b/X", "a/X"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("títulos = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if _, err := ParsePolicy("suffix"); err != nil {
+		t.Error(err)
+	}
+	if _, err := ParsePolicy("random"); err == nil {
+		t.Error("esperaba error para política desconocida")
+	}
+}
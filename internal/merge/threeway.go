@@ -0,0 +1,337 @@
+// internal/merge/threeway.go – Fusión de tres vías: base, ours y theirs
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// El flujo "exportar → editar el JSONL → volver a la wiki" tiene tres
+// versiones de cada tiddler:
+//
+//   base   → el export original del que salió el JSONL.
+//   ours   → el JSONL editado (ya revertido a tiddlers).
+//   theirs → la wiki actual, que pudo cambiar después del export.
+//
+// Aplicar ours sobre theirs (lo que hace CloneAndUpdateTexts) pierde en
+// silencio lo editado en la wiki.  ThreeWay compara campo por campo
+// (roundtrip.Flatten) y sólo toma de ours lo que ours cambió:
+//
+//   sin cambio en ours      → theirs
+//   sin cambio en theirs    → ours
+//   mismo cambio en ambos   → ese valor
+//   tags / tags_list        → unión de altas y bajas de cada lado (nunca conflicto)
+//   modified                → el más reciente
+//   text                    → Diff3 línea a línea; si no se resuelve, marcadores
+//   otro campo              → se conserva theirs y se informa el conflicto
+//
+// Un JSONL exportado con un modo con pérdidas (v1, v2) no trae todos los
+// campos: ThreeWayOptions.OursView proyecta la base a través del mismo modo
+// para que "falta en ours" no cuente como "ours lo borró".
+// --------------------------------------------------------------------------------
+
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// ThreeWayOptions ajusta ThreeWay.
+type ThreeWayOptions struct {
+	// OursView proyecta un tiddler de la base al esquema de ours (por
+	// ejemplo roundtrip.Through con el modo del JSONL); nil = identidad.
+	OursView func(models.Tiddler) (models.Tiddler, error)
+	// Deletions trata los títulos de la base ausentes en ours como borrados.
+	// Si es false, un JSONL filtrado (p.ej. sin tiddlers de sistema) no
+	// borra nada: lo que falta en ours se considera sin cambios.
+	Deletions bool
+	// Now, si no es "", se escribe en modified de los tiddlers que reciben
+	// cambios de ours (como hace TiddlyWiki al guardar).
+	Now string
+}
+
+// FieldConflict es un campo (o un tiddler entero) que cambió de forma
+// distinta en ours y en theirs.
+type FieldConflict struct {
+	Title      string `json:"title"`
+	Field      string `json:"field"`      // "" en conflictos de borrado
+	Kind       string `json:"kind"`       // text | field | delete
+	Resolution string `json:"resolution"` // markers | theirs | ours
+	Base       any    `json:"base"`
+	Ours       any    `json:"ours"`
+	Theirs     any    `json:"theirs"`
+	Hunks      []Hunk `json:"hunks,omitempty"`
+}
+
+// ThreeWayReport resume la fusión y se serializa como informe de conflictos.
+type ThreeWayReport struct {
+	Total     int             `json:"total"`   // tiddlers en la salida
+	Updated   int             `json:"updated"` // tiddlers de theirs con cambios de ours
+	Added     int             `json:"added"`   // tiddlers nuevos en ours
+	Deleted   int             `json:"deleted"` // tiddlers borrados en ours (con Deletions)
+	Conflicts []FieldConflict `json:"conflicts"`
+}
+
+// ThreeWay fusiona ours y theirs respecto de base.  La salida sigue el orden
+// de theirs, seguido de los tiddlers nuevos de ours en su orden.  Los
+// conflictos de texto quedan con marcadores en el tiddler; el resto se
+// resuelve según la tabla del encabezado y todo queda en el informe.
+func ThreeWay(base, ours, theirs []models.Tiddler, opts ThreeWayOptions) ([]models.Tiddler, ThreeWayReport, error) {
+	rep := ThreeWayReport{Conflicts: []FieldConflict{}}
+	view := opts.OursView
+	if view == nil {
+		view = func(t models.Tiddler) (models.Tiddler, error) { return t, nil }
+	}
+	baseBy := index(base)
+	oursBy := index(ours)
+
+	var out []models.Tiddler
+	seen := make(map[string]bool, len(theirs))
+	for _, th := range theirs {
+		seen[th.Title] = true
+		b, inBase := baseBy[th.Title]
+		o, inOurs := oursBy[th.Title]
+
+		if !inOurs {
+			if !inBase || !opts.Deletions {
+				out = append(out, th)
+				continue
+			}
+			// Borrado en ours: sólo se aplica si theirs no cambió.
+			changed, err := differs(b, th)
+			if err != nil {
+				return nil, rep, err
+			}
+			if changed {
+				rep.Conflicts = append(rep.Conflicts, FieldConflict{Title: th.Title, Kind: "delete",
+					Resolution: "theirs", Base: "present", Ours: "deleted", Theirs: "modified"})
+				out = append(out, th)
+			} else {
+				rep.Deleted++
+			}
+			continue
+		}
+
+		if !inBase {
+			b = models.Tiddler{Title: th.Title}
+		}
+		bv, err := view(b)
+		if err != nil {
+			return nil, rep, fmt.Errorf("proyectando la base de %q: %w", b.Title, err)
+		}
+		merged, fromOurs, err := mergeTiddler(b, bv, o, th, opts.Now, &rep)
+		if err != nil {
+			return nil, rep, fmt.Errorf("fusionando %q: %w", th.Title, err)
+		}
+		if fromOurs {
+			rep.Updated++
+		}
+		out = append(out, merged)
+	}
+
+	for _, o := range ours {
+		if seen[o.Title] {
+			continue
+		}
+		seen[o.Title] = true
+		b, inBase := baseBy[o.Title]
+		if !inBase {
+			rep.Added++
+			out = append(out, o)
+			continue
+		}
+		// Borrado en theirs: se respeta salvo que ours haya editado el tiddler.
+		bv, err := view(b)
+		if err != nil {
+			return nil, rep, fmt.Errorf("proyectando la base de %q: %w", b.Title, err)
+		}
+		changed, err := differs(bv, o)
+		if err != nil {
+			return nil, rep, err
+		}
+		if changed {
+			rep.Conflicts = append(rep.Conflicts, FieldConflict{Title: o.Title, Kind: "delete",
+				Resolution: "ours", Base: "present", Ours: "modified", Theirs: "deleted"})
+			out = append(out, o)
+		}
+	}
+	rep.Total = len(out)
+	return out, rep, nil
+}
+
+// mergeTiddler fusiona un tiddler presente en ours y theirs.  base es la
+// versión original y baseView la misma vista a través del esquema de ours.
+// Si ours no aporta nada y no hay conflictos, devuelve theirs sin tocar.
+func mergeTiddler(base, baseView, ours, theirs models.Tiddler, now string, rep *ThreeWayReport) (models.Tiddler, bool, error) {
+	var maps [4]map[string]any
+	for i, t := range []models.Tiddler{base, baseView, ours, theirs} {
+		m, err := roundtrip.Flatten(t)
+		if err != nil {
+			return theirs, false, err
+		}
+		maps[i] = m
+	}
+	b, bv, o, th := maps[0], maps[1], maps[2], maps[3]
+
+	result := make(map[string]any, len(th))
+	fromOurs, conflicted, oursModified := false, false, false
+	for _, k := range fieldKeys(maps[:]) {
+		bk, bvk, ok, tk := b[k], bv[k], o[k], th[k]
+		oursChanged := !reflect.DeepEqual(ok, bvk)
+		res := tk
+		switch {
+		case !oursChanged:
+		case !reflect.DeepEqual(tk, bk) && !reflect.DeepEqual(ok, tk):
+			// Ambos lados cambiaron el campo de forma distinta.
+			switch k {
+			case "tags", "tags_list":
+				res = mergeSet(bvk, ok, tk)
+			case "modified":
+				if normalizeTW(fmt.Sprint(ok)) > normalizeTW(fmt.Sprint(tk)) {
+					res = ok
+				}
+			case "text":
+				// ours se editó sobre la vista de la base, no sobre la base cruda
+				baseText, _ := bvk.(string)
+				oursText, _ := ok.(string)
+				theirsText, _ := tk.(string)
+				text, hunks := Diff3(baseText, oursText, theirsText)
+				res = text
+				if len(hunks) > 0 {
+					conflicted = true
+					rep.Conflicts = append(rep.Conflicts, FieldConflict{Title: theirs.Title, Field: k,
+						Kind: "text", Resolution: "markers", Base: bk, Ours: ok, Theirs: tk, Hunks: hunks})
+				}
+			default:
+				conflicted = true
+				rep.Conflicts = append(rep.Conflicts, FieldConflict{Title: theirs.Title, Field: k,
+					Kind: "field", Resolution: "theirs", Base: bk, Ours: ok, Theirs: tk})
+			}
+		default:
+			res = ok
+		}
+		if !reflect.DeepEqual(res, tk) {
+			fromOurs = true
+			if k == "modified" {
+				oursModified = true
+			}
+		}
+		if res != nil && !isEmptyList(res) {
+			result[k] = res
+		}
+	}
+	if !fromOurs && !conflicted {
+		return theirs, false, nil
+	}
+	if fromOurs && now != "" && !oursModified {
+		result["modified"] = now
+	}
+	t, err := unflatten(result)
+	return t, fromOurs, err
+}
+
+// fieldKeys devuelve las claves presentes en alguno de los mapas: primero
+// las estándar en el orden de roundtrip.Fields, después las personalizadas.
+func fieldKeys(maps []map[string]any) []string {
+	keys := append([]string(nil), roundtrip.Fields...)
+	std := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		std[k] = true
+	}
+	custom := map[string]bool{}
+	for _, m := range maps {
+		for k := range m {
+			if !std[k] {
+				custom[k] = true
+			}
+		}
+	}
+	extra := make([]string, 0, len(custom))
+	for k := range custom {
+		extra = append(extra, k)
+	}
+	sort.Strings(extra)
+	return append(keys, extra...)
+}
+
+// mergeSet aplica a theirs las altas y bajas de ours respecto de base,
+// conservando el orden de theirs y agregando las altas al final.
+func mergeSet(base, ours, theirs any) any {
+	b, o, t := toStrings(base), toStrings(ours), toStrings(theirs)
+	inBase, inOurs := set(b), set(o)
+	out := make([]any, 0, len(t)+len(o))
+	have := map[string]bool{}
+	for _, s := range t {
+		if inBase[s] && !inOurs[s] {
+			continue // borrada en ours
+		}
+		out = append(out, s)
+		have[s] = true
+	}
+	for _, s := range o {
+		if !inBase[s] && !have[s] {
+			out = append(out, s)
+			have[s] = true
+		}
+	}
+	return out
+}
+
+func toStrings(v any) []string {
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, x := range list {
+		if s, ok := x.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func set(list []string) map[string]bool {
+	m := make(map[string]bool, len(list))
+	for _, s := range list {
+		m[s] = true
+	}
+	return m
+}
+
+func isEmptyList(v any) bool {
+	list, ok := v.([]any)
+	return ok && len(list) == 0
+}
+
+// unflatten reconstruye un tiddler a partir de los campos de roundtrip.Flatten.
+func unflatten(fields map[string]any) (models.Tiddler, error) {
+	raw := make(map[string]any, len(fields))
+	for k, v := range fields {
+		raw[strings.TrimPrefix(k, "fields.")] = v
+	}
+	if tags, ok := raw["tags"].([]any); ok {
+		raw["tags"] = models.StringifyList(toStrings(tags))
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return models.Tiddler{}, err
+	}
+	var t models.Tiddler
+	err = json.Unmarshal(data, &t)
+	return t, err
+}
+
+// differs indica si a y b difieren en algún campo comparado.
+func differs(a, b models.Tiddler) (bool, error) {
+	losses, err := roundtrip.Compare(a, b)
+	return len(losses) > 0, err
+}
+
+func index(ts []models.Tiddler) map[string]models.Tiddler {
+	m := make(map[string]models.Tiddler, len(ts))
+	for _, t := range ts {
+		m[t.Title] = t
+	}
+	return m
+}
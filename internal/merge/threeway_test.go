@@ -0,0 +1,159 @@
+package merge
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestDiff3(t *testing.T) {
+	base := "uno\ndos\ntres\ncuatro"
+	cases := []struct {
+		name, ours, theirs, want string
+		hunks                    int
+	}{
+		{"sólo ours", "uno\nDOS\ntres\ncuatro", base, "uno\nDOS\ntres\ncuatro", 0},
+		{"sólo theirs", base, "uno\ndos\ntres\nCUATRO", "uno\ndos\ntres\nCUATRO", 0},
+		{"cambios separados", "UNO\ndos\ntres\ncuatro", "uno\ndos\ntres\ncuatro\ncinco", "UNO\ndos\ntres\ncuatro\ncinco", 0},
+		{"mismo cambio", "uno\nDOS\ntres\ncuatro", "uno\nDOS\ntres\ncuatro", "uno\nDOS\ntres\ncuatro", 0},
+		{"conflicto", "uno\nnuestro\ntres\ncuatro", "uno\nsuyo\ntres\ncuatro",
+			"uno\n<<<<<<< ours\nnuestro\n||||||| base\ndos\n=======\nsuyo\n>>>>>>> theirs\ntres\ncuatro", 1},
+	}
+	for _, c := range cases {
+		got, hunks := Diff3(base, c.ours, c.theirs)
+		if got != c.want {
+			t.Errorf("%s: texto =\n%s\nwant\n%s", c.name, got, c.want)
+		}
+		if len(hunks) != c.hunks {
+			t.Errorf("%s: %d conflictos, want %d", c.name, len(hunks), c.hunks)
+		}
+	}
+
+	_, hunks := Diff3(base, "uno\nnuestro\ntres\ncuatro", "uno\nsuyo\ntres\ncuatro")
+	want := Hunk{Line: 2, Base: []string{"dos"}, Ours: []string{"nuestro"}, Theirs: []string{"suyo"}}
+	if !reflect.DeepEqual(hunks[0], want) {
+		t.Errorf("hunk = %+v, want %+v", hunks[0], want)
+	}
+}
+
+func TestThreeWay_Campos(t *testing.T) {
+	base := []models.Tiddler{
+		{Title: "A", Text: "hola\nmundo", Tags: "x y", Color: "red", Modified: "20250101000000"},
+		{Title: "B", Text: "b", Modified: "20250101000000"},
+		{Title: "C", Text: "c"},
+	}
+	ours := []models.Tiddler{ // editado en el JSONL
+		{Title: "A", Text: "HOLA\nmundo", Tags: "x z", Color: "blue", Modified: "20250101000000"},
+		{Title: "B", Text: "nuestro", Modified: "20250101000000"},
+		{Title: "C", Text: "c"},
+		{Title: "Nuevo", Text: "n"},
+	}
+	theirs := []models.Tiddler{ // la wiki siguió cambiando
+		{Title: "A", Text: "hola\nmundo\n!", Tags: "x y w", Color: "green", Modified: "20250201000000"},
+		{Title: "B", Text: "suyo", Modified: "20250201000000"},
+		{Title: "C", Text: "c", Color: "gris"},
+	}
+	out, rep, err := ThreeWay(base, ours, theirs, ThreeWayOptions{Now: "20250301000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(out); !reflect.DeepEqual(got, []string{"A", "B", "C", "Nuevo"}) {
+		t.Fatalf("títulos = %v", got)
+	}
+
+	a := out[0]
+	if a.Text != "HOLA\nmundo\n!" {
+		t.Errorf("texto de A = %q", a.Text)
+	}
+	if got := a.TagsAsSlice(); !reflect.DeepEqual(got, []string{"x", "w", "z"}) {
+		t.Errorf("tags de A = %v", got)
+	}
+	if a.Color != "green" || a.Modified != "20250301000000" {
+		t.Errorf("A = %+v", a)
+	}
+	if !strings.Contains(out[1].Text, MarkerOurs) {
+		t.Errorf("B sin marcadores: %q", out[1].Text)
+	}
+	if !reflect.DeepEqual(out[2], theirs[2]) {
+		t.Errorf("C sin cambios en ours debería quedar como theirs: %+v", out[2])
+	}
+
+	var got []string
+	for _, c := range rep.Conflicts {
+		got = append(got, c.Title+"."+c.Field+":"+c.Resolution)
+	}
+	if want := []string{"A.color:theirs", "B.text:markers"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("conflictos = %v, want %v", got, want)
+	}
+	if rep.Updated != 2 || rep.Added != 1 || rep.Total != 4 {
+		t.Errorf("informe = %+v", rep)
+	}
+}
+
+func TestThreeWay_Borrados(t *testing.T) {
+	base := []models.Tiddler{{Title: "A", Text: "a"}, {Title: "B", Text: "b"}, {Title: "C", Text: "c"}}
+	ours := []models.Tiddler{{Title: "C", Text: "c editado"}}
+	theirs := []models.Tiddler{{Title: "A", Text: "a"}, {Title: "B", Text: "b cambiado"}}
+
+	// Sin Deletions, lo que falta en ours no se toca.
+	out, _, _ := ThreeWay(base, ours, theirs, ThreeWayOptions{})
+	if got := titles(out); !reflect.DeepEqual(got, []string{"A", "B", "C"}) {
+		t.Errorf("sin Deletions: %v", got)
+	}
+
+	// Con Deletions: A se borra; B cambió en theirs (conflicto, se conserva);
+	// C se borró en theirs pero ours lo editó (conflicto, se conserva ours).
+	out, rep, err := ThreeWay(base, ours, theirs, ThreeWayOptions{Deletions: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(out); !reflect.DeepEqual(got, []string{"B", "C"}) {
+		t.Errorf("con Deletions: %v", got)
+	}
+	if rep.Deleted != 1 || len(rep.Conflicts) != 2 {
+		t.Errorf("informe = %+v", rep)
+	}
+}
+
+// Un JSONL v1 no trae path: con OursView la ausencia no cuenta como borrado.
+func TestThreeWay_OursView(t *testing.T) {
+	base := []models.Tiddler{{Title: "A", Text: "a", Path: "wiki/a"}}
+	theirs := []models.Tiddler{{Title: "A", Text: "a", Path: "wiki/a2"}}
+	ours := []models.Tiddler{{Title: "A", Text: "a editado"}}
+	view := func(t models.Tiddler) (models.Tiddler, error) {
+		return roundtrip.Through(t, "v1", transform.Options{})
+	}
+	out, rep, err := ThreeWay(base, ours, theirs, ThreeWayOptions{OursView: view})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Path != "wiki/a2" || out[0].Text != "a editado" || len(rep.Conflicts) != 0 {
+		t.Errorf("resultado = %+v, conflictos %+v", out[0], rep.Conflicts)
+	}
+}
+
+// v1 reindenta los textos JSON: Diff3 debe comparar ours con la base
+// proyectada, o el reindentado parece una edición de ours.
+func TestThreeWay_OursViewText(t *testing.T) {
+	base := []models.Tiddler{{Title: "A", Text: "{\"a\":1}\nuno\ndos\ntres"}}
+	theirs := []models.Tiddler{{Title: "A", Text: "nuevo\n{\"a\":1}\nuno\ndos\ntres"}}
+	ours := []models.Tiddler{{Title: "A", Text: "{\n  \"a\": 1\n}\nuno\ndos\nTRES"}}
+	view := func(t models.Tiddler) (models.Tiddler, error) {
+		t.Text = strings.Replace(t.Text, "{\"a\":1}", "{\n  \"a\": 1\n}", 1)
+		return t, nil
+	}
+	out, rep, err := ThreeWay(base, ours, theirs, ThreeWayOptions{OursView: view})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rep.Conflicts) != 0 {
+		t.Fatalf("conflictos = %+v", rep.Conflicts)
+	}
+	if want := "nuevo\n{\"a\":1}\nuno\ndos\nTRES"; out[0].Text != want {
+		t.Errorf("texto = %q, want %q", out[0].Text, want)
+	}
+}
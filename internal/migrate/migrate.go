@@ -0,0 +1,178 @@
+// internal/migrate/migrate.go – Migración de JSONL entre versiones de esquema
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un dataset exportado hace meses con -mode v1 no tiene por qué re-exportarse
+// desde la wiki (que quizá ya cambió) para pasar a v3: cada registro se
+// revierte a models.Tiddler con el esquema de origen y se vuelve a convertir
+// con el de destino.  El tiddler es el pivote, así que cualquier versión va a
+// cualquier otra, hacia arriba o hacia abajo:
+//
+//   v1     ──┐             ┌──▶ v1
+//   v2     ──┤             ├──▶ v2
+//   v3     ──┼─▶ Tiddler ──┼──▶ v3
+//   hybrid ──┘             └──▶ hybrid
+//
+// Un registro que ya está en la versión de destino pasa tal cual (sólo se le
+// agrega schema_version si no lo traía): migrar dos veces no cambia nada.
+// Bajar de versión puede perder campos que el destino no guarda (tmap.id en
+// v1, el tipo MIME de los textos planos en v2…); Record los mide con
+// roundtrip.Compare y Report los cuenta por campo.
+//
+// Los fragmentos (type "fragment", ver internal/chunk) sólo existen en v2: no
+// son tiddlers y no se pueden llevar a otra versión.
+// --------------------------------------------------------------------------------
+
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/chunk"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+)
+
+// Options elige las versiones de origen y destino.
+type Options struct {
+	From string            // versión de origen; "" o transform.SchemaAuto = la de cada registro
+	To   string            // versión de destino
+	Conv transform.Options // fechas y zona de la conversión al destino
+}
+
+// Validate comprueba que las versiones existan.
+func (o Options) Validate() error {
+	if o.From != "" && o.From != transform.SchemaAuto {
+		if _, ok := transform.LookupSchema(o.From); !ok {
+			return fmt.Errorf("versión de origen desconocida: %q (usa auto, %s)", o.From, strings.Join(transform.SchemaVersions(), ", "))
+		}
+	}
+	if _, ok := transform.LookupSchema(o.To); !ok {
+		return fmt.Errorf("versión de destino desconocida: %q (usa %s)", o.To, strings.Join(transform.SchemaVersions(), ", "))
+	}
+	return nil
+}
+
+// Report resume una migración.
+type Report struct {
+	Records  int            // registros escritos
+	Migrated int            // de ellos, los convertidos (el resto ya estaba en To)
+	From     map[string]int // versión de origen → registros
+	Lost     map[string]int // campo → registros que lo perdieron al convertir
+}
+
+// LostFields devuelve los campos de Lost en orden alfabético.
+func (r Report) LostFields() []string {
+	fields := make([]string, 0, len(r.Lost))
+	for f := range r.Lost {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Record lleva record a la versión opts.To.  Devuelve el registro de
+// destino, la versión de origen y los campos que no sobrevivieron.
+func Record(record map[string]any, opts Options) (out any, from string, losses []roundtrip.Loss, err error) {
+	declared, err := transform.DeclaredSchema(record)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	from = opts.From
+	switch {
+	case from == "" || from == transform.SchemaAuto:
+		from = transform.DetectSchema(record)
+	case declared != "" && declared != from:
+		return nil, "", nil, fmt.Errorf("el registro declara schema_version %s, no %s", declared, from)
+	}
+
+	if typ, _ := record["type"].(string); from == "v2" && typ == chunk.FragmentType && opts.To != "v2" {
+		return nil, from, nil, errors.New("los fragmentos sólo existen en v2")
+	}
+	if from == opts.To {
+		if declared == "" {
+			record[transform.SchemaVersionKey] = opts.To
+		}
+		return record, from, nil, nil
+	}
+
+	t, err := transform.RecordToTiddlerAs(record, from)
+	if err != nil {
+		return nil, from, nil, err
+	}
+	if strings.TrimSpace(t.Title) == "" {
+		return nil, from, nil, errors.New("registro sin título")
+	}
+	restored, err := roundtrip.Through(t, opts.To, opts.Conv)
+	if err != nil {
+		return nil, from, nil, err
+	}
+	if losses, err = roundtrip.Compare(t, restored); err != nil {
+		return nil, from, nil, err
+	}
+	return transform.ConverterFor(opts.To, opts.Conv)(t), from, losses, nil
+}
+
+// Stream migra el JSONL de r a opts.To y lo escribe en w, una línea por
+// registro.  Un registro que no se puede migrar no detiene el proceso: se
+// escribe el resto y el error es *transform.SkippedError con sus líneas.
+func Stream(r io.Reader, w io.Writer, opts Options) (Report, error) {
+	rep := Report{From: map[string]int{}, Lost: map[string]int{}}
+	if err := opts.Validate(); err != nil {
+		return rep, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	var skipped []transform.LineError
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var record map[string]any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber() // los enteros grandes de los campos pasan intactos
+		if err := dec.Decode(&record); err != nil {
+			skipped = append(skipped, transform.LineError{Line: line, Err: err})
+			continue
+		}
+		out, from, losses, err := Record(record, opts)
+		if err != nil {
+			skipped = append(skipped, transform.LineError{Line: line, Err: err})
+			continue
+		}
+		if err := enc.Encode(out); err != nil {
+			return rep, fmt.Errorf("escribiendo línea %d: %w", line, err)
+		}
+		rep.Records++
+		rep.From[from]++
+		if from != opts.To {
+			rep.Migrated++
+		}
+		for _, l := range losses { // Compare informa cada campo una vez
+			rep.Lost[l.Field]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return rep, fmt.Errorf("leyendo JSONL: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return rep, err
+	}
+	if len(skipped) > 0 {
+		return rep, &transform.SkippedError{Total: rep.Records + len(skipped), Lines: skipped}
+	}
+	return rep, nil
+}
@@ -0,0 +1,115 @@
+// internal/migrate/migrate_test.go – Tests de Record y Stream
+// --------------------------------------------------------------------------------
+
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+var tiddler = models.Tiddler{Title: "Nota", Text: "cuerpo", Tags: "[[a b]] c", Type: "text/vnd.tiddlywiki",
+	Created: "20250605151000", Modified: "20250606151000", TmapID: "uuid-1",
+	ExtraFields: map[string]any{"status": "draft"}}
+
+// line serializa rec como una línea JSONL, opcionalmente sin schema_version
+// (registros anteriores a la versión de esquema).
+func line(t *testing.T, rec any, legacy bool) string {
+	t.Helper()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	json.Unmarshal(data, &m)
+	if legacy {
+		delete(m, transform.SchemaVersionKey)
+	}
+	data, _ = json.Marshal(m)
+	return string(data)
+}
+
+func TestStream_UpAndDown(t *testing.T) {
+	in := line(t, transform.ConvertTiddler(tiddler), true) + "\n\n" +
+		line(t, transform.ConvertTiddlerV3(tiddler), false) + "\n"
+	var out bytes.Buffer
+	rep, err := Stream(strings.NewReader(in), &out, Options{To: "v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Records != 2 || rep.Migrated != 2 || rep.From["v1"] != 1 || rep.From["v3"] != 1 {
+		t.Errorf("reporte = %+v", rep)
+	}
+	for _, l := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var m map[string]any
+		json.Unmarshal([]byte(l), &m)
+		if m["schema_version"] != "v2" || transform.DetectSchema(m) != "v2" {
+			t.Errorf("línea no es v2: %s", l)
+		}
+		back, _ := transform.RecordToTiddler(m)
+		if back.Title != "Nota" || back.Text != "cuerpo" || back.ExtraFields["status"] != "draft" {
+			t.Errorf("tiddler migrado = %+v", back)
+		}
+	}
+
+	// Bajar a v1 pierde tmap.id y avisa.
+	out.Reset()
+	rep, err = Stream(strings.NewReader(line(t, transform.ConvertTiddlerV3(tiddler), false)), &out, Options{To: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Lost["tmap.id"] != 1 || !reflect.DeepEqual(rep.LostFields(), []string{"tmap.id"}) {
+		t.Errorf("pérdidas = %v", rep.Lost)
+	}
+}
+
+func TestStream_SameVersion(t *testing.T) {
+	// Sin schema_version sólo se agrega la versión; el resto pasa intacto.
+	in := `{"id":"A","title":"A","text":"x","fields":{"n":12345678901234567890}}` + "\n"
+	var out bytes.Buffer
+	rep, err := Stream(strings.NewReader(in), &out, Options{To: "v3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"fields":{"n":12345678901234567890},"id":"A","schema_version":"v3","text":"x","title":"A"}` + "\n"
+	if out.String() != want || rep.Migrated != 0 {
+		t.Errorf("salida = %s (migrados %d)", out.String(), rep.Migrated)
+	}
+}
+
+func TestStream_Errors(t *testing.T) {
+	fragment := `{"schema_version":"v2","id":"A#1","type":"fragment","meta":{"title":"A"},"content":{"plain":"x"},"parent":"A"}`
+	in := strings.Join([]string{
+		line(t, transform.ConvertTiddlerV2(tiddler), false),
+		`no es json`,
+		`{"schema_version":"v9","id":"B"}`,
+		fragment,
+	}, "\n")
+	var out bytes.Buffer
+	rep, err := Stream(strings.NewReader(in), &out, Options{To: "v3"})
+	var skipped *transform.SkippedError
+	if !errors.As(err, &skipped) || len(skipped.Lines) != 3 || skipped.Total != 4 || rep.Records != 1 {
+		t.Fatalf("err = %v, reporte = %+v", err, rep)
+	}
+	if skipped.Lines[2].Line != 4 || !strings.Contains(skipped.Lines[2].Error(), "fragmentos") {
+		t.Errorf("línea 4 = %v", skipped.Lines[2])
+	}
+
+	// Los fragmentos sí quedan en v2; un origen fijo debe coincidir con el declarado.
+	if _, err := Stream(strings.NewReader(fragment), &out, Options{To: "v2"}); err != nil {
+		t.Errorf("fragmento a v2: %v", err)
+	}
+	if _, err := Stream(strings.NewReader(fragment), &out, Options{From: "v1", To: "v3"}); err == nil {
+		t.Error("origen v1 sobre un registro v2 sin error")
+	}
+	if (Options{To: "v4"}).Validate() == nil || (Options{From: "v0", To: "v1"}).Validate() == nil {
+		t.Error("versiones desconocidas sin error")
+	}
+}
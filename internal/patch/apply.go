@@ -0,0 +1,199 @@
+// internal/patch/apply.go – Aplicación de parches con precondiciones
+// --------------------------------------------------------------------------------
+// Apply aplica las operaciones en orden sobre una copia de la wiki.  Antes de
+// tocar un tiddler comprueba que esté en la versión sobre la que se calculó
+// el parche:
+//
+//   add     → el título no existe.
+//   remove  → el título existe y su HashTiddler coincide con base.
+//   update  → ídem.
+//   rename  → ídem, y el título nuevo no existe.
+//
+// Si alguna operación falla no se aplica ninguna (todo o nada): el
+// RejectedError lista cada operación rechazada y el motivo.
+// --------------------------------------------------------------------------------
+
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Estados de una operación en el informe.
+const (
+	StatusApplied  = "applied"
+	StatusRejected = "rejected"
+	StatusSkipped  = "skipped" // aplicable, pero el parche se rechazó entero
+)
+
+// Result es el desenlace de una operación.
+type Result struct {
+	Index  int    `json:"index"` // posición en el parche (desde 1)
+	Op     string `json:"op"`
+	Title  string `json:"title"`
+	To     string `json:"to,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Report resume la aplicación de un parche.
+type Report struct {
+	Ops      int      `json:"ops"`
+	Applied  int      `json:"applied"`
+	Rejected int      `json:"rejected"`
+	Results  []Result `json:"results"`
+}
+
+// RejectedError indica que el parche no se aplicó porque alguna operación no
+// cumplía sus precondiciones.
+type RejectedError struct {
+	Rejected []Result
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("parche rechazado: %d operaciones no aplicables", len(e.Rejected))
+}
+
+// Apply aplica ops a ts y devuelve la wiki resultante: el orden original,
+// los renombrados en su sitio y las altas al final.  ts no se modifica.
+func Apply(ts []models.Tiddler, ops []Op) ([]models.Tiddler, Report, error) {
+	rep := Report{Ops: len(ops), Results: make([]Result, 0, len(ops))}
+	order := make([]string, 0, len(ts)+len(ops))
+	cur := make(map[string]models.Tiddler, len(ts))
+	for _, t := range ts {
+		if _, dup := cur[t.Title]; !dup {
+			order = append(order, t.Title)
+		}
+		cur[t.Title] = t
+	}
+
+	var rejected []Result
+	for i, op := range ops {
+		res := Result{Index: i + 1, Op: op.Op, Title: op.Title, To: op.To, Status: StatusApplied}
+		if err := applyOp(cur, &order, op); err != nil {
+			res.Status, res.Reason = StatusRejected, err.Error()
+			rejected = append(rejected, res)
+			rep.Rejected++
+		} else {
+			rep.Applied++
+		}
+		rep.Results = append(rep.Results, res)
+	}
+	if len(rejected) > 0 {
+		// Todo o nada: nada de lo aplicable llega a la salida.
+		for i := range rep.Results {
+			if rep.Results[i].Status == StatusApplied {
+				rep.Results[i].Status = StatusSkipped
+			}
+		}
+		rep.Applied = 0
+		return nil, rep, &RejectedError{Rejected: rejected}
+	}
+
+	out := make([]models.Tiddler, 0, len(order))
+	for _, title := range order {
+		if t, ok := cur[title]; ok {
+			out = append(out, t)
+		}
+	}
+	return out, rep, nil
+}
+
+// applyOp comprueba las precondiciones de op y la aplica sobre cur.
+func applyOp(cur map[string]models.Tiddler, order *[]string, op Op) error {
+	if err := check(op); err != nil {
+		return err
+	}
+	t, exists := cur[op.Title]
+	if op.Op == OpAdd {
+		if exists {
+			return fmt.Errorf("ya existe")
+		}
+		add := *op.Tiddler
+		add.Title = op.Title
+		cur[op.Title] = add
+		*order = append(*order, op.Title)
+		return nil
+	}
+
+	if !exists {
+		return fmt.Errorf("no existe")
+	}
+	if h := dedup.HashTiddler(t); h != op.Base {
+		return fmt.Errorf("cambió desde que se generó el parche (base %s, actual %s)", short(op.Base), short(h))
+	}
+	switch op.Op {
+	case OpRemove:
+		delete(cur, op.Title)
+		for i, title := range *order {
+			if title == op.Title {
+				*order = append((*order)[:i], (*order)[i+1:]...)
+				break
+			}
+		}
+	case OpUpdate:
+		nt, err := edit(t, op)
+		if err != nil {
+			return err
+		}
+		cur[op.Title] = nt
+	case OpRename:
+		if _, taken := cur[op.To]; taken {
+			return fmt.Errorf("el título nuevo %q ya existe", op.To)
+		}
+		nt, err := edit(t, op)
+		if err != nil {
+			return err
+		}
+		nt.Title = op.To
+		delete(cur, op.Title)
+		cur[op.To] = nt
+		for i, title := range *order {
+			if title == op.Title {
+				(*order)[i] = op.To
+			}
+		}
+	}
+	return nil
+}
+
+// edit aplica set/unset sobre el JSON de TiddlyWiki de t.
+func edit(t models.Tiddler, op Op) (models.Tiddler, error) {
+	raw, err := rawFields(t)
+	if err != nil {
+		return t, err
+	}
+	for _, name := range op.Unset {
+		delete(raw, name)
+	}
+	for name, v := range op.Set {
+		if name == "title" {
+			return t, fmt.Errorf("set no puede cambiar el título (usa rename)")
+		}
+		raw[name] = v
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return t, err
+	}
+	var nt models.Tiddler
+	if err := json.Unmarshal(b, &nt); err != nil {
+		return t, fmt.Errorf("aplicando cambios: %w", err)
+	}
+	if len(nt.ExtraFields) == 0 {
+		nt.ExtraFields = nil
+	}
+	return nt, nil
+}
+
+// short abrevia un hash para los mensajes.
+func short(h string) string {
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
@@ -0,0 +1,78 @@
+// internal/patch/folder.go – Aplicación de parches sobre una wiki en carpeta
+// --------------------------------------------------------------------------------
+// ApplyFolder carga los .tid de la carpeta, aplica el parche en memoria con
+// Apply (mismas precondiciones, todo o nada) y sólo entonces toca el disco:
+// reescribe los archivos de los tiddlers modificados, crea los de las altas
+// (y los renombrados) en la raíz de la carpeta y borra los de las bajas.
+// --------------------------------------------------------------------------------
+
+package patch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/wikifolder"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// ApplyFolder aplica ops sobre la wiki en carpeta dir.
+func ApplyFolder(dir string, ops []Op) (Report, error) {
+	folder, err := wikifolder.Read(dir)
+	if err != nil {
+		return Report{}, err
+	}
+	out, rep, err := Apply(folder.Tiddlers, ops)
+	if err != nil {
+		return rep, err
+	}
+
+	result := make(map[string]models.Tiddler, len(out))
+	for _, t := range out {
+		result[t.Title] = t
+	}
+	touched := make(map[string]bool)
+	var titles []string
+	for _, op := range ops {
+		for _, title := range []string{op.Title, op.To} {
+			if title != "" && !touched[title] {
+				touched[title] = true
+				titles = append(titles, title)
+			}
+		}
+	}
+
+	// Primero se escriben los archivos nuevos o cambiados y después se borran
+	// los que sobran: un fallo a mitad de camino no pierde contenido.
+	var stale []string
+	for _, title := range titles {
+		path, had := folder.Paths[title]
+		t, keep := result[title]
+		if !keep {
+			if had {
+				stale = append(stale, path)
+			}
+			continue
+		}
+		if !had {
+			path = filepath.Join(dir, wikifolder.FileName(title))
+			if _, err := os.Stat(path); err == nil {
+				return rep, fmt.Errorf("%s ya existe y no es el archivo de %q", path, title)
+			}
+		}
+		data, err := wikifolder.Format(t)
+		if err != nil {
+			return rep, err
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return rep, fmt.Errorf("escribiendo %s: %w", path, err)
+		}
+	}
+	for _, path := range stale {
+		if err := os.Remove(path); err != nil {
+			return rep, fmt.Errorf("borrando %s: %w", path, err)
+		}
+	}
+	return rep, nil
+}
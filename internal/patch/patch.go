@@ -0,0 +1,192 @@
+// internal/patch/patch.go – Parches de tiddlers (flujo JSONL de operaciones)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un parche es el resultado de `diff` en una forma que se puede revisar,
+// versionar y aplicar más tarde, como un parche de código.  Cada línea es una
+// operación sobre un tiddler:
+//
+//   {"op":"add","title":"Nuevo","tiddler":{…el tiddler completo…}}
+//   {"op":"remove","title":"Viejo","base":"<hash>"}
+//   {"op":"update","title":"Foo","base":"<hash>","set":{"text":"…"},"unset":["color"]}
+//   {"op":"rename","title":"Bar","to":"Baz","base":"<hash>","set":{…}}
+//
+// set/unset usan los nombres y valores del JSON de TiddlyWiki (los campos
+// personalizados por su nombre, las etiquetas como "[[a b]] c").  base es
+// dedup.HashTiddler de la versión sobre la que se calculó el parche: Apply
+// rechaza la operación si el tiddler cambió desde entonces (parche obsoleto).
+// --------------------------------------------------------------------------------
+
+package patch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/dedup"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/diff"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Operaciones de un parche.
+const (
+	OpAdd    = "add"
+	OpRemove = "remove"
+	OpUpdate = "update"
+	OpRename = "rename"
+)
+
+// Op es una operación del parche.
+type Op struct {
+	Op      string          `json:"op"`
+	Title   string          `json:"title"`
+	To      string          `json:"to,omitempty"`      // nuevo título (rename)
+	Base    string          `json:"base,omitempty"`    // dedup.HashTiddler de la versión de partida
+	Tiddler *models.Tiddler `json:"tiddler,omitempty"` // tiddler completo (add)
+	Set     map[string]any  `json:"set,omitempty"`
+	Unset   []string        `json:"unset,omitempty"`
+}
+
+// Make calcula el parche que lleva de before a after, en el orden de
+// diff.Compare (altas, bajas, renombrados, modificaciones).
+func Make(before, after []models.Tiddler) ([]Op, error) {
+	res, err := diff.Compare(before, after, diff.Options{})
+	if err != nil {
+		return nil, err
+	}
+	old, cur := byTitle(before), byTitle(after)
+
+	ops := make([]Op, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		switch e.Kind {
+		case diff.Added:
+			t := cur[e.Title]
+			ops = append(ops, Op{Op: OpAdd, Title: e.Title, Tiddler: &t})
+		case diff.Removed:
+			ops = append(ops, Op{Op: OpRemove, Title: e.Title, Base: dedup.HashTiddler(old[e.Title])})
+		case diff.Renamed, diff.Modified:
+			from := e.Title
+			op := Op{Op: OpUpdate, Title: e.Title}
+			if e.Kind == diff.Renamed {
+				from = e.From
+				op = Op{Op: OpRename, Title: e.From, To: e.Title}
+			}
+			op.Base = dedup.HashTiddler(old[from])
+			if err := setChanges(&op, e.Changes, cur[e.Title]); err != nil {
+				return nil, err
+			}
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// setChanges traduce los cambios de diff (nombres de roundtrip.Flatten) a
+// set/unset con los nombres y valores del JSON de TiddlyWiki de after.
+func setChanges(op *Op, changes []diff.Change, after models.Tiddler) error {
+	raw, err := rawFields(after)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		name := strings.TrimPrefix(c.Field, "fields.")
+		v, ok := raw[name]
+		if c.After == nil || !ok {
+			op.Unset = append(op.Unset, name)
+			continue
+		}
+		if name == "tags" {
+			v = models.StringifyList(after.TagsAsSlice())
+		}
+		if op.Set == nil {
+			op.Set = make(map[string]any)
+		}
+		op.Set[name] = v
+	}
+	return nil
+}
+
+// Read lee un parche JSONL (las líneas en blanco se ignoran).
+func Read(r io.Reader) ([]Op, error) {
+	var ops []Op
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var op Op
+		if err := json.Unmarshal([]byte(text), &op); err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+		if err := check(op); err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+		ops = append(ops, op)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("leyendo parche: %w", err)
+	}
+	return ops, nil
+}
+
+// check valida que una operación traiga lo que necesita.
+func check(op Op) error {
+	if op.Title == "" {
+		return fmt.Errorf("operación %q sin título", op.Op)
+	}
+	switch op.Op {
+	case OpAdd:
+		if op.Tiddler == nil {
+			return fmt.Errorf("add %q sin tiddler", op.Title)
+		}
+		return nil
+	case OpRemove, OpUpdate, OpRename:
+		if op.Base == "" {
+			return fmt.Errorf("%s %q sin base", op.Op, op.Title)
+		}
+		if op.Op == OpRename && op.To == "" {
+			return fmt.Errorf("rename %q sin título nuevo", op.Title)
+		}
+		return nil
+	}
+	return fmt.Errorf("operación desconocida: %q", op.Op)
+}
+
+// Write escribe el parche como JSONL, una operación por línea.
+func Write(w io.Writer, ops []Op) error {
+	enc := json.NewEncoder(w)
+	for _, op := range ops {
+		if err := enc.Encode(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawFields devuelve el tiddler como el mapa de su JSON de TiddlyWiki.
+func rawFields(t models.Tiddler) (map[string]any, error) {
+	b, err := json.Marshal(&t)
+	if err != nil {
+		return nil, fmt.Errorf("serializando %q: %w", t.Title, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+func byTitle(ts []models.Tiddler) map[string]models.Tiddler {
+	m := make(map[string]models.Tiddler, len(ts))
+	for _, t := range ts {
+		if _, dup := m[t.Title]; !dup {
+			m[t.Title] = t
+		}
+	}
+	return m
+}
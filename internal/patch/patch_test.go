@@ -0,0 +1,179 @@
+// internal/patch/patch_test.go – Tests de Make, Read/Write, Apply y ApplyFolder
+// --------------------------------------------------------------------------------
+
+package patch
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/roundtrip"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/wikifolder"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func wikis() (before, after []models.Tiddler) {
+	before = []models.Tiddler{
+		{Title: "Foo", Text: "uno", Type: "text/plain", Tags: "[[a b]]", Color: "red", Modified: "20250101000000"},
+		{Title: "Bar", Text: "bar", Type: "text/plain", Modified: "20250101000000"},
+		{Title: "Viejo", Text: "movido", Modified: "20250101000000"},
+		{Title: "Igual", Text: "igual", Modified: "20250101000000"},
+	}
+	after = []models.Tiddler{
+		{Title: "Foo", Text: "dos", Type: "text/plain", Tags: []string{"a b", "c"}, Modified: "20250202000000",
+			ExtraFields: map[string]any{"caption": "Fu"}},
+		{Title: "Nuevo", Text: "hola", Modified: "20250202000000"},
+		{Title: "Renombrado", Text: "movido", Modified: "20250101000000"},
+		{Title: "Igual", Text: "igual", Modified: "20250101000000"},
+	}
+	return before, after
+}
+
+func TestMakeApply(t *testing.T) {
+	before, after := wikis()
+	ops, err := Make(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := map[string]string{}
+	for _, op := range ops {
+		kinds[op.Title] = op.Op
+	}
+	want := map[string]string{"Nuevo": OpAdd, "Bar": OpRemove, "Viejo": OpRename, "Foo": OpUpdate}
+	for title, op := range want {
+		if kinds[title] != op {
+			t.Errorf("%s: op %q, want %q", title, kinds[title], op)
+		}
+	}
+
+	// Ida y vuelta por JSONL.
+	var buf bytes.Buffer
+	if err := Write(&buf, ops); err != nil {
+		t.Fatal(err)
+	}
+	ops, err = Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, rep, err := Apply(before, ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Applied != len(ops) {
+		t.Errorf("aplicadas %d de %d", rep.Applied, len(ops))
+	}
+	titles := []string{}
+	for _, t := range got {
+		titles = append(titles, t.Title)
+	}
+	if want := "Foo Renombrado Igual Nuevo"; strings.Join(titles, " ") != want {
+		t.Errorf("orden = %v, want %s", titles, want)
+	}
+	if got[0].Tags != "[[a b]] c" || got[0].Color != "" || got[0].ExtraFields["caption"] != "Fu" {
+		t.Errorf("Foo = %+v", got[0])
+	}
+	for i, title := range []string{"Foo", "Renombrado", "Igual", "Nuevo"} {
+		losses, err := roundtrip.Compare(afterByTitle(after, title), got[i])
+		if err != nil || len(losses) > 0 {
+			t.Errorf("%s difiere del destino: %v %v", title, losses, err)
+		}
+	}
+}
+
+func TestApply_Stale(t *testing.T) {
+	before, after := wikis()
+	ops, err := Make(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Alguien editó Foo después de generar el parche, y Nuevo ya existe.
+	current := append([]models.Tiddler(nil), before...)
+	current[0].Text = "editado en la wiki"
+	current = append(current, models.Tiddler{Title: "Nuevo"})
+
+	out, rep, err := Apply(current, ops)
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("err = %v, want RejectedError", err)
+	}
+	if out != nil || rep.Applied != 0 || len(rejected.Rejected) != 2 {
+		t.Errorf("out = %v, informe = %+v", out, rep)
+	}
+	for _, r := range rep.Results {
+		if r.Status == StatusApplied {
+			t.Errorf("%s quedó como aplicada en un parche rechazado", r.Title)
+		}
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	for _, line := range []string{
+		`{"op":"update","title":"A"}`,
+		`{"op":"borrar","title":"A","base":"x"}`,
+		`{"op":"rename","title":"A","base":"x"}`,
+		`{"op":"add","title":"A"}`,
+	} {
+		if _, err := Read(bytes.NewBufferString(line + "\n")); err == nil {
+			t.Errorf("%s: sin error", line)
+		}
+	}
+}
+
+func TestApplyFolder(t *testing.T) {
+	before, after := wikis()
+	dir := t.TempDir()
+	for _, tid := range before {
+		data, err := wikifolder.Format(tid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, wikifolder.FileName(tid.Title)), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, err := Make(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ApplyFolder(dir, ops); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Bar.tid", "Viejo.tid"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s debería haberse borrado", name)
+		}
+	}
+	folder, err := wikifolder.Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folder.Tiddlers) != 4 {
+		t.Fatalf("%d tiddlers en la carpeta, want 4", len(folder.Tiddlers))
+	}
+	for _, got := range folder.Tiddlers {
+		losses, err := roundtrip.Compare(afterByTitle(after, got.Title), got)
+		if err != nil || len(losses) > 0 {
+			t.Errorf("%s difiere del destino: %v %v", got.Title, losses, err)
+		}
+	}
+
+	// El mismo parche ya no aplica: Foo cambió y Nuevo existe.
+	if _, err := ApplyFolder(dir, ops); err == nil {
+		t.Error("segunda aplicación sin error")
+	}
+}
+
+func afterByTitle(ts []models.Tiddler, title string) models.Tiddler {
+	for _, t := range ts {
+		if t.Title == title {
+			return t
+		}
+	}
+	return models.Tiddler{}
+}
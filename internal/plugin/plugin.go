@@ -0,0 +1,225 @@
+// internal/plugin/plugin.go – Empaquetado de tiddlers como plugin de TiddlyWiki
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un plugin de TiddlyWiki es un único tiddler `application/json` cuyo texto
+// lleva los tiddlers empaquetados:
+//
+//   {"title":"$:/plugins/equipo/proyecto","type":"application/json",
+//    "plugin-type":"plugin","version":"1.0.0",
+//    "text":"{\"tiddlers\":{\"Foo\":{\"title\":\"Foo\",\"text\":\"…\"}}}"}
+//
+// Al importarlo, la wiki destino ve esos tiddlers como "shadow tiddlers": un
+// proyecto entero viaja así como un solo archivo.  Dentro del paquete todos
+// los campos son strings (las etiquetas como lista de TiddlyWiki y los valores
+// estructurados como JSON), igual que los guarda TiddlyWiki.
+//
+// Pack arma el plugin; Unpack y UnpackAll hacen el camino inverso para que el
+// contenido de los plugins de una wiki se pueda exportar y buscar como
+// tiddlers sueltos (cada uno con el campo `plugin` apuntando a su paquete).  Un
+// tiddler real con el mismo título que un shadow lo sobrescribe, como en
+// TiddlyWiki.
+// --------------------------------------------------------------------------------
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// DefaultType es el plugin-type de un plugin común.
+const DefaultType = "plugin"
+
+// ShadowField es el campo que Unpack añade a cada shadow tiddler con el
+// título de su plugin.
+const ShadowField = "plugin"
+
+// structured son los campos que el modelo guarda como JSON y que dentro del
+// paquete viajan serializados en un string.
+var structured = map[string]bool{"relations": true, "tags_list": true, "content": true, "meta": true}
+
+// Info describe el plugin.
+type Info struct {
+	Title       string // "$:/plugins/<autor>/<nombre>"
+	Name        string // nombre visible; por defecto la última parte de Title
+	Description string
+	Version     string
+	Type        string   // plugin-type: plugin | theme | language…
+	Dependents  []string // plugins que deben instalarse junto a éste
+}
+
+// payload es el texto JSON de un tiddler plugin.
+type payload struct {
+	Tiddlers map[string]map[string]any `json:"tiddlers"`
+}
+
+// IsPlugin indica si t es un tiddler plugin.
+func IsPlugin(t models.Tiddler) bool {
+	pt, _ := t.ExtraFields["plugin-type"].(string)
+	return pt != "" && t.Type == "application/json"
+}
+
+// Pack empaqueta ts en un tiddler plugin.
+func Pack(ts []models.Tiddler, info Info) (models.Tiddler, error) {
+	if info.Title == "" {
+		return models.Tiddler{}, fmt.Errorf("el plugin necesita un título ($:/plugins/autor/nombre)")
+	}
+	if info.Type == "" {
+		info.Type = DefaultType
+	}
+	if info.Name == "" {
+		info.Name = info.Title[strings.LastIndex(info.Title, "/")+1:]
+	}
+
+	p := payload{Tiddlers: make(map[string]map[string]any, len(ts))}
+	for _, t := range ts {
+		if t.Title == info.Title {
+			return models.Tiddler{}, fmt.Errorf("el plugin no puede contenerse a sí mismo (%q)", t.Title)
+		}
+		fields, err := Fields(t)
+		if err != nil {
+			return models.Tiddler{}, err
+		}
+		packed := make(map[string]any, len(fields))
+		for k, v := range fields {
+			packed[k] = v
+		}
+		p.Tiddlers[t.Title] = packed
+	}
+	text, err := json.Marshal(p)
+	if err != nil {
+		return models.Tiddler{}, fmt.Errorf("serializando plugin: %w", err)
+	}
+
+	extra := map[string]any{"plugin-type": info.Type, "name": info.Name}
+	if info.Version != "" {
+		extra["version"] = info.Version
+	}
+	if info.Description != "" {
+		extra["description"] = info.Description
+	}
+	if len(info.Dependents) > 0 {
+		extra["dependents"] = models.StringifyList(info.Dependents)
+	}
+	return models.Tiddler{
+		Title:       info.Title,
+		Type:        "application/json",
+		Text:        string(text),
+		ExtraFields: extra,
+	}, nil
+}
+
+// Fields devuelve los campos de t como strings, como los guarda TiddlyWiki
+// (se omiten los vacíos).
+func Fields(t models.Tiddler) (map[string]string, error) {
+	b, err := json.Marshal(&t)
+	if err != nil {
+		return nil, fmt.Errorf("serializando %q: %w", t.Title, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]string, len(raw))
+	for name, v := range raw {
+		var s string
+		switch vv := v.(type) {
+		case nil:
+			continue
+		case string:
+			s = vv
+		default:
+			if name == "tags" {
+				s = models.StringifyList(t.TagsAsSlice())
+				break
+			}
+			b, err := json.Marshal(vv)
+			if err != nil {
+				return nil, err
+			}
+			s = string(b)
+		}
+		if s != "" || name == "text" {
+			fields[name] = s
+		}
+	}
+	return fields, nil
+}
+
+// Unpack devuelve los tiddlers empaquetados en p, ordenados por título, con
+// el campo ShadowField apuntando a p.
+func Unpack(p models.Tiddler) ([]models.Tiddler, error) {
+	var body payload
+	if err := json.Unmarshal([]byte(p.Text), &body); err != nil {
+		return nil, fmt.Errorf("plugin %q: texto ilegible: %w", p.Title, err)
+	}
+	titles := make([]string, 0, len(body.Tiddlers))
+	for title := range body.Tiddlers {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	out := make([]models.Tiddler, 0, len(titles))
+	for _, title := range titles {
+		fields := body.Tiddlers[title]
+		for name := range structured {
+			if s, ok := fields[name].(string); ok {
+				var v any
+				if err := json.Unmarshal([]byte(s), &v); err != nil {
+					return nil, fmt.Errorf("plugin %q, tiddler %q: campo %s ilegible: %w", p.Title, title, name, err)
+				}
+				fields[name] = v
+			}
+		}
+		if _, ok := fields["title"]; !ok {
+			fields["title"] = title
+		}
+		b, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		var t models.Tiddler
+		if err := json.Unmarshal(b, &t); err != nil {
+			return nil, fmt.Errorf("plugin %q, tiddler %q: %w", p.Title, title, err)
+		}
+		t.ExtraFields[ShadowField] = p.Title
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// UnpackAll añade a ts, detrás de cada plugin, sus shadow tiddlers.  Los
+// shadows con el título de un tiddler real (o de un shadow anterior) se
+// omiten.  Devuelve también cuántos shadows se añadieron.
+func UnpackAll(ts []models.Tiddler) ([]models.Tiddler, int, error) {
+	taken := make(map[string]bool, len(ts))
+	for _, t := range ts {
+		taken[t.Title] = true
+	}
+	out := make([]models.Tiddler, 0, len(ts))
+	added := 0
+	for _, t := range ts {
+		out = append(out, t)
+		if !IsPlugin(t) {
+			continue
+		}
+		shadows, err := Unpack(t)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, s := range shadows {
+			if taken[s.Title] {
+				continue
+			}
+			taken[s.Title] = true
+			out = append(out, s)
+			added++
+		}
+	}
+	return out, added, nil
+}
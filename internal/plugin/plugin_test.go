@@ -0,0 +1,89 @@
+// internal/plugin/plugin_test.go – Tests de Pack
+// --------------------------------------------------------------------------------
+
+package plugin
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestPack(t *testing.T) {
+	ts := []models.Tiddler{
+		{Title: "Foo", Text: "hola", Type: "text/plain", Tags: []string{"a b", "c"}, ExtraFields: map[string]any{"orden": 3.0}},
+		{Title: "Bar", Relations: map[string]any{"define": []any{"Foo"}}},
+	}
+	p, err := Pack(ts, Info{Title: "$:/plugins/equipo/proyecto", Version: "1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Type != "application/json" || p.ExtraFields["plugin-type"] != "plugin" ||
+		p.ExtraFields["name"] != "proyecto" || p.ExtraFields["version"] != "1.0.0" {
+		t.Errorf("plugin = %+v", p)
+	}
+	var body payload
+	if err := json.Unmarshal([]byte(p.Text), &body); err != nil {
+		t.Fatal(err)
+	}
+	foo := body.Tiddlers["Foo"]
+	if foo["text"] != "hola" || foo["tags"] != "[[a b]] c" || foo["orden"] != "3" {
+		t.Errorf("Foo empaquetado = %v", foo)
+	}
+	if body.Tiddlers["Bar"]["relations"] != `{"define":["Foo"]}` {
+		t.Errorf("Bar empaquetado = %v", body.Tiddlers["Bar"])
+	}
+
+	if _, err := Pack(ts, Info{}); err == nil {
+		t.Error("plugin sin título sin error")
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	ts := []models.Tiddler{
+		{Title: "Foo", Text: "hola", Type: "text/plain", Tags: "[[a b]] c", Created: "20250101120000"},
+		{Title: "Bar", Relations: map[string]any{"define": []any{"Foo"}}, TagsList: []string{"x"}},
+	}
+	p, err := Pack(ts, Info{Title: "$:/plugins/equipo/proyecto", Dependents: []string{"$:/plugins/equipo/base"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ExtraFields["dependents"] != "$:/plugins/equipo/base" {
+		t.Errorf("dependents = %v", p.ExtraFields["dependents"])
+	}
+	if !IsPlugin(p) || IsPlugin(ts[0]) {
+		t.Error("IsPlugin no distingue el plugin")
+	}
+
+	shadows, err := Unpack(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shadows) != 2 || shadows[0].Title != "Bar" || shadows[1].Title != "Foo" {
+		t.Fatalf("shadows = %+v", shadows)
+	}
+	bar, foo := shadows[0], shadows[1]
+	if foo.Text != "hola" || foo.Tags != "[[a b]] c" || foo.Created != "20250101120000" || foo.ExtraFields[ShadowField] != p.Title {
+		t.Errorf("Foo = %+v", foo)
+	}
+	if rels, _ := bar.Relations["define"].([]any); len(rels) != 1 || len(bar.TagsList) != 1 {
+		t.Errorf("Bar = %+v", bar)
+	}
+
+	// Un tiddler real con el título de un shadow lo sobrescribe.
+	wiki := []models.Tiddler{p, {Title: "Foo", Text: "versión local"}}
+	all, n, err := UnpackAll(wiki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || len(all) != 3 || all[1].Title != "Bar" || all[2].Text != "versión local" {
+		t.Errorf("UnpackAll = %d, %+v", n, all)
+	}
+
+	broken := p
+	broken.Text = "{roto"
+	if _, _, err := UnpackAll([]models.Tiddler{broken}); err == nil {
+		t.Error("plugin ilegible sin error")
+	}
+}
@@ -0,0 +1,229 @@
+// internal/roundtrip/roundtrip.go – Verificación de ida y vuelta por modo
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// El README promete que TiddlyWiki JSON → JSONL → TiddlyWiki JSON es
+// bidireccional.  Este paquete lo comprueba en lugar de suponerlo: para cada
+// modo convierte cada tiddler, lo serializa como lo haría `export`, lo
+// revierte con transform.RecordToTiddlerAs en el esquema de ese modo (lo que
+// hace `revert`) y compara el resultado con el original campo por campo.
+//
+//   rep, _ := roundtrip.Check(tiddlers, transform.Modes, transform.Options{})
+//   for _, m := range rep.Modes { fmt.Println(m.Mode, m.Intact, m.LostFields()) }
+//
+// La comparación es estructural, no textual: las etiquetas se comparan como
+// lista ("[[a]] b" equivale a ["a","b"]) y un valor vacío equivale a uno
+// ausente.  Los campos personalizados se informan como "fields.<nombre>".
+// --------------------------------------------------------------------------------
+
+package roundtrip
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Fields son los campos estándar comparados, en el orden del informe.
+var Fields = []string{
+	"title", "text", "type", "tags", "created", "modified",
+	"color", "path", "tmap.id", "source_wiki", "relations", "tags_list",
+}
+
+// compatFields son campos de entrada alternativos de models.Tiddler que no
+// forman parte de un tiddler de TiddlyWiki y no se comparan.
+var compatFields = map[string]bool{"textMarkdown": true, "content": true, "meta": true}
+
+// Loss es un campo que no sobrevivió la ida y vuelta.
+type Loss struct {
+	Title    string `json:"title"`
+	Field    string `json:"field"`
+	Original any    `json:"original"`
+	Restored any    `json:"restored"`
+}
+
+// ModeReport resume las pérdidas de un modo.
+type ModeReport struct {
+	Mode     string         `json:"mode"`
+	Tiddlers int            `json:"tiddlers"`
+	Intact   int            `json:"intact"` // tiddlers restaurados sin diferencias
+	Lost     map[string]int `json:"lost"`   // campo → tiddlers afectados
+	Losses   []Loss         `json:"losses"`
+}
+
+// Lossless indica si todos los tiddlers volvieron intactos.
+func (r ModeReport) Lossless() bool { return r.Intact == r.Tiddlers }
+
+// LostFields devuelve los campos con pérdidas: primero los estándar en el
+// orden de Fields, después los personalizados en orden alfabético.
+func (r ModeReport) LostFields() []string {
+	var std, custom []string
+	for _, f := range Fields {
+		if r.Lost[f] > 0 {
+			std = append(std, f)
+		}
+	}
+	for f := range r.Lost {
+		if strings.HasPrefix(f, "fields.") {
+			custom = append(custom, f)
+		}
+	}
+	sort.Strings(custom)
+	return append(std, custom...)
+}
+
+// Report agrupa el resultado de todos los modos verificados.
+type Report struct {
+	Modes []ModeReport `json:"modes"`
+}
+
+// Check verifica cada modo de modes sobre tiddlers.
+func Check(tiddlers []models.Tiddler, modes []string, opts transform.Options) (Report, error) {
+	var rep Report
+	for _, mode := range modes {
+		mr, err := CheckMode(tiddlers, mode, opts)
+		if err != nil {
+			return rep, err
+		}
+		rep.Modes = append(rep.Modes, mr)
+	}
+	return rep, nil
+}
+
+// CheckMode exporta tiddlers con mode, los revierte y compara cada uno con
+// su original.
+func CheckMode(tiddlers []models.Tiddler, mode string, opts transform.Options) (ModeReport, error) {
+	rep := ModeReport{Mode: mode, Tiddlers: len(tiddlers), Lost: map[string]int{}, Losses: []Loss{}}
+	conv := transform.ConverterFor(mode, opts)
+	for i, t := range tiddlers {
+		restored, err := throughMode(t, mode, conv)
+		if err != nil {
+			return rep, fmt.Errorf("modo %s, tiddler #%d (%q): %w", mode, i+1, t.Title, err)
+		}
+		losses, err := Compare(t, restored)
+		if err != nil {
+			return rep, fmt.Errorf("modo %s, tiddler #%d (%q): %w", mode, i+1, t.Title, err)
+		}
+		if len(losses) == 0 {
+			rep.Intact++
+		}
+		for _, l := range losses {
+			rep.Lost[l.Field]++
+		}
+		rep.Losses = append(rep.Losses, losses...)
+	}
+	return rep, nil
+}
+
+// Through devuelve t tal como vuelve de exportarlo con mode y revertirlo:
+// lo que ese modo conserva.  merge.ThreeWay lo usa para comparar un JSONL
+// editado con la base vista a través del mismo modo.
+func Through(t models.Tiddler, mode string, opts transform.Options) (models.Tiddler, error) {
+	return throughMode(t, mode, transform.ConverterFor(mode, opts))
+}
+
+// throughMode hace el viaje completo de un tiddler: conversión, línea JSONL
+// y reversión con el esquema de mode.
+func throughMode(t models.Tiddler, mode string, conv func(models.Tiddler) any) (models.Tiddler, error) {
+	line, err := json.Marshal(conv(t))
+	if err != nil {
+		return models.Tiddler{}, fmt.Errorf("serializar: %w", err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(line, &record); err != nil {
+		return models.Tiddler{}, fmt.Errorf("el registro no es un objeto JSON: %w", err)
+	}
+	return transform.RecordToTiddlerAs(record, mode)
+}
+
+// Compare devuelve los campos en que restored difiere de original.
+func Compare(original, restored models.Tiddler) ([]Loss, error) {
+	a, err := Flatten(original)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Flatten(restored)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := append([]string(nil), Fields...)
+	var custom []string
+	for k := range a {
+		if strings.HasPrefix(k, "fields.") {
+			custom = append(custom, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok && strings.HasPrefix(k, "fields.") {
+			custom = append(custom, k)
+		}
+	}
+	sort.Strings(custom)
+	keys = append(keys, custom...)
+
+	var losses []Loss
+	for _, k := range keys {
+		if !reflect.DeepEqual(a[k], b[k]) {
+			losses = append(losses, Loss{Title: original.Title, Field: k, Original: a[k], Restored: b[k]})
+		}
+	}
+	return losses, nil
+}
+
+// Flatten pasa el tiddler por JSON (como lo escribe `revert`) y devuelve sus
+// campos normalizados: etiquetas como lista, vacíos eliminados y campos
+// personalizados con el prefijo "fields.".
+func Flatten(t models.Tiddler) (map[string]any, error) {
+	data, err := json.Marshal(&t)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(Fields))
+	for _, f := range Fields {
+		known[f] = true
+	}
+
+	out := make(map[string]any, len(raw))
+	for k, v := range raw {
+		switch {
+		case compatFields[k]:
+			continue
+		case k == "tags":
+			var tags []any
+			for _, tag := range t.TagsAsSlice() {
+				tags = append(tags, tag)
+			}
+			v = tags
+		case !known[k]:
+			k = "fields." + k
+		}
+		if !isEmpty(v) {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+func isEmpty(v any) bool {
+	switch vv := v.(type) {
+	case nil:
+		return true
+	case string:
+		return vv == ""
+	case []any:
+		return len(vv) == 0
+	case map[string]any:
+		return len(vv) == 0
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package roundtrip
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/internal/transform"
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// fixture cubre todos los campos que compara Check: fechas de 17, 14 y 8
+// dígitos, una fecha inválida, etiquetas con y sin corchetes, relaciones,
+// tags_list, path, procedencia y campos personalizados de varios tipos.
+const fixture = `[
+  {"title":"Inicio","text":"hola","type":"text/vnd.tiddlywiki","tags":"[[con espacios]] simple",
+   "created":"20250605151000123","modified":"20250606000000","color":"#00ff00","path":"wiki/inicio",
+   "tmap.id":"uuid-1","source_wiki":"equipoA","relations":{"define":["Concepto"]},"tags_list":["con espacios","simple"],
+   "caption":"Página de inicio","priority":2,"draft":false},
+  {"title":"Nota","text":"sin extras","created":"20250101","modified":"ayer"},
+  {"title":"$:/config/x","text":"yes","type":"text/plain"}
+]`
+
+func loadFixture(t *testing.T) []models.Tiddler {
+	t.Helper()
+	var ts []models.Tiddler
+	if err := json.Unmarshal([]byte(fixture), &ts); err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// v3 es el modo reversible: debe volver sin pérdidas en cualquier zona.
+func TestCheck_V3SinPerdidas(t *testing.T) {
+	ts := loadFixture(t)
+	bogota, err := transform.ParseLocation("-05:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []transform.Options{{}, {Location: bogota}, {Fallback: transform.FallbackOmit}} {
+		rep, err := CheckMode(ts, "v3", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rep.Lossless() {
+			t.Errorf("v3 (%+v) perdió datos: %+v", opts, rep.Losses)
+		}
+	}
+}
+
+// Los demás modos no escriben todos los campos; el informe debe decir
+// exactamente qué campos pierde cada uno.
+func TestCheck_PerdidasPorModo(t *testing.T) {
+	ts := loadFixture(t)
+	rep, err := Check(ts, transform.Modes, transform.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"v1":     {"path", "tmap.id", "relations", "tags_list"},
+		"v2":     {"type", "created", "modified", "path", "relations", "tags_list"},
+		"v3":     nil,
+		"hybrid": {"path", "tmap.id", "relations", "tags_list"},
+	}
+	for _, m := range rep.Modes {
+		if got := m.LostFields(); !reflect.DeepEqual(got, want[m.Mode]) {
+			t.Errorf("%s pierde %v, want %v", m.Mode, got, want[m.Mode])
+		}
+		if m.Tiddlers != len(ts) {
+			t.Errorf("%s: %d tiddlers, want %d", m.Mode, m.Tiddlers, len(ts))
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	orig := models.Tiddler{Title: "A", Tags: "[[x y]] z", Text: "t",
+		ExtraFields: map[string]any{"status": "draft"}}
+
+	// Misma lista de etiquetas con otra sintaxis: no es pérdida.
+	same := models.Tiddler{Title: "A", Tags: []string{"x y", "z"}, Text: "t",
+		ExtraFields: map[string]any{"status": "draft"}}
+	if losses, _ := Compare(orig, same); len(losses) != 0 {
+		t.Errorf("diferencias inesperadas: %+v", losses)
+	}
+
+	changed := models.Tiddler{Title: "A", Tags: "z", Text: "t",
+		ExtraFields: map[string]any{"owner": "ana"}}
+	losses, err := Compare(orig, changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for _, l := range losses {
+		fields = append(fields, l.Field)
+	}
+	if want := []string{"tags", "fields.owner", "fields.status"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("campos = %v, want %v", fields, want)
+	}
+}
+
+// v3 escribe en "text" el contenido desenvuelto de {"content":{"plain":…}}:
+// el envoltorio no vuelve y Check debe informarlo.
+func TestCheck_TextoEnvuelto(t *testing.T) {
+	ts := []models.Tiddler{{Title: "J", Type: "application/json", Text: `{"content":{"plain":"hola"},"meta":{}}`}}
+	rep, err := CheckMode(ts, "v3", transform.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rep.LostFields(); !reflect.DeepEqual(got, []string{"text"}) {
+		t.Errorf("pierde %v, want [text]", got)
+	}
+}
@@ -0,0 +1,220 @@
+// internal/sections/sections.go – Secciones con nombre de un tiddler
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Muchos tiddlers son estructurados y no un texto corrido.  Parse reconoce
+// tres formas y devuelve una models.Section por parte, para que quien
+// consume el export pueda pedir directamente "Resumen" o "Requisitos":
+//
+//   1. Objetos JSON con varias claves (application/json o texto que es un
+//      objeto): una sección por clave, en orden alfabético.  El envoltorio
+//      {"content":{"plain":…}} no cuenta: se mira el texto que envuelve.
+//   2. Encabezados según el tipo del tiddler (! … !!!!!! en wikitext,
+//      # … ###### en text/x-markdown y text/markdown): una sección por
+//      encabezado con el texto que hay hasta el siguiente.  En wikitext
+//      "#" abre una lista numerada, y en Markdown "!" es una imagen.
+//   3. Listas de definiciones: todas las líneas "campo: valor", o pares
+//      "; término" / ": definición" de TiddlyWiki.
+//
+// Un texto sin estructura (o con una sola clave/definición) no tiene
+// secciones.  Blocks expone el corte por encabezados, que también usa
+// internal/chunk para los breadcrumbs de los fragmentos.
+// --------------------------------------------------------------------------------
+
+package sections
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Block es el texto bajo un encabezado.  El bloque inicial, antes del primer
+// encabezado, tiene Level 0 y Path vacío.
+type Block struct {
+	Level int      // 1 = ! o #
+	Title string   // texto del encabezado
+	Path  []string // encabezados que lo contienen, incluido el propio
+	Text  string   // el bloque completo, con la línea del encabezado
+	Body  string   // el bloque sin la línea del encabezado
+}
+
+// Blocks divide text, de tipo MIME typ, por encabezados (ver headingMark).
+// Los bloques de código (```) no se miran: un "# comentario" dentro de ellos
+// no es un encabezado.  Se omiten los bloques vacíos.
+func Blocks(text, typ string) []Block {
+	mark := headingMark(typ)
+	var out []Block
+	var stack []string // encabezados abiertos, por nivel
+	cur := Block{}
+	var lines []string
+	flush := func() {
+		cur.Text = strings.TrimSpace(strings.Join(lines, "\n"))
+		if cur.Level > 0 {
+			cur.Body = strings.TrimSpace(strings.Join(lines[1:], "\n"))
+		} else {
+			cur.Body = cur.Text
+		}
+		if cur.Text != "" {
+			out = append(out, cur)
+		}
+		lines = nil
+	}
+
+	fenced := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fenced = !fenced
+		}
+		if level, title := heading(line, mark); !fenced && level > 0 {
+			flush()
+			if level > len(stack) {
+				level = len(stack) + 1
+			}
+			stack = append(stack[:level-1], title)
+			cur = Block{Level: level, Title: title, Path: append([]string(nil), stack...)}
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return out
+}
+
+// headingMark devuelve el carácter que abre un encabezado en el tipo typ:
+// '#' en Markdown, '!' en wikitext (tipo vacío o text/vnd.tiddlywiki) y 0 en
+// el resto (texto plano, JSON…), que no tiene encabezados.
+func headingMark(typ string) byte {
+	switch typ {
+	case "text/x-markdown", "text/markdown":
+		return '#'
+	case "", "text/vnd.tiddlywiki":
+		return '!'
+	}
+	return 0
+}
+
+// heading devuelve el nivel y el título si line es un encabezado con mark.
+func heading(line string, mark byte) (int, string) {
+	level := 0
+	for mark != 0 && level < len(line) && line[level] == mark {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, ""
+	}
+	rest := line[level:]
+	if mark == '#' && !strings.HasPrefix(rest, " ") {
+		return 0, "" // "#etiqueta" no es un encabezado Markdown
+	}
+	if title := strings.TrimSpace(rest); title != "" {
+		return level, title
+	}
+	return 0, ""
+}
+
+// Parse devuelve las secciones de t, o nil si su texto no es estructurado.
+func Parse(t models.Tiddler) []models.Section {
+	text, typ := strings.TrimSpace(t.Text), t.Type
+	if obj, ok := jsonObject(text); ok {
+		if inner, innerType, wrapped := wrappedText(obj); wrapped {
+			text, typ = inner, innerType
+		} else if len(obj) > 1 {
+			return fromJSON(obj)
+		} else if t.Type == "application/json" {
+			return nil
+		}
+	}
+	if s := fromHeadings(text, typ); s != nil {
+		return s
+	}
+	return fromDefinitions(text)
+}
+
+func jsonObject(text string) (map[string]any, bool) {
+	if !strings.HasPrefix(text, "{") {
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(text), &obj); err != nil {
+		return nil, false
+	}
+	return obj, true
+}
+
+// wrappedText reconoce {"content":{"plain"|"markdown":…}} y devuelve el
+// texto con su tipo: Markdown o wikitext.
+func wrappedText(obj map[string]any) (string, string, bool) {
+	content, ok := obj["content"].(map[string]any)
+	if !ok || len(obj) != 1 {
+		return "", "", false
+	}
+	if s, ok := content["markdown"].(string); ok && s != "" {
+		return s, "text/x-markdown", true
+	}
+	if s, ok := content["plain"].(string); ok && s != "" {
+		return s, "", true
+	}
+	return "", "", false
+}
+
+func fromJSON(obj map[string]any) []models.Section {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]models.Section, 0, len(keys))
+	for _, k := range keys {
+		value, ok := obj[k].(string)
+		if !ok {
+			b, _ := json.Marshal(obj[k])
+			value = string(b)
+		}
+		out = append(out, models.Section{Name: k, RawValue: value})
+	}
+	return out
+}
+
+func fromHeadings(text, typ string) []models.Section {
+	var out []models.Section
+	for _, b := range Blocks(text, typ) {
+		if b.Level > 0 && b.Body != "" {
+			out = append(out, models.Section{Name: b.Title, RawValue: b.Body})
+		}
+	}
+	return out
+}
+
+// definition es una línea "campo: valor"; la clave no lleva ":" ni pasa de
+// 40 caracteres (así "https://…" o una frase con dos puntos no cuentan).
+var definition = regexp.MustCompile(`^([^:;\s][^:]{0,39}):\s+(.+)$`)
+
+func fromDefinitions(text string) []models.Section {
+	var out []models.Section
+	term := ""
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, ";"):
+			term = strings.TrimSpace(line[1:])
+		case strings.HasPrefix(line, ":") && term != "":
+			out = append(out, models.Section{Name: term, RawValue: strings.TrimSpace(line[1:])})
+			term = ""
+		default:
+			m := definition.FindStringSubmatch(line)
+			if m == nil {
+				return nil
+			}
+			out = append(out, models.Section{Name: strings.TrimSpace(m[1]), RawValue: m[2]})
+		}
+	}
+	if len(out) < 2 || term != "" {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+// internal/sections/sections_test.go – Tests de Parse y Blocks
+// --------------------------------------------------------------------------------
+
+package sections
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		tiddler models.Tiddler
+		want    []models.Section
+	}{
+		"encabezados wikitext": {
+			models.Tiddler{Text: "Intro.\n! Resumen\nCorto.\n!! Detalle\n\n! Requisitos\n# exportar\n# revertir"},
+			[]models.Section{{Name: "Resumen", RawValue: "Corto."}, {Name: "Requisitos", RawValue: "# exportar\n# revertir"}},
+		},
+		"encabezados Markdown": {
+			models.Tiddler{Type: "text/x-markdown", Text: "# Resumen\n![logo](a.png)\n## Requisitos\n- exportar"},
+			[]models.Section{{Name: "Resumen", RawValue: "![logo](a.png)"}, {Name: "Requisitos", RawValue: "- exportar"}},
+		},
+		"texto plano": {models.Tiddler{Type: "text/plain", Text: "! Resumen\nCorto.\n! Otro\nMás."}, nil},
+		"definiciones": {
+			models.Tiddler{Text: "estado: borrador\nresponsable: Ana\n"},
+			[]models.Section{{Name: "estado", RawValue: "borrador"}, {Name: "responsable", RawValue: "Ana"}},
+		},
+		"definiciones TiddlyWiki": {
+			models.Tiddler{Text: "; RAG\n: recuperación aumentada\n; JSONL\n: un objeto por línea"},
+			[]models.Section{{Name: "RAG", RawValue: "recuperación aumentada"}, {Name: "JSONL", RawValue: "un objeto por línea"}},
+		},
+		"objeto JSON": {
+			models.Tiddler{Type: "application/json", Text: `{"resumen":"R","requisitos":["a","b"]}`},
+			[]models.Section{{Name: "requisitos", RawValue: `["a","b"]`}, {Name: "resumen", RawValue: "R"}},
+		},
+		"envoltorio content": {
+			models.Tiddler{Text: `{"content":{"plain":"! Resumen\nDentro."}}`},
+			[]models.Section{{Name: "Resumen", RawValue: "Dentro."}},
+		},
+		"texto corrido":     {models.Tiddler{Text: "Una frase: con dos puntos.\nY otra línea normal."}, nil},
+		"una definición":    {models.Tiddler{Text: "estado: borrador"}, nil},
+		"url":               {models.Tiddler{Text: "web: https://example.org\nhttps://example.org/otra"}, nil},
+		"JSON de una clave": {models.Tiddler{Type: "application/json", Text: `{"a":1}`}, nil},
+		"vacío":             {models.Tiddler{}, nil},
+	}
+	for name, c := range cases {
+		if got := Parse(c.tiddler); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Parse = %q, want %q", name, got, c.want)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	text := "Antes\n! A\n!!! B\nb\n```\n! no\n```\n!! C\n# lista"
+	var paths [][]string
+	for _, b := range Blocks(text, "") {
+		paths = append(paths, b.Path)
+	}
+	want := [][]string{nil, {"A"}, {"A", "B"}, {"A", "C"}}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %q, want %q", paths, want)
+	}
+	if b := Blocks(text, "")[2]; b.Level != 2 || b.Body != "b\n```\n! no\n```" {
+		t.Errorf("bloque B = %+v", b)
+	}
+
+	md := "# A\n#etiqueta\n```\n# no\n```\n## B\n! no"
+	var titles []string
+	for _, b := range Blocks(md, "text/markdown") {
+		titles = append(titles, b.Title)
+	}
+	if want := []string{"A", "B"}; !reflect.DeepEqual(titles, want) {
+		t.Errorf("encabezados Markdown = %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,210 @@
+// internal/subtree/subtree.go – Subárbol de un proyecto a partir de su raíz
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// `revert -root-title` saca un único tiddler; para mover un proyecto entero a
+// otra wiki hace falta la raíz y todo lo que cuelga de ella.  Collect recorre
+// la wiki en anchura desde la raíz siguiendo cuatro tipos de arista:
+//
+//   tags       → los tiddlers etiquetados con el actual (jerarquía de tags).
+//   list       → los títulos del campo `list` del actual.
+//   links      → [[Destino]], [[texto|Destino]] y {{Destino}} en el texto.
+//   relations  → los títulos de `relations` (p.ej. define, requiere).
+//
+// Depth limita cuántos saltos se alejan de la raíz (0 = sólo la raíz; < 0 =
+// sin límite).  Los títulos referenciados que no existen en la wiki se
+// informan en Missing en lugar de fallar.
+// --------------------------------------------------------------------------------
+
+package subtree
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Aristas que se pueden seguir.
+const (
+	EdgeTags      = "tags"
+	EdgeList      = "list"
+	EdgeLinks     = "links"
+	EdgeRelations = "relations"
+)
+
+// Edges son todas las aristas, en el orden en que se recorren.
+var Edges = []string{EdgeTags, EdgeList, EdgeLinks, EdgeRelations}
+
+// Options ajusta el recorrido.
+type Options struct {
+	Depth  int      // saltos desde la raíz; < 0 = sin límite
+	Follow []string // aristas a seguir; vacío = todas
+}
+
+// Member es un tiddler del subárbol y cómo se llegó a él.
+type Member struct {
+	Title string `json:"title"`
+	Depth int    `json:"depth"`
+	Via   string `json:"via,omitempty"`  // arista por la que se llegó
+	From  string `json:"from,omitempty"` // tiddler desde el que se llegó
+}
+
+// Result es el subárbol: los tiddlers en orden de recorrido (la raíz
+// primero) y los títulos referenciados que no existen.
+type Result struct {
+	Root     string           `json:"root"`
+	Tiddlers []models.Tiddler `json:"-"`
+	Members  []Member         `json:"members"`
+	Missing  []string         `json:"missing,omitempty"`
+}
+
+// ParseEdges interpreta una lista de aristas separadas por comas ("all" =
+// todas).
+func ParseEdges(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" || s == "all" {
+		return Edges, nil
+	}
+	var out []string
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimSpace(e)
+		if !isEdge(e) {
+			return nil, fmt.Errorf("arista desconocida: %q (usa %s o all)", e, strings.Join(Edges, ", "))
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Collect reúne el subárbol de root en ts.
+func Collect(ts []models.Tiddler, root string, opts Options) (Result, error) {
+	follow := opts.Follow
+	if len(follow) == 0 {
+		follow = Edges
+	}
+	byTitle := make(map[string]models.Tiddler, len(ts))
+	tagged := make(map[string][]string) // tag → títulos etiquetados, en orden de entrada
+	for _, t := range ts {
+		if _, dup := byTitle[t.Title]; dup {
+			continue
+		}
+		byTitle[t.Title] = t
+		for _, tag := range t.TagsAsSlice() {
+			tagged[tag] = append(tagged[tag], t.Title)
+		}
+	}
+	if _, ok := byTitle[root]; !ok {
+		return Result{}, fmt.Errorf("no se encontró el tiddler raíz: %s", root)
+	}
+
+	res := Result{Root: root}
+	seen := map[string]bool{root: true}
+	missing := make(map[string]bool)
+	queue := []Member{{Title: root}}
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+		t := byTitle[m.Title]
+		res.Members = append(res.Members, m)
+		res.Tiddlers = append(res.Tiddlers, t)
+		if opts.Depth >= 0 && m.Depth >= opts.Depth {
+			continue
+		}
+		for _, edge := range follow {
+			var next []string
+			switch edge {
+			case EdgeTags:
+				next = tagged[t.Title]
+			case EdgeList:
+				if s, ok := t.ExtraFields["list"].(string); ok {
+					next = models.ParseList(s)
+				}
+			case EdgeLinks:
+				next = Links(t.Text)
+			case EdgeRelations:
+				next = relationTitles(t.Relations)
+			}
+			for _, title := range next {
+				if seen[title] {
+					continue
+				}
+				if _, ok := byTitle[title]; !ok {
+					if !missing[title] {
+						missing[title] = true
+						res.Missing = append(res.Missing, title)
+					}
+					continue
+				}
+				seen[title] = true
+				queue = append(queue, Member{Title: title, Depth: m.Depth + 1, Via: edge, From: t.Title})
+			}
+		}
+	}
+	return res, nil
+}
+
+// linkRE reconoce [[Destino]], [[texto|Destino]] y {{Destino}} (con plantilla
+// {{Destino||Plantilla}} o campo {{Destino!!campo}}).
+var linkRE = regexp.MustCompile(`\[\[([^\]]+)\]\]|\{\{([^{}|!]+)`)
+
+// Links devuelve los títulos enlazados o transcluidos en un texto, sin
+// repetir y en orden de aparición (los enlaces externos no cuentan).
+func Links(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range linkRE.FindAllStringSubmatch(text, -1) {
+		title := m[2]
+		if m[1] != "" {
+			title = m[1]
+			if i := strings.LastIndex(title, "|"); i >= 0 {
+				title = title[i+1:]
+			}
+		}
+		title = strings.TrimSpace(title)
+		if title != "" && !seen[title] && !strings.Contains(title, "://") {
+			seen[title] = true
+			out = append(out, title)
+		}
+	}
+	return out
+}
+
+// relationTitles aplana relations ({"define":["A","B"],"requiere":"C"}) en
+// una lista de títulos, con las claves en orden alfabético.  tmap.id es un
+// identificador, no un título.
+func relationTitles(rels map[string]any) []string {
+	keys := make([]string, 0, len(rels))
+	for k := range rels {
+		if k != "tmap.id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var out []string
+	for _, k := range keys {
+		switch v := rels[k].(type) {
+		case string:
+			out = append(out, models.ParseList(v)...)
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					out = append(out, s)
+				}
+			}
+		case []string:
+			out = append(out, v...)
+		}
+	}
+	return out
+}
+
+func isEdge(e string) bool {
+	for _, known := range Edges {
+		if e == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+// internal/subtree/subtree_test.go – Tests de Collect, Links y ParseEdges
+// --------------------------------------------------------------------------------
+
+package subtree
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func wiki() []models.Tiddler {
+	return []models.Tiddler{
+		{Title: "Proyecto", Text: "Ver [[Guía|Guía de uso]] y {{Resumen||plantilla}}, [[web|https://ejemplo.org]]",
+			ExtraFields: map[string]any{"list": "Capítulo [[Sin escribir]]"}},
+		{Title: "Capítulo", Text: "capítulo", Relations: map[string]any{"requiere": []any{"Glosario"}, "tmap.id": "abc"}},
+		{Title: "Tarea", Tags: "Proyecto"},
+		{Title: "Subtarea", Tags: "[[Tarea]]"},
+		{Title: "Guía de uso"},
+		{Title: "Resumen"},
+		{Title: "Glosario"},
+		{Title: "Ajeno", Text: "[[Proyecto]]"},
+	}
+}
+
+func titles(r Result) []string {
+	var out []string
+	for _, m := range r.Members {
+		out = append(out, m.Title)
+	}
+	return out
+}
+
+func TestCollect(t *testing.T) {
+	res, err := Collect(wiki(), "Proyecto", Options{Depth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Proyecto", "Tarea", "Capítulo", "Guía de uso", "Resumen", "Subtarea", "Glosario"}
+	if got := titles(res); !reflect.DeepEqual(got, want) {
+		t.Errorf("subárbol = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(res.Missing, []string{"Sin escribir"}) {
+		t.Errorf("ausentes = %v", res.Missing)
+	}
+	if m := res.Members[6]; m.Via != EdgeRelations || m.From != "Capítulo" || m.Depth != 2 {
+		t.Errorf("Glosario = %+v", m)
+	}
+	if len(res.Tiddlers) != len(res.Members) || res.Tiddlers[0].Title != "Proyecto" {
+		t.Errorf("tiddlers desalineados con members")
+	}
+}
+
+func TestCollect_DepthAndEdges(t *testing.T) {
+	res, err := Collect(wiki(), "Proyecto", Options{Depth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := titles(res); len(got) != 5 {
+		t.Errorf("profundidad 1 = %v, want la raíz y sus 4 vecinos", got)
+	}
+
+	res, err = Collect(wiki(), "Proyecto", Options{Depth: -1, Follow: []string{EdgeTags}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := titles(res), []string{"Proyecto", "Tarea", "Subtarea"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sólo tags = %v, want %v", got, want)
+	}
+
+	if _, err := Collect(wiki(), "No existe", Options{}); err == nil {
+		t.Error("raíz inexistente sin error")
+	}
+}
+
+func TestParseEdges(t *testing.T) {
+	if got, _ := ParseEdges("all"); !reflect.DeepEqual(got, Edges) {
+		t.Errorf("all = %v", got)
+	}
+	if got, _ := ParseEdges("tags, list"); !reflect.DeepEqual(got, []string{"tags", "list"}) {
+		t.Errorf("tags, list = %v", got)
+	}
+	if _, err := ParseEdges("tags,hijos"); err == nil {
+		t.Error("arista desconocida sin error")
+	}
+}
@@ -5,17 +5,28 @@
 //   • ConvertTiddlersV2 → genera []models.RecordV2   (esquema AI-friendly v2).
 //   • ConvertTiddlersV3 → genera []map[string]any    (esquema mínimo para JSONL estricto v3).
 //
+// Cada una tiene su variante por registro (ConvertTiddler, ConvertTiddlerV2,
+// ConvertTiddlerV3, ConvertTiddlerHybrid) para el pipeline en streaming: los
+// tiddlers se convierten y escriben de a uno sin materializar el slice.
+//
 // La versión v3 produce objetos JSON planos que cumplen con:
 //
 //   - Una sola línea por objeto (ideal para JSONL).
 //   - Campos esenciales: id, title, created, modified, tags, tmap.id, relations, type, text.
-//   - Fechas en RFC3339 con zona (por ejemplo "2025-06-05T15:10:00-05:00").
+//   - Fechas en RFC3339 con zona (por ejemplo "2025-06-05T15:10:00-05:00");
+//     la zona de salida se elige con Options.Location (ver dates.go).
 //   - Sin duplicación de tags ni niveles de anidación innecesarios.
 //
 // De esta manera, un JSONL estricto tendrá líneas como:
 //
 //   {"id":"_____BirdsColor","title":"_____BirdsColor","created":"2025-06-05T15:10:00-05:00", ... }
 //
+// Ningún modo descarta los campos personalizados (Tiddler.ExtraFields): v1,
+// v3 e híbrido los escriben bajo "fields" y v2 dentro de meta.extra, con su
+// tipo JSON original.  ReverseJSONLToTiddlyJSON los restaura.
+//
+// Todos los registros llevan "schema_version" con su modo (ver schema.go).
+//
 // --------------------------------------------------------------------------------
 
 package transform
@@ -24,8 +35,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"regexp"
-	"time"
 
+	"github.com/diegoabeltran16/OpenPages-Source/internal/classify"
+	"github.com/diegoabeltran16/OpenPages-Source/internal/sections"
 	"github.com/diegoabeltran16/OpenPages-Source/models"
 )
 
@@ -33,17 +45,21 @@ import (
 // Utilidades compartidas
 // -----------------------------------------------------------------------------
 
-var tagRe = regexp.MustCompile(`\[\[([^]]+)\]\]`)
+// tagRe reconoce una etiqueta TiddlyWiki: [[con espacios]] o una palabra suelta.
+var tagRe = regexp.MustCompile(`\[\[([^]]+)\]\]|(\S+)`)
 
-// parseTags extrae todas las etiquetas de la forma [[etiqueta]] de un string.
+// parseTags extrae las etiquetas de un string TiddlyWiki ("[[tag 1]] tag2"),
+// o las copia si ya vienen como lista.
 func parseTags(raw any) []string {
 	switch v := raw.(type) {
 	case string:
 		matches := tagRe.FindAllStringSubmatch(v, -1)
 		tags := make([]string, 0, len(matches))
 		for _, m := range matches {
-			if len(m) > 1 {
+			if m[1] != "" {
 				tags = append(tags, m[1])
+			} else {
+				tags = append(tags, m[2])
 			}
 		}
 		return tags
@@ -62,25 +78,17 @@ func parseTags(raw any) []string {
 	}
 }
 
-// parseTWDate intenta parsear un string TiddlyWiki (yyyymmddhhMMSS o yyyymmdd).
-// Devuelve time.Time y true si tuvo éxito; de lo contrario, time.Time{} y false.
-func parseTWDate(raw string) (time.Time, bool) {
-	layouts := []string{"20060102150405", "20060102"}
-	for _, l := range layouts {
-		if t, err := time.ParseInLocation(l, raw, time.UTC); err == nil {
-			return t, true
-		}
+// extraFields copia los campos personalizados de t; nil si no tiene ninguno
+// (así "fields" se omite en la salida).
+func extraFields(t models.Tiddler) map[string]any {
+	if len(t.ExtraFields) == 0 {
+		return nil
 	}
-	return time.Time{}, false
-}
-
-// formatISO8601 formatea un time.Time en RFC3339 con offset, p.ej. "2025-06-05T15:10:00-05:00".
-func formatISO8601(t time.Time) string {
-	// Si t es cero, usamos la hora actual
-	if t.IsZero() {
-		return time.Now().Format("2006-01-02T15:04:05-07:00")
+	fields := make(map[string]any, len(t.ExtraFields))
+	for k, v := range t.ExtraFields {
+		fields[k] = v
 	}
-	return t.Format("2006-01-02T15:04:05-07:00")
+	return fields
 }
 
 // orEmpty devuelve el valor o "" si está vacío
@@ -91,54 +99,84 @@ func orEmpty(s string) string {
 	return s
 }
 
+// Modes son los modos de conversión a JSONL, en el orden en que se documentan.
+var Modes = []string{"v1", "v2", "v3", "hybrid"}
+
+// ConverterFor devuelve el conversor por registro de mode (v1 si mode es
+// desconocido).  "tiddlywiki" deja el tiddler tal cual (como puntero, para
+// usar su MarshalJSON).
+func ConverterFor(mode string, opts Options) func(models.Tiddler) any {
+	switch mode {
+	case "v2":
+		return func(t models.Tiddler) any { return ConvertTiddlerV2With(t, opts) }
+	case "v3":
+		return func(t models.Tiddler) any { return ConvertTiddlerV3With(t, opts) }
+	case "hybrid":
+		return func(t models.Tiddler) any { return ConvertTiddlerHybrid(t) }
+	case "tiddlywiki":
+		return func(t models.Tiddler) any { return &t }
+	default:
+		return func(t models.Tiddler) any { return ConvertTiddler(t) }
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Versión 1 – lógica intacta (esquema heredado)
 // -----------------------------------------------------------------------------
 
 func ConvertTiddlers(ts []models.Tiddler) []models.Record {
 	recs := make([]models.Record, 0, len(ts))
-
 	for _, t := range ts {
-		// --- Extracción robusta de campos secundarios ---
-		created := t.Created
-		modified := t.Modified
-		color := t.Color
-
-		// Buscar en Meta si están vacíos
-		if t.Meta != nil {
-			if created == "" {
-				created = t.Meta.Created
-			}
-			if color == "" {
-				color = t.Meta.Color
-			}
-		}
+		recs = append(recs, ConvertTiddler(t))
+	}
+	return recs
+}
 
-		rec := models.Record{
-			ID:          t.Title,
-			Tags:        parseTags(t.Tags),
-			ContentType: t.Type,
-			CreatedAt:   created,
-			ModifiedAt:  modified,
-			Color:       color,
+// ConvertTiddler convierte un solo tiddler al esquema v1 (ver ConvertTiddlers).
+func ConvertTiddler(t models.Tiddler) models.Record {
+	// --- Extracción robusta de campos secundarios ---
+	created := t.Created
+	modified := t.Modified
+	color := t.Color
+
+	// Buscar en Meta si están vacíos
+	if t.Meta != nil {
+		if created == "" {
+			created = t.Meta.Created
+		}
+		if color == "" {
+			color = t.Meta.Color
 		}
+	}
 
-		if t.Type == "application/json" {
-			var buf bytes.Buffer
-			if err := json.Indent(&buf, []byte(t.Text), "", "  "); err == nil {
-				rec.TextMarkdown = buf.String()
-				rec.TextPlain = buf.String()
-			} else {
-				rec.TextMarkdown = t.Text
-				rec.TextPlain = t.Text
-			}
+	rec := models.Record{
+		SchemaVersion: models.SchemaV1,
+		ID:            t.Title,
+		Tags:          parseTags(t.Tags),
+		ContentType:   t.Type,
+		CreatedAt:     created,
+		ModifiedAt:    modified,
+		Color:         color,
+		Source:        t.Source,
+		Kind:          classify.Of(t),
+		IsSystem:      classify.IsSystem(t.Title),
+		Fields:        extraFields(t),
+	}
+
+	if t.Type == "application/json" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, []byte(t.Text), "", "  "); err == nil {
+			rec.TextMarkdown = buf.String()
+			rec.TextPlain = buf.String()
 		} else {
 			rec.TextMarkdown = t.Text
 			rec.TextPlain = t.Text
 		}
-		recs = append(recs, rec)
+	} else {
+		rec.TextMarkdown = t.Text
+		rec.TextPlain = t.Text
 	}
-	return recs
+	return rec
 }
 
 // -----------------------------------------------------------------------------
@@ -147,77 +185,98 @@ func ConvertTiddlers(ts []models.Tiddler) []models.Record {
 
 func ConvertTiddlersV2(ts []models.Tiddler) []models.RecordV2 {
 	recs := make([]models.RecordV2, 0, len(ts))
-
 	for _, t := range ts {
-		// --- Extracción robusta de campos secundarios ---
-		created := t.Created
-		modified := t.Modified
-		color := t.Color
-		tmapid := t.TmapID
-
-		// Buscar en Meta si están vacíos
-		if t.Meta != nil {
-			if created == "" {
-				created = t.Meta.Created
-			}
-			if modified == "" {
-				modified = t.Meta.Modified
+		recs = append(recs, ConvertTiddlerV2(t))
+	}
+	return recs
+}
+
+// ConvertTiddlerV2 convierte un solo tiddler al esquema v2 (ver ConvertTiddlersV2).
+func ConvertTiddlerV2(t models.Tiddler) models.RecordV2 {
+	return ConvertTiddlerV2With(t, Options{})
+}
+
+// ConvertTiddlerV2With es ConvertTiddlerV2 con opciones de conversión: las
+// fechas se expresan en opts.Location (UTC por defecto).
+func ConvertTiddlerV2With(t models.Tiddler, opts Options) models.RecordV2 {
+	// --- Extracción robusta de campos secundarios ---
+	created := t.Created
+	modified := t.Modified
+	color := t.Color
+	tmapid := t.TmapID
+
+	// Buscar en Meta si están vacíos
+	if t.Meta != nil {
+		if created == "" {
+			created = t.Meta.Created
+		}
+		if modified == "" {
+			modified = t.Meta.Modified
+		}
+		if color == "" {
+			color = t.Meta.Color
+		}
+		// Buscar en Meta.Extra
+		if t.Meta.Extra != nil {
+			if tmapid == "" {
+				tmapid = t.Meta.Extra["tmap.id"]
 			}
 			if color == "" {
-				color = t.Meta.Color
-			}
-			// Buscar en Meta.Extra
-			if t.Meta.Extra != nil {
-				if tmapid == "" {
-					tmapid = t.Meta.Extra["tmap.id"]
-				}
-				if color == "" {
-					color = t.Meta.Extra["color"]
-				}
+				color = t.Meta.Extra["color"]
 			}
 		}
+	}
 
-		// Meta
-		createdTime, _ := parseTWDate(created)
-		modifiedTime, _ := parseTWDate(modified)
-
-		meta := models.RecordMeta{
-			Title:    t.Title,
-			Tags:     parseTags(t.Tags),
-			Created:  createdTime,
-			Modified: modifiedTime,
-			Color:    color,
-			Extra: map[string]string{
-				"tmap.id": tmapid,
-			},
-		}
+	// Meta
+	createdTime := opts.inZone(created)
+	modifiedTime := opts.inZone(modified)
+
+	meta := models.RecordMeta{
+		Title:    t.Title,
+		Tags:     parseTags(t.Tags),
+		Created:  createdTime,
+		Modified: modifiedTime,
+		Color:    color,
+		Kind:     classify.Of(t),
+		IsSystem: classify.IsSystem(t.Title),
+		Extra: map[string]any{
+			"tmap.id": tmapid,
+		},
+	}
+	for k, v := range t.ExtraFields {
+		meta.Extra[k] = v
+	}
+	if t.Source != "" {
+		meta.Extra["source_wiki"] = t.Source
+	}
 
-		// Content
-		var content models.Content
-		switch t.Type {
-		case "application/json":
-			var obj map[string]any
-			if err := json.Unmarshal([]byte(t.Text), &obj); err == nil {
-				content.JSON = obj
-			} else {
-				content.Plain = t.Text
-			}
-		case "text/x-markdown":
-			content.Markdown = t.Text
-		default:
+	// Content
+	var content models.Content
+	switch t.Type {
+	case "application/json":
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(t.Text), &obj); err == nil {
+			content.JSON = obj
+		} else {
 			content.Plain = t.Text
 		}
-
-		rec := models.RecordV2{
-			ID:        t.Title,
-			Type:      "tiddler",
-			Meta:      meta,
-			Content:   content,
-			Relations: nil,
-		}
-		recs = append(recs, rec)
+	case "text/x-markdown":
+		content.Markdown = t.Text
+	default:
+		content.Plain = t.Text
 	}
-	return recs
+	// Secciones con nombre (encabezados, definiciones, claves JSON).
+	content.Sections = sections.Parse(t)
+
+	rec := models.RecordV2{
+		SchemaVersion: models.SchemaV2,
+		ID:            t.Title,
+		Type:          "tiddler",
+		Meta:          meta,
+		Content:       content,
+		Relations:     nil,
+	}
+	return rec
 }
 
 // -----------------------------------------------------------------------------
@@ -228,152 +287,189 @@ func ConvertTiddlersV2(ts []models.Tiddler) []models.RecordV2 {
 // donde cada map corresponde a un JSON plano sin saltos de línea internos.
 // Campos incluidos:
 //   - "id", "title": ambos iguales a t.Title
-//   - "created", "modified": ISO8601 con zona, o ahora si no se parsea
+//   - "created", "modified": ISO8601 con zona; si no se parsean, según
+//     Options.Fallback (por defecto, la hora actual)
 //   - "tags": []string (de parseTags)
 //   - "tmap.id": string
 //   - "relations": map[string][]string (si aplica; aquí nil)
 //   - "type": t.Type
 //   - "text": t.Text (plano o markdown)
+//   - "source_wiki": wiki de origen (sólo si se combinaron varios exports)
+//   - "fields": campos personalizados (Tiddler.ExtraFields), si los hay
+//   - "kind", "is_system": clase del tiddler (ver internal/classify)
+//   - "schema_version": "v3"
 //
 // No se duplica tags en otro nivel. Ideal para JSONL.
 func ConvertTiddlersV3(ts []models.Tiddler) []map[string]any {
 	recs := make([]map[string]any, 0, len(ts))
-
 	for _, t := range ts {
-		// 1) Fechas en time.Time
-		createdTime, okC := parseTWDate(t.Created)
-		modifiedTime, okM := parseTWDate(t.Modified)
-
-		// 2) Formatear fechas a string ISO8601
-		createdStr := formatISO8601(createdTime)
-		if !okC {
-			// Si parse falló, usamos ahora
-			createdStr = formatISO8601(time.Now())
+		recs = append(recs, ConvertTiddlerV3(t))
+	}
+	return recs
+}
+
+// ConvertTiddlerV3 convierte un solo tiddler al esquema v3 (ver ConvertTiddlersV3).
+func ConvertTiddlerV3(t models.Tiddler) map[string]any {
+	return ConvertTiddlerV3With(t, Options{})
+}
+
+// ConvertTiddlerV3With es ConvertTiddlerV3 con opciones de conversión.
+func ConvertTiddlerV3With(t models.Tiddler, opts Options) map[string]any {
+	// 1-2) Fechas a string ISO8601 (o respaldo según opts.Fallback)
+	createdStr, okC := opts.isoDate(t.Created)
+	modifiedStr, okM := opts.isoDate(t.Modified)
+
+	// 3) Extraer tags y tags_list
+	tags := parseTags(t.Tags)
+	var tagsList []string
+	if t.TagsList != nil {
+		tagsList = t.TagsList
+	} else {
+		tagsList = []string{}
+	}
+
+	// --- Extracción robusta de campos secundarios ---
+	created := t.Created
+	modified := t.Modified
+	color := t.Color
+	tmapid := t.TmapID
+	path := t.Path
+
+	// Buscar en Meta si están vacíos
+	if t.Meta != nil {
+		if created == "" {
+			created = t.Meta.Created
 		}
-		modifiedStr := formatISO8601(modifiedTime)
-		if !okM {
-			modifiedStr = formatISO8601(time.Now())
+		if modified == "" {
+			modified = t.Meta.Modified
 		}
-
-		// 3) Extraer tags y tags_list
-		tags := parseTags(t.Tags)
-		var tagsList []string
-		if t.TagsList != nil {
-			tagsList = t.TagsList
-		} else {
-			tagsList = []string{}
+		if color == "" {
+			color = t.Meta.Color
 		}
-
-		// --- Extracción robusta de campos secundarios ---
-		created := t.Created
-		modified := t.Modified
-		color := t.Color
-		tmapid := t.TmapID
-		path := t.Path
-
-		// Buscar en Meta si están vacíos
-		if t.Meta != nil {
-			if created == "" {
-				created = t.Meta.Created
-			}
-			if modified == "" {
-				modified = t.Meta.Modified
+		// Buscar en Meta.Extra
+		if t.Meta.Extra != nil {
+			if tmapid == "" {
+				tmapid = t.Meta.Extra["tmap.id"]
 			}
 			if color == "" {
-				color = t.Meta.Color
+				color = t.Meta.Extra["color"]
 			}
-			// Buscar en Meta.Extra
-			if t.Meta.Extra != nil {
-				if tmapid == "" {
-					tmapid = t.Meta.Extra["tmap.id"]
-				}
-				if color == "" {
-					color = t.Meta.Extra["color"]
-				}
-				if path == "" {
-					path = t.Meta.Extra["path"]
-				}
+			if path == "" {
+				path = t.Meta.Extra["path"]
 			}
 		}
+	}
 
-		// 4) Construir el objeto JSON mínimo y robusto
-		obj := map[string]any{
-			"id":           t.Title,
-			"title":        t.Title,
-			"created":      createdStr,        // <-- ahora RFC3339
-			"created_raw":  orEmpty(created),  // <-- opcional, el crudo
-			"modified":     modifiedStr,       // <-- ahora RFC3339
-			"modified_raw": orEmpty(modified), // <-- opcional, el crudo
-			"tags":         tags,
-			"tags_list":    tagsList,
-			"tmap.id":      orEmpty(tmapid),
-			"type":         orEmpty(t.Type),
-			"text":         GetTextContent(t.Text),
-			"color":        orEmpty(color),
-			"path":         orEmpty(path),
-		}
+	// 4) Construir el objeto JSON mínimo y robusto
+	obj := map[string]any{
+		"id":             t.Title,
+		"title":          t.Title,
+		"created":        createdStr,        // <-- ahora RFC3339
+		"created_raw":    orEmpty(created),  // <-- opcional, el crudo
+		"modified":       modifiedStr,       // <-- ahora RFC3339
+		"modified_raw":   orEmpty(modified), // <-- opcional, el crudo
+		"tags":           tags,
+		"tags_list":      tagsList,
+		"tmap.id":        orEmpty(tmapid),
+		"type":           orEmpty(t.Type),
+		"text":           GetTextContent(t.Text),
+		"color":          orEmpty(color),
+		"path":           orEmpty(path),
+		"kind":           classify.Of(t),
+		"is_system":      classify.IsSystem(t.Title),
+		"schema_version": models.SchemaV3,
+	}
 
-		// 5) Relaciones explícitas si aplica
-		if t.Relations != nil {
-			obj["relations"] = t.Relations
-		} else {
-			obj["relations"] = map[string]any{}
-		}
+	if !okC {
+		delete(obj, "created")
+	}
+	if !okM {
+		delete(obj, "modified")
+	}
+	if t.Source != "" {
+		obj["source_wiki"] = t.Source
+	}
+	if fields := extraFields(t); fields != nil {
+		obj["fields"] = fields
+	}
 
-		recs = append(recs, obj)
+	// 5) Relaciones explícitas si aplica
+	if t.Relations != nil {
+		obj["relations"] = t.Relations
+	} else {
+		obj["relations"] = map[string]any{}
 	}
-	return recs
+	return obj
 }
 
 // ConvertTiddlersHybrid genera un slice de objetos planos ideales para IA/RAG.
 func ConvertTiddlersHybrid(ts []models.Tiddler) []models.Record {
 	recs := make([]models.Record, 0, len(ts))
 	for _, t := range ts {
-		// --- Extracción robusta de campos secundarios ---
-		created := t.Created
-		modified := t.Modified
-		color := t.Color
-
-		// Buscar en Meta si están vacíos
-		if t.Meta != nil {
-			if created == "" {
-				created = t.Meta.Created
-			}
-			if modified == "" {
-				modified = t.Meta.Modified
-			}
-			if color == "" {
-				color = t.Meta.Color
-			}
-		}
+		recs = append(recs, ConvertTiddlerHybrid(t))
+	}
+	return recs
+}
 
-		rec := models.Record{
-			ID:           t.Title,
-			Tags:         parseTags(t.Tags),
-			ContentType:  t.Type,
-			TextMarkdown: GetTextContent(t.Text),
-			TextPlain:    GetTextContent(t.Text),
-			CreatedAt:    created,
-			ModifiedAt:   modified,
-			Color:        color,
+// ConvertTiddlerHybrid convierte un solo tiddler al esquema híbrido.
+func ConvertTiddlerHybrid(t models.Tiddler) models.Record {
+	// --- Extracción robusta de campos secundarios ---
+	created := t.Created
+	modified := t.Modified
+	color := t.Color
+
+	// Buscar en Meta si están vacíos
+	if t.Meta != nil {
+		if created == "" {
+			created = t.Meta.Created
+		}
+		if modified == "" {
+			modified = t.Meta.Modified
+		}
+		if color == "" {
+			color = t.Meta.Color
 		}
-		recs = append(recs, rec)
 	}
-	return recs
+
+	text := GetTextContent(t.Text)
+	rec := models.Record{
+		SchemaVersion: models.SchemaHybrid,
+		ID:            t.Title,
+		Tags:          parseTags(t.Tags),
+		ContentType:   t.Type,
+		TextMarkdown:  text,
+		TextPlain:     text,
+		CreatedAt:     created,
+		ModifiedAt:    modified,
+		Color:         color,
+		Source:        t.Source,
+		Kind:          classify.Of(t),
+		IsSystem:      classify.IsSystem(t.Title),
+		Fields:        extraFields(t),
+	}
+	return rec
+}
+
+// textWrapper es la forma {"content":{"plain":…,"markdown":…}} que reconoce
+// GetTextContent.  Decodificar a un struct (y no a map[string]any) evita
+// reservar memoria para el resto de las claves del objeto.
+type textWrapper struct {
+	Content *struct {
+		Plain    any `json:"plain"`
+		Markdown any `json:"markdown"`
+	} `json:"content"`
 }
 
 // GetTextContent extrae el texto del contenido, manejando tanto JSON como texto plano
 func GetTextContent(text string) string {
 	if len(text) > 0 && text[0] == '{' && text[len(text)-1] == '}' {
-		var w map[string]any
-		if err := json.Unmarshal([]byte(text), &w); err == nil {
-			if c, ok := w["content"].(map[string]any); ok {
-				if plain, ok := c["plain"].(string); ok && plain != "" {
-					return plain
-				}
-				if markdown, ok := c["markdown"].(string); ok && markdown != "" {
-					return markdown
-				}
+		var w textWrapper
+		if err := json.Unmarshal([]byte(text), &w); err == nil && w.Content != nil {
+			if plain, ok := w.Content.Plain.(string); ok && plain != "" {
+				return plain
+			}
+			if markdown, ok := w.Content.Markdown.(string); ok && markdown != "" {
+				return markdown
 			}
 		}
 	}
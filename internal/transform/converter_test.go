@@ -1,16 +1,18 @@
 // internal/transform/converter_test.go – Tests para transform.ConvertTiddlers, ConvertTiddlersV2 y ConvertTiddlersV3
 // --------------------------------------------------------------------------------
-// Estas pruebas viven en el **mismo paquete** (`transform`) para acceder a parseTags y parseTWDate.
+// Estas pruebas viven en el **mismo paquete** (`transform`) para acceder a parseTags y ParseTWDate.
 // Verifican:
 //   1. Extracción correcta de etiquetas con parseTags.
 //   2. ConvertTiddlers (v1): Tiddler → models.Record.
 //   3. ConvertTiddlersV2 (v2): Tiddler → models.RecordV2, campos meta/content.
 //   4. ConvertTiddlersV3 (v3): Tiddler → map[string]any minimalista para JSONL.
+//   5. Campos personalizados (ExtraFields) en todos los modos y de vuelta.
 // --------------------------------------------------------------------------------
 
 package transform
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -21,12 +23,15 @@ import (
 // ----------------------------- parseTags -----------------------------
 // Test_parseTags comprueba la extracción de etiquetas, incluyendo espacios.
 func Test_parseTags(t *testing.T) {
-	raw := "[[tag1]] [[tag 2]] [[tag3]]"
 	want := []string{"tag1", "tag 2", "tag3"}
-
-	got := parseTags(raw)
-	if !reflect.DeepEqual(got, want) {
-		t.Errorf("parseTags(%q) = %v, want %v", raw, got, want)
+	for _, raw := range []string{
+		"[[tag1]] [[tag 2]] [[tag3]]",
+		"[[tag1]] [[tag 2]] tag3", // palabra suelta sin corchetes
+	} {
+		got := parseTags(raw)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("parseTags(%q) = %v, want %v", raw, got, want)
+		}
 	}
 }
 
@@ -53,22 +58,26 @@ func TestConvertTiddlers(t *testing.T) {
 
 	want := []models.Record{
 		{
-			ID:           "Foo",
-			Tags:         []string{"a", "b"},
-			ContentType:  "text/plain",
-			TextMarkdown: "plain text",
-			TextPlain:    "plain text",
-			CreatedAt:    "20250101",
-			ModifiedAt:   "20250102",
+			SchemaVersion: models.SchemaV1,
+			ID:            "Foo",
+			Tags:          []string{"a", "b"},
+			ContentType:   "text/plain",
+			TextMarkdown:  "plain text",
+			TextPlain:     "plain text",
+			CreatedAt:     "20250101",
+			ModifiedAt:    "20250102",
+			Kind:          "content",
 		},
 		{
-			ID:           "Bar",
-			Tags:         []string{"x"},
-			ContentType:  "application/json",
-			TextMarkdown: "{\n  \"key\": \"value\"\n}",
-			TextPlain:    "{\n  \"key\": \"value\"\n}",
-			CreatedAt:    "20250103",
-			ModifiedAt:   "20250104",
+			SchemaVersion: models.SchemaV1,
+			ID:            "Bar",
+			Tags:          []string{"x"},
+			ContentType:   "application/json",
+			TextMarkdown:  "{\n  \"key\": \"value\"\n}",
+			TextPlain:     "{\n  \"key\": \"value\"\n}",
+			CreatedAt:     "20250103",
+			ModifiedAt:    "20250104",
+			Kind:          "content",
 		},
 	}
 
@@ -113,7 +122,7 @@ func TestConvertTiddlersV2(t *testing.T) {
 	if m0.Title != "Alpha" {
 		t.Errorf("v2 Meta.Title = %q, want %q", m0.Title, "Alpha")
 	}
-	// parseTWDate("20250101") → 2025-01-01 00:00:00 UTC
+	// ParseTWDate("20250101") → 2025-01-01 00:00:00 UTC
 	expectedCreated0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	if !m0.Created.Equal(expectedCreated0) {
 		t.Errorf("v2 Meta.Created = %v, want %v", m0.Created, expectedCreated0)
@@ -139,6 +148,16 @@ func TestConvertTiddlersV2(t *testing.T) {
 	if val, ok := c1["field"]; !ok || val.(float64) != 123 {
 		t.Errorf("v2 Content.JSON[\"field\"] = %v, want 123", val)
 	}
+	if recs[0].Content.Sections != nil {
+		t.Errorf("v2 Content.Sections de texto corrido = %v, want nil", recs[0].Content.Sections)
+	}
+
+	// Texto con encabezados: una sección por encabezado.
+	rec := ConvertTiddlerV2(models.Tiddler{Title: "Gamma", Text: "! Resumen\nR\n! Requisitos\nQ"})
+	wantSections := []models.Section{{Name: "Resumen", RawValue: "R"}, {Name: "Requisitos", RawValue: "Q"}}
+	if !reflect.DeepEqual(rec.Content.Sections, wantSections) {
+		t.Errorf("v2 Content.Sections = %v, want %v", rec.Content.Sections, wantSections)
+	}
 }
 
 // ----------------------------- ConvertTiddlersV3 (v3) -----------------------------
@@ -175,7 +194,7 @@ func TestConvertTiddlersV3(t *testing.T) {
 	// Primer objeto: fechas parseadas en RFC3339
 	obj0 := recs[0]
 	// created "20250301" → 2025-03-01T00:00:00Z (UTC) formateado con offset
-	parsedCreated0, _ := parseTWDate("20250301")
+	parsedCreated0, _ := ParseTWDate("20250301")
 	want0 := parsedCreated0.Format("2006-01-02T15:04:05-07:00")
 	if obj0["created"] != want0 {
 		t.Errorf("v3 obj0[\"created\"] = %q, want %q", obj0["created"], want0)
@@ -224,10 +243,54 @@ func TestConvertTiddlersV3(t *testing.T) {
 	}
 }
 
-// ----------------------------- parseTWDate fallback -----------------------------
-// Asegura que parseTWDate retorne (Time{}, false) si el formato no coincide.
+// ----------------------------- Campos personalizados -----------------------------
+// Los ExtraFields llegan a todos los modos con su tipo y vuelven con el reverso.
+func TestConvert_ExtraFields(t *testing.T) {
+	var td models.Tiddler
+	raw := `{"title":"Nota","text":"hola","caption":"Mi nota","status":"draft","priority":3,"reviewed":true}`
+	if err := json.Unmarshal([]byte(raw), &td); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"caption": "Mi nota", "status": "draft", "priority": float64(3), "reviewed": true}
+
+	if got := ConvertTiddler(td).Fields; !reflect.DeepEqual(got, want) {
+		t.Errorf("v1 Fields = %v, want %v", got, want)
+	}
+	if got := ConvertTiddlerHybrid(td).Fields; !reflect.DeepEqual(got, want) {
+		t.Errorf("hybrid Fields = %v, want %v", got, want)
+	}
+	extra := ConvertTiddlerV2(td).Meta.Extra
+	for k, v := range want {
+		if extra[k] != v {
+			t.Errorf("v2 meta.extra[%q] = %v (%T), want %v", k, extra[k], extra[k], v)
+		}
+	}
+	obj := ConvertTiddlerV3(td)
+	if got := obj["fields"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("v3 fields = %v, want %v", got, want)
+	}
+
+	// Sin campos personalizados no aparece "fields".
+	if _, ok := ConvertTiddlerV3(models.Tiddler{Title: "X"})["fields"]; ok {
+		t.Error("v3 escribió fields vacío")
+	}
+
+	// v3 → JSONL → Tiddler conserva los campos y sus tipos.
+	data, _ := json.Marshal(obj)
+	var rec map[string]any
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatal(err)
+	}
+	back, _ := RecordToTiddler(rec)
+	if !reflect.DeepEqual(back.ExtraFields, want) {
+		t.Errorf("reverso ExtraFields = %v, want %v", back.ExtraFields, want)
+	}
+}
+
+// ----------------------------- ParseTWDate fallback -----------------------------
+// Asegura que ParseTWDate retorne (Time{}, false) si el formato no coincide.
 func Test_parseTWDate_Invalid(t *testing.T) {
-	if _, ok := parseTWDate("notadate"); ok {
-		t.Errorf("parseTWDate(\"notadate\") devolvió ok=true, se esperaba false")
+	if _, ok := ParseTWDate("notadate"); ok {
+		t.Errorf("ParseTWDate(\"notadate\") devolvió ok=true, se esperaba false")
 	}
 }
@@ -0,0 +1,118 @@
+// internal/transform/dates.go – Fechas TiddlyWiki ⇄ ISO8601 con zona horaria
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// TiddlyWiki guarda las fechas en UTC como dígitos, sin separadores:
+//
+//   20250605151000123   → 17 dígitos: yyyymmddhhMMSSmmm (formato nativo)
+//   20250605151000      → 14 dígitos: yyyymmddhhMMSS   (exports antiguos)
+//   20250605            →  8 dígitos: yyyymmdd          (escritos a mano)
+//
+// Todas las conversiones de fecha del paquete pasan por aquí:
+//
+//   • ParseTWDate   → dígitos TiddlyWiki → time.Time en UTC (con milisegundos).
+//   • formatISO8601 → time.Time → "2025-06-05T10:10:00.123-05:00" en la zona
+//                     pedida (UTC por defecto); los milisegundos sólo aparecen
+//                     si no son cero.
+//   • parseRFC3339ToTW → el camino inverso: cualquier offset se normaliza a
+//                     UTC y se escriben 17 dígitos si hay milisegundos.
+//
+// Así un valor sobrevive intacto el viaje TiddlyWiki → v3 → TiddlyWiki sin
+// importar la zona elegida para la salida (ver twDateFromRecord en reverse.go).
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layouts de fecha TiddlyWiki, del más al menos preciso.
+const (
+	twLayoutMillis = "20060102150405.000" // se usa sin el punto (ver ParseTWDate)
+	twLayout       = "20060102150405"
+	twLayoutDay    = "20060102"
+	isoLayout      = "2006-01-02T15:04:05-07:00"
+	isoLayoutMilli = "2006-01-02T15:04:05.000-07:00"
+)
+
+// ParseTWDate intenta parsear un string TiddlyWiki (17, 14 u 8 dígitos) como
+// UTC.  Devuelve time.Time y true si tuvo éxito; de lo contrario, time.Time{}
+// y false.
+func ParseTWDate(raw string) (time.Time, bool) {
+	switch len(raw) {
+	case 17:
+		// Go no admite milisegundos sin separador: insertamos el punto.
+		if t, err := time.ParseInLocation(twLayoutMillis, raw[:14]+"."+raw[14:], time.UTC); err == nil {
+			return t, true
+		}
+	case 14:
+		if t, err := time.ParseInLocation(twLayout, raw, time.UTC); err == nil {
+			return t, true
+		}
+	case 8:
+		if t, err := time.ParseInLocation(twLayoutDay, raw, time.UTC); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// FormatTWDate formatea t como fecha TiddlyWiki en UTC: 17 dígitos si t tiene
+// milisegundos, 14 si no.
+func FormatTWDate(t time.Time) string {
+	t = t.UTC()
+	if t.Nanosecond()/int(time.Millisecond) != 0 {
+		return strings.Replace(t.Format(twLayoutMillis), ".", "", 1)
+	}
+	return t.Format(twLayout)
+}
+
+// formatISO8601 formatea t en RFC3339 con offset en la zona loc (nil = UTC),
+// p.ej. "2025-06-05T15:10:00-05:00".  Los milisegundos se incluyen sólo si no
+// son cero.  Si t es cero, usa la hora actual.
+func formatISO8601(t time.Time, loc *time.Location) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+	if t.Nanosecond()/int(time.Millisecond) != 0 {
+		return t.Format(isoLayoutMilli)
+	}
+	return t.Format(isoLayout)
+}
+
+// parseRFC3339ToTW convierte una fecha RFC3339 (con cualquier offset o "Z",
+// con o sin fracción de segundo) de vuelta al formato TiddlyWiki en UTC.
+func parseRFC3339ToTW(rfc3339Str string) (string, error) {
+	t, err := time.Parse(time.RFC3339Nano, rfc3339Str)
+	if err != nil {
+		return "", fmt.Errorf("formato de fecha no reconocido: %s", rfc3339Str)
+	}
+	return FormatTWDate(t), nil
+}
+
+// ParseLocation interpreta el valor de un flag -tz: "" o "UTC", "Local", un
+// nombre IANA ("America/Bogota") o un offset fijo ("-05:00").
+func ParseLocation(s string) (*time.Location, error) {
+	switch s {
+	case "", "UTC", "utc", "Z":
+		return time.UTC, nil
+	case "Local", "local":
+		return time.Local, nil
+	}
+	if t, err := time.Parse("-07:00", s); err == nil {
+		_, offset := t.Zone()
+		return time.FixedZone(s, offset), nil
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, fmt.Errorf("zona horaria inválida: %q (usa UTC, Local, un nombre IANA o un offset como -05:00)", s)
+	}
+	return loc, nil
+}
@@ -0,0 +1,112 @@
+// internal/transform/dates_test.go – Tests del módulo de fechas
+package transform
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestParseTWDate_Precisiones(t *testing.T) {
+	cases := map[string]time.Time{
+		"20250605151000123": time.Date(2025, 6, 5, 15, 10, 0, 123e6, time.UTC),
+		"20250605151000":    time.Date(2025, 6, 5, 15, 10, 0, 0, time.UTC),
+		"20250605":          time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC),
+	}
+	for raw, want := range cases {
+		got, ok := ParseTWDate(raw)
+		if !ok || !got.Equal(want) || got.Location() != time.UTC {
+			t.Errorf("ParseTWDate(%q) = %v, %v; want %v UTC", raw, got, ok, want)
+		}
+	}
+	for _, bad := range []string{"", "2025060515100", "2025-06-05", "20251305151000"} {
+		if _, ok := ParseTWDate(bad); ok {
+			t.Errorf("ParseTWDate(%q) devolvió ok=true", bad)
+		}
+	}
+}
+
+func TestFormatISO8601_Zonas(t *testing.T) {
+	ts, _ := ParseTWDate("20250605151000123")
+	bogota, err := ParseLocation("America/Bogota")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed, err := ParseLocation("+02:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{nil, "2025-06-05T15:10:00.123+00:00"},
+		{bogota, "2025-06-05T10:10:00.123-05:00"},
+		{fixed, "2025-06-05T17:10:00.123+02:00"},
+	}
+	for _, c := range cases {
+		if got := formatISO8601(ts, c.loc); got != c.want {
+			t.Errorf("formatISO8601(%v) = %q, want %q", c.loc, got, c.want)
+		}
+	}
+	whole, _ := ParseTWDate("20250605151000")
+	if got := formatISO8601(whole, nil); got != "2025-06-05T15:10:00+00:00" {
+		t.Errorf("sin milisegundos: %q", got)
+	}
+}
+
+func TestParseRFC3339ToTW_NormalizaUTC(t *testing.T) {
+	cases := map[string]string{
+		"2025-06-05T10:10:00-05:00":     "20250605151000",
+		"2025-06-05T15:10:00Z":          "20250605151000",
+		"2025-06-05T17:10:00.123+02:00": "20250605151000123",
+	}
+	for in, want := range cases {
+		got, err := parseRFC3339ToTW(in)
+		if err != nil || got != want {
+			t.Errorf("parseRFC3339ToTW(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := parseRFC3339ToTW("ayer"); err == nil {
+		t.Error("parseRFC3339ToTW(\"ayer\") no devolvió error")
+	}
+}
+
+// El viaje TiddlyWiki → v3 → TiddlyWiki conserva las fechas exactas en
+// cualquier zona de salida, aun sin los campos *_raw.
+func TestRoundTripFechas(t *testing.T) {
+	bogota, _ := ParseLocation("America/Bogota")
+	for _, loc := range []*time.Location{nil, bogota} {
+		for _, raw := range []string{"20250605151000123", "20250605151000", "20250605"} {
+			td := models.Tiddler{Title: "T", Created: raw, Modified: raw}
+			rec := ConvertTiddlerV3With(td, Options{Location: loc})
+
+			data, _ := json.Marshal(rec)
+			var back map[string]any
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := RecordToTiddler(back)
+			if got.Created != raw || got.Modified != raw {
+				t.Errorf("zona %v, %q: volvió created=%q modified=%q", loc, raw, got.Created, got.Modified)
+			}
+
+			// Sin *_raw se recupera el instante (14 o 17 dígitos).
+			delete(back, "created_raw")
+			got, _ = RecordToTiddler(back)
+			want, _ := ParseTWDate(raw)
+			if back, ok := ParseTWDate(got.Created); !ok || !back.Equal(want) {
+				t.Errorf("zona %v, %q sin raw: volvió %q", loc, raw, got.Created)
+			}
+		}
+	}
+}
+
+func TestParseLocation_Invalida(t *testing.T) {
+	if _, err := ParseLocation("Marte/Olympus"); err == nil {
+		t.Error("ParseLocation aceptó una zona inexistente")
+	}
+}
@@ -0,0 +1,261 @@
+// internal/transform/normalize.go – Cualquier entrada → JSONL v3
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Un mismo corpus puede llegar en varias formas:
+//
+//   1. Array TiddlyWiki   → `[ {"title":…}, … ]`
+//   2. Mapa TiddlyWiki    → `{ "título": {…}, … }`      (en el orden del archivo)
+//   3. JSONL de cualquier modo (v1, v2, v3, hybrid) o de tiddlers crudos.
+//
+// NormalizeToV3 detecta la forma, lleva cada registro a models.Tiddler
+// (RecordToTiddler si trae "id", el tiddler tal cual si no) y escribe una
+// línea v3 por tiddler.  Un registro ilegible no detiene el proceso ni se
+// descarta en silencio: se escribe el resto y se devuelve *SkippedError con
+// la línea (JSONL) o la posición (array/mapa) de cada problema.  Los
+// fragmentos de un export con -chunk-tokens se omiten con un aviso.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// LineError describe un registro de entrada que no se pudo interpretar.
+// Line es la línea en JSONL o la posición (desde 1) en un array o mapa.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string { return fmt.Sprintf("línea %d: %v", e.Line, e.Err) }
+
+// SkippedError indica que algunos registros no se pudieron interpretar; la
+// salida contiene todos los demás.
+type SkippedError struct {
+	Total int // registros leídos, válidos o no
+	Lines []LineError
+}
+
+func (e *SkippedError) Error() string {
+	parts := make([]string, 0, len(e.Lines))
+	for _, l := range e.Lines {
+		parts = append(parts, l.Error())
+	}
+	return fmt.Sprintf("%d de %d registros no se pudieron interpretar: %s",
+		len(e.Lines), e.Total, strings.Join(parts, "; "))
+}
+
+// rawRecord es un registro de entrada todavía sin interpretar.
+type rawRecord struct {
+	line int
+	data json.RawMessage
+}
+
+// NormalizeToV3 lee r en cualquiera de las formas admitidas y escribe en w
+// una línea JSONL v3 por tiddler.  Devuelve la cantidad de líneas escritas;
+// si hubo registros ilegibles el error es *SkippedError.
+func NormalizeToV3(r io.Reader, w io.Writer, opts Options) (int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("leyendo entrada: %w", err)
+	}
+	records, skipped, err := splitRecords(data)
+	if err != nil {
+		return 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	written, fragments := 0, 0
+	for _, rec := range records {
+		t, err := rawToTiddler(rec.data)
+		if errors.Is(err, ErrFragment) {
+			fragments++
+			continue
+		}
+		if err != nil {
+			skipped = append(skipped, LineError{Line: rec.line, Err: err})
+			continue
+		}
+		if err := enc.Encode(ConvertTiddlerV3With(t, opts)); err != nil {
+			return written, fmt.Errorf("escribiendo línea %d: %w", written+1, err)
+		}
+		written++
+	}
+	if err := bw.Flush(); err != nil {
+		return written, err
+	}
+	if err := fragmentsSkipped(fragments, written+len(skipped)); err != nil {
+		return written, err
+	}
+	if len(skipped) > 0 {
+		sort.Slice(skipped, func(i, j int) bool { return skipped[i].Line < skipped[j].Line })
+		return written, &SkippedError{Total: written + len(skipped), Lines: skipped}
+	}
+	return written, nil
+}
+
+// splitRecords separa data en registros según su forma.  Los errores de
+// sintaxis de líneas JSONL se devuelven en skipped; los de un array o mapa
+// mal formado invalidan el documento completo.
+func splitRecords(data []byte) (records []rawRecord, skipped []LineError, err error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, nil, nil
+	}
+
+	// Un solo documento JSON: array TiddlyWiki, mapa TiddlyWiki o un único
+	// registro (JSONL de una línea).
+	if json.Valid(trimmed) {
+		switch trimmed[0] {
+		case '[':
+			var arr []json.RawMessage
+			if err := json.Unmarshal(trimmed, &arr); err != nil {
+				return nil, nil, fmt.Errorf("array JSON: %w", err)
+			}
+			for i, r := range arr {
+				records = append(records, rawRecord{line: i + 1, data: r})
+			}
+			return records, nil, nil
+		case '{':
+			if isRecord(trimmed) {
+				return []rawRecord{{line: 1, data: trimmed}}, nil, nil
+			}
+			records, err := mapValues(trimmed)
+			return records, nil, err
+		}
+	} else if trimmed[0] == '[' {
+		return nil, nil, errors.New("array JSON mal formado")
+	}
+
+	// JSONL: un registro por línea.
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			var v any
+			skipped = append(skipped, LineError{Line: i + 1, Err: json.Unmarshal(line, &v)})
+			continue
+		}
+		records = append(records, rawRecord{line: i + 1, data: append(json.RawMessage(nil), line...)})
+	}
+	return records, skipped, nil
+}
+
+// isRecord distingue un registro suelto ({"title":…} o {"id":…}) de un mapa
+// TiddlyWiki, cuyos valores son todos objetos.
+func isRecord(obj []byte) bool {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(obj, &m); err != nil {
+		return false
+	}
+	for _, key := range []string{"id", "title"} {
+		var s string
+		if v, ok := m[key]; ok && json.Unmarshal(v, &s) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// mapValues devuelve los valores de un mapa TiddlyWiki en el orden del archivo.
+func mapValues(obj []byte) ([]rawRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if _, err := dec.Token(); err != nil { // '{'
+		return nil, err
+	}
+	var records []rawRecord
+	for i := 1; dec.More(); i++ {
+		if _, err := dec.Token(); err != nil { // clave (título)
+			return nil, fmt.Errorf("clave del tiddler %d: %w", i, err)
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("tiddler %d: %w", i, err)
+		}
+		records = append(records, rawRecord{line: i, data: v})
+	}
+	return records, nil
+}
+
+// rawToTiddler interpreta un registro: con "id" es un registro exportado
+// (RecordToTiddler); sin él, un tiddler crudo de TiddlyWiki.
+func rawToTiddler(data json.RawMessage) (models.Tiddler, error) {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return models.Tiddler{}, errors.New("el registro no es un objeto JSON")
+	}
+	var t models.Tiddler
+	if _, ok := m["id"]; ok {
+		var err error
+		if t, err = RecordToTiddler(m); err != nil {
+			return t, err
+		}
+	} else if err := json.Unmarshal(data, &t); err != nil {
+		return t, err
+	}
+	if strings.TrimSpace(t.Title) == "" {
+		return t, errors.New("registro sin título")
+	}
+	return t, nil
+}
+
+// ReadRecords lee r en cualquiera de las formas de NormalizeToV3 y devuelve
+// los tiddlers junto con el esquema del primer registro (DetectSchema, o
+// "tiddlywiki" si la entrada son tiddlers crudos).  Si hubo registros
+// ilegibles devuelve los demás y un *SkippedError; los fragmentos se omiten.
+func ReadRecords(r io.Reader) ([]models.Tiddler, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("leyendo entrada: %w", err)
+	}
+	records, skipped, err := splitRecords(data)
+	if err != nil {
+		return nil, "", err
+	}
+	var tiddlers []models.Tiddler
+	schema := ""
+	fragments := 0
+	for _, rec := range records {
+		t, err := rawToTiddler(rec.data)
+		if errors.Is(err, ErrFragment) {
+			fragments++
+			continue
+		}
+		if err != nil {
+			skipped = append(skipped, LineError{Line: rec.line, Err: err})
+			continue
+		}
+		if schema == "" {
+			schema = "tiddlywiki"
+			var m map[string]any
+			if json.Unmarshal(rec.data, &m) == nil {
+				if _, ok := m["id"]; ok {
+					schema = DetectSchema(m)
+				}
+			}
+		}
+		tiddlers = append(tiddlers, t)
+	}
+	if err := fragmentsSkipped(fragments, len(tiddlers)+len(skipped)); err != nil {
+		return nil, "", err
+	}
+	if len(skipped) > 0 {
+		sort.Slice(skipped, func(i, j int) bool { return skipped[i].Line < skipped[j].Line })
+		return tiddlers, schema, &SkippedError{Total: len(tiddlers) + len(skipped), Lines: skipped}
+	}
+	return tiddlers, schema, nil
+}
@@ -0,0 +1,101 @@
+// internal/transform/normalize_test.go – Tests de NormalizeToV3
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// normalizeTitles normaliza input y devuelve los títulos escritos.
+func normalizeTitles(t *testing.T, input string) ([]string, error) {
+	t.Helper()
+	var out bytes.Buffer
+	n, err := NormalizeToV3(strings.NewReader(input), &out, Options{Fallback: FallbackOmit})
+	var titles []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if jerr := json.Unmarshal([]byte(line), &rec); jerr != nil {
+			t.Fatalf("línea de salida inválida %q: %v", line, jerr)
+		}
+		if _, ok := rec["id"]; !ok {
+			t.Errorf("la salida no es v3: %s", line)
+		}
+		titles = append(titles, rec["title"].(string))
+	}
+	if n != len(titles) {
+		t.Errorf("NormalizeToV3 informó %d líneas, se escribieron %d", n, len(titles))
+	}
+	return titles, err
+}
+
+func TestNormalizeToV3_Formas(t *testing.T) {
+	v1 := `{"id":"a1","title":"Uno","textPlain":"x","createdAt":"2025-01-01T00:00:00Z","modifiedAt":"2025-01-01T00:00:00Z"}`
+	v3 := `{"id":"b2","title":"Dos","text":"y","tags":["t"],"created":"2025-01-01T00:00:00Z","modified":"2025-01-01T00:00:00Z"}`
+	cases := map[string]struct {
+		input string
+		want  []string
+	}{
+		"array":          {`[{"title":"B","text":"1"},{"title":"A","text":"2"}]`, []string{"B", "A"}},
+		"mapa en orden":  {`{"Zeta":{"title":"Zeta"},"Alfa":{"title":"Alfa"}}`, []string{"Zeta", "Alfa"}},
+		"jsonl de modos": {v1 + "\n" + v3 + "\n" + `{"title":"Crudo","text":"z"}` + "\n", []string{"Uno", "Dos", "Crudo"}},
+		"registro único": {v3, []string{"Dos"}},
+		"vacío":          {" \n", nil},
+	}
+	for name, c := range cases {
+		got, err := normalizeTitles(t, c.input)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: títulos = %v, want %v", name, got, c.want)
+		}
+	}
+}
+
+// Las líneas ilegibles se informan con su número y no detienen el resto.
+func TestNormalizeToV3_LineasOmitidas(t *testing.T) {
+	input := `{"title":"A"}
+{roto
+{"text":"sin título"}
+
+{"title":"B"}
+[1,2]
+`
+	got, err := normalizeTitles(t, input)
+	if want := []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("títulos = %v, want %v", got, want)
+	}
+	var skipped *SkippedError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("err = %v, want *SkippedError", err)
+	}
+	var lines []int
+	for _, l := range skipped.Lines {
+		lines = append(lines, l.Line)
+	}
+	if want := []int{2, 3, 6}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("líneas omitidas = %v, want %v (%v)", lines, want, err)
+	}
+	if skipped.Total != 5 {
+		t.Errorf("Total = %d, want 5", skipped.Total)
+	}
+}
+
+func TestNormalizeToV3_ArrayMalFormado(t *testing.T) {
+	var out bytes.Buffer
+	_, err := NormalizeToV3(strings.NewReader(`[{"title":"A"},`), &out, Options{})
+	var skipped *SkippedError
+	if err == nil || errors.As(err, &skipped) {
+		t.Errorf("err = %v, want error de documento", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("salida inesperada: %s", out.String())
+	}
+}
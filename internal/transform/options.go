@@ -0,0 +1,93 @@
+// internal/transform/options.go – Opciones de conversión (salida reproducible)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Para que dos corridas sobre la misma entrada produzcan archivos idénticos
+// byte a byte, nada en la salida puede depender del momento de ejecución.
+// Históricamente v3 rellenaba con `time.Now()` las fechas ausentes o
+// ilegibles; Options permite elegir otra política:
+//
+//   FallbackNow   → hora actual (comportamiento histórico, no reproducible).
+//   FallbackOmit  → la clave "created"/"modified" no se escribe.
+//   FallbackFixed → se usa siempre Options.FixedDate.
+//
+// El orden de las claves ya es canónico: encoding/json escribe los mapas (v3)
+// con claves ordenadas y los structs (v1, v2, hybrid) en orden de declaración.
+//
+// Location elige la zona en que v2 y v3 expresan las fechas (UTC si es nil);
+// el instante es el mismo, sólo cambia el offset impreso.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"fmt"
+	"time"
+)
+
+// Políticas de fecha de respaldo para v3.
+const (
+	FallbackNow   = "now"
+	FallbackOmit  = "omit"
+	FallbackFixed = "fixed"
+)
+
+// Options ajusta la conversión.  El valor cero reproduce el comportamiento
+// histórico de los conversores.
+type Options struct {
+	Fallback  string         // FallbackNow ("" equivale), FallbackOmit o FallbackFixed
+	FixedDate time.Time      // fecha usada con FallbackFixed
+	Location  *time.Location // zona de salida de las fechas (nil = UTC)
+}
+
+// ParseFallback interpreta el valor de un flag -fallback-date: "now", "omit",
+// o una fecha fija en formato TiddlyWiki (yyyymmdd[hhMMSS]) o RFC3339.
+func ParseFallback(s string) (Options, error) {
+	switch s {
+	case "", FallbackNow:
+		return Options{Fallback: FallbackNow}, nil
+	case FallbackOmit:
+		return Options{Fallback: FallbackOmit}, nil
+	}
+	if t, ok := ParseTWDate(s); ok {
+		return Options{Fallback: FallbackFixed, FixedDate: t}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return Options{Fallback: FallbackFixed, FixedDate: t}, nil
+	}
+	return Options{}, fmt.Errorf("fecha de respaldo inválida: %q (usa now, omit, yyyymmddhhMMSS o RFC3339)", s)
+}
+
+// Reproducible indica si la salida no depende del momento de ejecución.
+func (o Options) Reproducible() bool {
+	return o.Fallback == FallbackOmit || o.Fallback == FallbackFixed
+}
+
+// isoDate formatea una fecha TiddlyWiki para v3.  Si raw no se puede parsear
+// aplica la política de respaldo; ok=false significa que la clave se omite.
+func (o Options) isoDate(raw string) (s string, ok bool) {
+	if t, parsed := ParseTWDate(raw); parsed {
+		return formatISO8601(t, o.Location), true
+	}
+	switch o.Fallback {
+	case FallbackOmit:
+		return "", false
+	case FallbackFixed:
+		return formatISO8601(o.FixedDate, o.Location), true
+	default:
+		return formatISO8601(time.Now().Truncate(time.Second), o.Location), true
+	}
+}
+
+// inZone parsea una fecha TiddlyWiki y la expresa en o.Location.  Si raw no
+// se puede parsear devuelve time.Time{} (v2 lo serializa como fecha cero).
+func (o Options) inZone(raw string) time.Time {
+	t, ok := ParseTWDate(raw)
+	if !ok {
+		return time.Time{}
+	}
+	if o.Location != nil {
+		t = t.In(o.Location)
+	}
+	return t
+}
@@ -0,0 +1,53 @@
+// internal/transform/options_test.go – Tests de las opciones de conversión
+package transform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestParseFallback(t *testing.T) {
+	fixed := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		in      string
+		want    Options
+		wantErr bool
+	}{
+		{"", Options{Fallback: FallbackNow}, false},
+		{"now", Options{Fallback: FallbackNow}, false},
+		{"omit", Options{Fallback: FallbackOmit}, false},
+		{"20240101000000", Options{Fallback: FallbackFixed, FixedDate: fixed}, false},
+		{"2024-01-01T00:00:00Z", Options{Fallback: FallbackFixed, FixedDate: fixed}, false},
+		{"mañana", Options{}, true},
+	}
+	for _, c := range cases {
+		got, err := ParseFallback(c.in)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParseFallback(%q) error = %v, wantErr %v", c.in, err, c.wantErr)
+			continue
+		}
+		if got.Fallback != c.want.Fallback || !got.FixedDate.Equal(c.want.FixedDate) {
+			t.Errorf("ParseFallback(%q) = %+v, want %+v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestConvertTiddlerV3With_Fallback(t *testing.T) {
+	td := models.Tiddler{Title: "Sin fechas", Created: "ayer"}
+
+	omit := ConvertTiddlerV3With(td, Options{Fallback: FallbackOmit})
+	if _, ok := omit["created"]; ok {
+		t.Errorf("FallbackOmit escribió created: %v", omit["created"])
+	}
+	if _, ok := omit["modified"]; ok {
+		t.Errorf("FallbackOmit escribió modified: %v", omit["modified"])
+	}
+
+	opts, _ := ParseFallback("20240101000000")
+	fixed := ConvertTiddlerV3With(td, opts)
+	if fixed["created"] != "2024-01-01T00:00:00+00:00" || fixed["modified"] != "2024-01-01T00:00:00+00:00" {
+		t.Errorf("FallbackFixed: created=%v modified=%v", fixed["created"], fixed["modified"])
+	}
+}
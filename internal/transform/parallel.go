@@ -0,0 +1,151 @@
+// internal/transform/parallel.go – Conversión en paralelo con orden determinista
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Convertir un tiddler es independiente de los demás, así que el trabajo se
+// reparte entre varias goroutines.  Lo delicado es el orden: la salida debe
+// ser idéntica a la secuencial, sin importar qué worker termine primero.
+//
+//   • ParallelMap   → para slices: cada worker escribe en results[i], de modo
+//                     que el orden sale gratis del índice.
+//   • ConvertStream → para el pipeline en streaming: cada tiddler recibe un
+//                     número de secuencia y el colector reordena antes de
+//                     emitir.  Un semáforo de `window` turnos limita cuántos
+//                     registros están en vuelo, así la memoria sigue acotada.
+//
+// Con workers <= 1 ambas funciones corren en la goroutine que llama, sin
+// sobrecosto.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"context"
+	"sync"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// ParallelMap aplica conv a cada tiddler usando workers goroutines.  El
+// resultado conserva el orden de ts.
+func ParallelMap[T any](ts []models.Tiddler, workers int, conv func(models.Tiddler) T) []T {
+	out := make([]T, len(ts))
+	if workers <= 1 || len(ts) < 2 {
+		for i, t := range ts {
+			out[i] = conv(t)
+		}
+		return out
+	}
+	if workers > len(ts) {
+		workers = len(ts)
+	}
+
+	// Bloques contiguos: cada worker toca una región distinta de out.
+	chunk := (len(ts) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(ts); start += chunk {
+		end := start + chunk
+		if end > len(ts) {
+			end = len(ts)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				out[i] = conv(ts[i])
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return out
+}
+
+// ConvertStream convierte con workers goroutines los tiddlers que entrega
+// source y llama a emit con cada registro en el orden original.  Si source o
+// emit fallan, el resto del trabajo se cancela y se devuelve ese error.
+func ConvertStream(ctx context.Context, workers int,
+	source func(yield func(models.Tiddler) error) error,
+	conv func(models.Tiddler) any,
+	emit func(rec any) error,
+) error {
+	if workers <= 1 {
+		return source(func(t models.Tiddler) error { return emit(conv(t)) })
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		seq int
+		t   models.Tiddler
+	}
+	type result struct {
+		seq int
+		rec any
+	}
+	window := workers * 4
+	slots := make(chan struct{}, window) // turnos: registros en vuelo
+	jobs := make(chan job)
+	results := make(chan result, window)
+
+	// Productor: numera los tiddlers en el orden en que llegan.
+	var srcErr error
+	go func() {
+		defer close(jobs)
+		seq := 0
+		srcErr = source(func(t models.Tiddler) error {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			select {
+			case jobs <- job{seq: seq, t: t}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			seq++
+			return nil
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- result{seq: j.seq, rec: conv(j.t)}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Colector: emite en orden; lo que llega adelantado espera en pending.
+	pending := make(map[int]any, window)
+	next := 0
+	var emitErr error
+	for r := range results {
+		if emitErr != nil {
+			continue // drenar hasta que los workers terminen
+		}
+		pending[r.seq] = r.rec
+		for rec, ok := pending[next]; ok; rec, ok = pending[next] {
+			delete(pending, next)
+			next++
+			<-slots
+			if err := emit(rec); err != nil {
+				emitErr = err
+				cancel()
+				break
+			}
+		}
+	}
+	if emitErr != nil {
+		return emitErr
+	}
+	return srcErr
+}
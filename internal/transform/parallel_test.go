@@ -0,0 +1,184 @@
+// internal/transform/parallel_test.go – Tests y benchmarks de la conversión en paralelo
+// --------------------------------------------------------------------------------
+// Los tests comprueban que ParallelMap y ConvertStream producen exactamente la
+// misma salida (y en el mismo orden) que la conversión secuencial.
+//
+// Los benchmarks usan un corpus sintético de 100k tiddlers con la mezcla que
+// encarece la conversión: texto plano, tiddlers application/json (v1 los
+// re-indenta) y envoltorios {"content":{…}} que GetTextContent decodifica.
+//
+//   go test ./internal/transform -run '^$' -bench . -benchmem
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// syntheticCorpus genera n tiddlers deterministas.
+func syntheticCorpus(n int) []models.Tiddler {
+	body := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 8)
+	ts := make([]models.Tiddler, n)
+	for i := range ts {
+		t := models.Tiddler{
+			Title:    fmt.Sprintf("Tiddler %06d", i),
+			Tags:     fmt.Sprintf("[[grupo %d]] [[corpus]]", i%50),
+			Created:  "20250101120000",
+			Modified: fmt.Sprintf("202502%02d120000", i%28+1),
+			Type:     "text/vnd.tiddlywiki",
+			Text:     body,
+		}
+		switch i % 3 {
+		case 1:
+			t.Type = "application/json"
+			t.Text = fmt.Sprintf(`{"id":%d,"items":[1,2,3],"nota":%q}`, i, body)
+		case 2:
+			t.Text = fmt.Sprintf(`{"content":{"plain":%q,"markdown":"# %d"},"meta":{"n":%d}}`, body, i, i)
+		}
+		ts[i] = t
+	}
+	return ts
+}
+
+func TestParallelMap_Orden(t *testing.T) {
+	ts := syntheticCorpus(1000)
+	want := ConvertTiddlersV2(ts)
+	for _, workers := range []int{0, 1, 3, 8, 2000} {
+		got := ParallelMap(ts, workers, ConvertTiddlerV2)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("workers=%d: la salida difiere de la secuencial", workers)
+		}
+	}
+}
+
+func TestConvertStream(t *testing.T) {
+	ts := syntheticCorpus(500)
+	source := func(yield func(models.Tiddler) error) error {
+		for _, td := range ts {
+			if err := yield(td); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	conv := func(td models.Tiddler) any { return ConvertTiddler(td) }
+
+	for _, workers := range []int{1, 4} {
+		var got []models.Record
+		err := ConvertStream(context.Background(), workers, source, conv, func(rec any) error {
+			got = append(got, rec.(models.Record))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("workers=%d: %v", workers, err)
+		}
+		if !reflect.DeepEqual(got, ConvertTiddlers(ts)) {
+			t.Errorf("workers=%d: orden o contenido distinto al secuencial", workers)
+		}
+	}
+
+	// Un error de emit detiene el pipeline y se propaga.
+	boom := errors.New("disco lleno")
+	n := 0
+	err := ConvertStream(context.Background(), 4, source, conv, func(any) error {
+		if n++; n == 10 {
+			return boom
+		}
+		return nil
+	})
+	if !errors.Is(err, boom) || n != 10 {
+		t.Errorf("err = %v tras %d registros, want %v tras 10", err, n, boom)
+	}
+
+	// Un error del origen también se propaga.
+	srcErr := errors.New("JSON roto")
+	err = ConvertStream(context.Background(), 4, func(yield func(models.Tiddler) error) error {
+		yield(ts[0])
+		return srcErr
+	}, conv, func(any) error { return nil })
+	if !errors.Is(err, srcErr) {
+		t.Errorf("err = %v, want %v", err, srcErr)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Benchmarks sobre 100k tiddlers
+// -----------------------------------------------------------------------------
+
+var (
+	benchOnce   sync.Once
+	benchCorpus []models.Tiddler
+)
+
+func corpus100k(b *testing.B) []models.Tiddler {
+	b.Helper()
+	benchOnce.Do(func() { benchCorpus = syntheticCorpus(100_000) })
+	return benchCorpus
+}
+
+// benchWorkers devuelve 1, 4 y la cantidad de CPUs, sin repetidos.
+func benchWorkers() []int {
+	ws := []int{1, 4}
+	if n := runtime.NumCPU(); n != 1 && n != 4 {
+		ws = append(ws, n)
+	}
+	return ws
+}
+
+func benchConvert[T any](b *testing.B, conv func(models.Tiddler) T) {
+	ts := corpus100k(b)
+	for _, workers := range benchWorkers() {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ParallelMap(ts, workers, conv)
+			}
+		})
+	}
+}
+
+func BenchmarkConvertV1_100k(b *testing.B)     { benchConvert(b, ConvertTiddler) }
+func BenchmarkConvertV2_100k(b *testing.B)     { benchConvert(b, ConvertTiddlerV2) }
+func BenchmarkConvertV3_100k(b *testing.B)     { benchConvert(b, ConvertTiddlerV3) }
+func BenchmarkConvertHybrid_100k(b *testing.B) { benchConvert(b, ConvertTiddlerHybrid) }
+
+func BenchmarkConvertStream_100k(b *testing.B) {
+	ts := corpus100k(b)
+	source := func(yield func(models.Tiddler) error) error {
+		for _, t := range ts {
+			if err := yield(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	conv := func(t models.Tiddler) any { return ConvertTiddlerV3(t) }
+	for _, workers := range benchWorkers() {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := ConvertStream(context.Background(), workers, source, conv, func(any) error { return nil }); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetTextContent(b *testing.B) {
+	wrapped := `{"content":{"plain":"hola mundo","markdown":"# hola"},"meta":{"title":"x","tags":["a","b"]}}`
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		GetTextContent(wrapped)
+	}
+}
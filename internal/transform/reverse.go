@@ -13,14 +13,24 @@
 // Algoritmo:
 //   1. Lee archivo JSONL línea por línea.
 //   2. Parsea cada línea como map[string]any.
-//   3. Convierte campos enriquecidos de vuelta al formato TiddlyWiki:
-//      - RFC3339 → formato TiddlyWiki (yyyymmddhhMMSS)
+//   3. Detecta el esquema del registro (v1/hybrid, v2 o v3; ver
+//      reverse_modes.go) o usa el indicado, y para v3:
+//      - RFC3339 → formato TiddlyWiki en UTC (yyyymmddhhMMSS[mmm]); si el
+//        registro trae created_raw/modified_raw con el mismo instante, se
+//        restaura el valor crudo tal cual (ver twDateFromRecord)
 //      - []string tags → "[[tag1]] [[tag2]]"
+//      - "fields" → campos personalizados del tiddler (ExtraFields)
 //      - Campos simples directos
 //   4. Serializa como array JSON con indentación.
+//   Los fragmentos (type "fragment") se omiten con un aviso en stderr.
 //
 // Firma:
 //   ReverseJSONLToTiddlyJSON(inputPath, outputPath string) error
+//   ReverseJSONLToTiddlyJSONAs(inputPath, outputPath, schema string) error
+//     ("-" en cualquiera de las dos rutas significa stdin / stdout)
+//
+// La dirección contraria, ReverseTiddlyJSONToJSONL, normaliza cualquier
+// entrada a JSONL v3 (ver normalize.go).
 // --------------------------------------------------------------------------------
 
 package transform
@@ -28,7 +38,9 @@ package transform
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -36,8 +48,9 @@ import (
 	"github.com/diegoabeltran16/OpenPages-Source/models"
 )
 
-// ReverseJSONLToTiddlyJSON lee un archivo JSONL (generado por ConvertTiddlersV3)
-// y lo convierte de vuelta al formato JSON de TiddlyWiki compatible.
+// ReverseJSONLToTiddlyJSON lee un archivo JSONL de cualquier modo (el esquema
+// se detecta por registro) y lo convierte de vuelta al formato JSON de
+// TiddlyWiki compatible.
 //
 // Transformaciones aplicadas:
 //   - RFC3339 dates → TiddlyWiki format (20060102150405)
@@ -49,16 +62,28 @@ import (
 //
 //	ReverseJSONLToTiddlyJSON("data/out/tiddlers.jsonl", "data/out/restored.json")
 func ReverseJSONLToTiddlyJSON(inputPath, outputPath string) error {
+	return ReverseJSONLToTiddlyJSONAs(inputPath, outputPath, SchemaAuto)
+}
+
+// ReverseJSONLToTiddlyJSONAs es ReverseJSONLToTiddlyJSON con un esquema fijo
+// ("v1", "v2", "v3", "hybrid" o SchemaAuto) para todas las líneas.
+func ReverseJSONLToTiddlyJSONAs(inputPath, outputPath, schema string) error {
 	// 1) Abrir archivo JSONL de entrada
-	file, err := os.Open(inputPath)
-	if err != nil {
-		return fmt.Errorf("no se pudo abrir archivo JSONL '%s': %w", inputPath, err)
+	var file io.Reader = os.Stdin
+	if inputPath != "-" {
+		f, err := os.Open(inputPath)
+		if err != nil {
+			return fmt.Errorf("no se pudo abrir archivo JSONL '%s': %w", inputPath, err)
+		}
+		defer f.Close()
+		file = f
 	}
-	defer file.Close()
 
 	var tiddlers []models.Tiddler
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
 	lineNumber := 0
+	fragments := 0
 
 	// 2) Procesar cada línea del JSONL
 	for scanner.Scan() {
@@ -77,7 +102,11 @@ func ReverseJSONLToTiddlyJSON(inputPath, outputPath string) error {
 		}
 
 		// 4) Convertir registro de vuelta a Tiddler
-		tiddler, err := recordToTiddler(record)
+		tiddler, err := RecordToTiddlerAs(record, schema)
+		if errors.Is(err, ErrFragment) {
+			fragments++
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("error convirtiendo línea %d: %w", lineNumber, err)
 		}
@@ -89,13 +118,20 @@ func ReverseJSONLToTiddlyJSON(inputPath, outputPath string) error {
 	if err := scanner.Err(); err != nil {
 		return fmt.Errorf("error leyendo archivo JSONL: %w", err)
 	}
+	if err := fragmentsSkipped(fragments, len(tiddlers)); err != nil {
+		return err
+	}
 
 	// 6) Crear archivo de salida
-	outputFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("no se pudo crear archivo de salida '%s': %w", outputPath, err)
+	var outputFile io.Writer = os.Stdout
+	if outputPath != "-" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("no se pudo crear archivo de salida '%s': %w", outputPath, err)
+		}
+		defer f.Close()
+		outputFile = f
 	}
-	defer outputFile.Close()
 
 	// 7) Serializar como JSON con indentación (formato TiddlyWiki)
 	encoder := json.NewEncoder(outputFile)
@@ -105,18 +141,27 @@ func ReverseJSONLToTiddlyJSON(inputPath, outputPath string) error {
 		return fmt.Errorf("error escribiendo JSON de salida: %w", err)
 	}
 
-	fmt.Printf("🔄 Reversión completada: %d tiddlers convertidos\n", len(tiddlers))
+	fmt.Fprintf(os.Stderr, "🔄 Reversión completada: %d tiddlers convertidos\n", len(tiddlers))
 	return nil
 }
 
-// recordToTiddler convierte un map[string]any (de JSONL v3) de vuelta a models.Tiddler
-func recordToTiddler(record map[string]any) (models.Tiddler, error) {
+// RecordToTiddler convierte un registro de cualquier modo de vuelta a
+// models.Tiddler, detectando su esquema (ver RecordToTiddlerAs).
+func RecordToTiddler(record map[string]any) (models.Tiddler, error) {
+	return RecordToTiddlerAs(record, SchemaAuto)
+}
+
+// recordV3ToTiddler es la inversa de ConvertTiddlerV3.
+func recordV3ToTiddler(record map[string]any) models.Tiddler {
 	tiddler := models.Tiddler{}
 
 	// Campos string simples
 	if id, ok := record["id"].(string); ok {
 		tiddler.Title = id
 	}
+	if title, ok := record["title"].(string); ok && title != "" {
+		tiddler.Title = title
+	}
 	if text, ok := record["text"].(string); ok {
 		tiddler.Text = text
 	}
@@ -126,42 +171,39 @@ func recordToTiddler(record map[string]any) (models.Tiddler, error) {
 	if tmapID, ok := record["tmap.id"].(string); ok {
 		tiddler.TmapID = tmapID
 	}
-
-	// Fechas: convertir de RFC3339 a formato TiddlyWiki
-	if created, ok := record["created"].(string); ok {
-		if t, err := parseRFC3339ToTW(created); err == nil {
-			tiddler.Created = t
-		} else {
-			// Fallback: usar fecha actual si el parseo falla
-			tiddler.Created = time.Now().Format("20060102150405")
-		}
+	if source, ok := record["source_wiki"].(string); ok {
+		tiddler.Source = source
 	}
 
-	if modified, ok := record["modified"].(string); ok {
-		if t, err := parseRFC3339ToTW(modified); err == nil {
-			tiddler.Modified = t
-		} else {
-			// Fallback: usar fecha actual si el parseo falla
-			tiddler.Modified = time.Now().Format("20060102150405")
-		}
-	}
+	// Fechas: convertir de RFC3339 a formato TiddlyWiki
+	tiddler.Created = twDateFromRecord(record, "created")
+	tiddler.Modified = twDateFromRecord(record, "modified")
 
 	// Tags: convertir de []interface{} a formato TiddlyWiki "[[tag1]] [[tag2]]"
-	if tags, ok := record["tags"].([]interface{}); ok {
-		var tagStrings []string
-		for _, tag := range tags {
-			if tagStr, ok := tag.(string); ok {
-				// Envolver cada tag en [[ ]]
-				tagStrings = append(tagStrings, fmt.Sprintf("[[%s]]", tagStr))
-			}
-		}
-		tiddler.Tags = strings.Join(tagStrings, " ")
-	}
+	tiddler.Tags = tagsToTW(record["tags"])
 
 	// Campos opcionales que podrían estar presentes
 	if color, ok := record["color"].(string); ok {
 		tiddler.Color = color
 	}
+	if path, ok := record["path"].(string); ok {
+		tiddler.Path = path
+	}
+	if fields, ok := record["fields"].(map[string]any); ok {
+		tiddler.ExtraFields = fields
+	}
+	// v3 escribe relations {} y tags_list [] aunque estén vacíos: sólo se
+	// restauran si traen algo.
+	if rels, ok := record["relations"].(map[string]any); ok && len(rels) > 0 {
+		tiddler.Relations = rels
+	}
+	if list, ok := record["tags_list"].([]any); ok && len(list) > 0 {
+		for _, tag := range list {
+			if s, ok := tag.(string); ok {
+				tiddler.TagsList = append(tiddler.TagsList, s)
+			}
+		}
+	}
 
 	// Si text es JSON, deserializar y reinyectar campos
 	if text, ok := record["text"].(string); ok {
@@ -180,27 +222,37 @@ func recordToTiddler(record map[string]any) (models.Tiddler, error) {
 		}
 	}
 
-	return tiddler, nil
+	return tiddler
 }
 
-// parseRFC3339ToTW convierte una fecha RFC3339 de vuelta al formato TiddlyWiki
-func parseRFC3339ToTW(rfc3339Str string) (string, error) {
-	// Intentar varios formatos RFC3339
-	layouts := []string{
-		"2006-01-02T15:04:05-07:00",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05.000Z",
-		"2006-01-02T15:04:05.000-07:00",
-	}
-
-	for _, layout := range layouts {
-		if t, err := time.Parse(layout, rfc3339Str); err == nil {
-			// Convertir a formato TiddlyWiki: yyyymmddhhMMSS
-			return t.Format("20060102150405"), nil
+// twDateFromRecord reconstruye la fecha TiddlyWiki de record[key].  v3 guarda
+// siempre el valor original en key+"_raw", que tiene prioridad:
+//   - si representa el mismo instante que la fecha ISO (o ésta falta), así
+//     "20250101" o "20250101120000000" vuelven idénticos;
+//   - si no es una fecha TiddlyWiki ("" o "ayer"): la ISO fue un respaldo
+//     (Options.Fallback) y no un dato del tiddler.
+//
+// Sólo si la ISO se editó a mano (otro instante) o no hay *_raw se usa la ISO.
+// Una ISO ilegible sin *_raw se conserva tal cual.
+func twDateFromRecord(record map[string]any, key string) string {
+	iso, hasISO := record[key].(string)
+	raw, hasRaw := record[key+"_raw"].(string)
+	if hasRaw {
+		rawT, ok := ParseTWDate(raw)
+		if !ok || !hasISO {
+			return raw
+		}
+		if isoT, err := time.Parse(time.RFC3339Nano, iso); err != nil || rawT.Equal(isoT) {
+			return raw
 		}
 	}
-
-	return "", fmt.Errorf("formato de fecha no reconocido: %s", rfc3339Str)
+	if !hasISO {
+		return ""
+	}
+	if tw, err := parseRFC3339ToTW(iso); err == nil {
+		return tw
+	}
+	return iso
 }
 
 func RestoreTiddlerWrapper(original models.Tiddler, newPlain string, newMarkdown string) models.Tiddler {
@@ -233,38 +285,31 @@ func looksLikeJSON(s string) bool {
 	return strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}")
 }
 
+// ReverseTiddlyJSONToJSONL normaliza inputPath (array o mapa TiddlyWiki, o
+// JSONL de cualquier modo) a JSONL v3 en outputPath ("-" = stdin / stdout).
+// Ver NormalizeToV3: si hubo registros ilegibles, el resto se escribe igual y
+// el error es *SkippedError.
 func ReverseTiddlyJSONToJSONL(inputPath, outputPath string) error {
-	file, err := os.Open(inputPath)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	// Detectar si es array JSON
-	buf := make([]byte, 1)
-	if _, err := file.Read(buf); err != nil {
-		return err
-	}
-	file.Seek(0, 0) // Reset
-
-	var tiddlers []map[string]any
-	if buf[0] == '[' {
-		// Es un array JSON
-		if err := json.NewDecoder(file).Decode(&tiddlers); err != nil {
-			return err
+	var in io.Reader = os.Stdin
+	if inputPath != "-" {
+		f, err := os.Open(inputPath)
+		if err != nil {
+			return fmt.Errorf("no se pudo abrir '%s': %w", inputPath, err)
 		}
-	} else {
-		// Es JSONL
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			var t map[string]any
-			if err := json.Unmarshal(scanner.Bytes(), &t); err == nil {
-				tiddlers = append(tiddlers, t)
-			}
+		defer f.Close()
+		in = f
+	}
+	var out io.Writer = os.Stdout
+	if outputPath != "-" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("no se pudo crear archivo de salida '%s': %w", outputPath, err)
 		}
+		defer f.Close()
+		out = f
 	}
 
-	// Procesar cada tiddler...
-	// ...tu lógica aquí...
-	return nil
+	n, err := NormalizeToV3(in, out, Options{})
+	fmt.Fprintf(os.Stderr, "🔄 Normalización completada: %d registros v3\n", n)
+	return err
 }
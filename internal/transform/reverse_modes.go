@@ -0,0 +1,240 @@
+// internal/transform/reverse_modes.go – Reversión por modo (v1, v2, v3, hybrid)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Cada modo de export escribe el tiddler con claves distintas:
+//
+//   v1 / hybrid → models.Record:   id, textPlain/textMarkdown, createdAt/modifiedAt
+//   v2          → models.RecordV2: id, meta{title,tags,created,…,extra}, content{plain|markdown|json}
+//   v3          → mapa plano:      id, title, text, created(_raw), modified(_raw), …
+//
+// RecordToTiddlerAs lleva cualquiera de ellos de vuelta a models.Tiddler.
+// Con SchemaAuto el esquema es el de schema_version o, si falta, se detecta
+// por sus claves (DetectSchema); v1 e hybrid comparten esquema y se
+// revierten igual.
+//
+// Lo que un modo no escribe no puede volver: v2 no guarda el tipo MIME de los
+// textos planos ni path/relations/tags_list, y sus fechas son time.Time (una
+// fecha de 8 dígitos vuelve con 14).  `openpages roundtrip` lo mide.
+//
+// Los fragmentos (type "fragment", export con -chunk-tokens) no son
+// tiddlers: cada uno lleva un trozo del texto sin los encabezados y, con
+// solapamiento, repite parte del anterior, así que no se puede rehacer el
+// original.  RecordToTiddlerAs devuelve ErrFragment y los lectores de esta
+// carpeta los omiten avisando en stderr.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// SchemaAuto pide detectar el esquema de cada registro (ver DetectSchema).
+const SchemaAuto = "auto"
+
+// FragmentType es el type de los registros de fragmento (ver internal/chunk).
+const FragmentType = "fragment"
+
+// ErrFragment indica que un registro es un fragmento y no un tiddler.
+var ErrFragment = errors.New("registro de fragmento (type \"fragment\"): no es un tiddler; exporta sin -chunk-tokens para revertir")
+
+// IsFragment informa si record es un fragmento de internal/chunk.
+func IsFragment(record map[string]any) bool {
+	typ, _ := record["type"].(string)
+	_, hasParent := record["parent"].(string)
+	return typ == FragmentType && hasParent
+}
+
+// fragmentsSkipped avisa en stderr de los fragmentos omitidos.  Si la entrada
+// no tenía otra cosa devuelve un error: no hay tiddlers que leer.
+func fragmentsSkipped(fragments, tiddlers int) error {
+	if fragments == 0 {
+		return nil
+	}
+	if tiddlers == 0 {
+		return fmt.Errorf("la entrada sólo tiene fragmentos (%d, export con -chunk-tokens): no hay tiddlers que revertir", fragments)
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  %d fragmentos omitidos (type \"fragment\"): no son tiddlers y no se pueden revertir\n", fragments)
+	return nil
+}
+
+// DetectSchema deduce el modo que produjo record: su schema_version si es
+// conocido (ver schema.go) y, en registros anteriores a schema_version, "v2"
+// si trae meta y content como objetos, "v1" si trae claves del esquema
+// compacto (textPlain, textMarkdown, createdAt, modifiedAt) y "v3" en otro
+// caso.  Sin schema_version, hybrid se detecta como "v1" (mismo esquema).
+func DetectSchema(record map[string]any) string {
+	if version, err := DeclaredSchema(record); err == nil && version != "" {
+		return version
+	}
+	_, hasMeta := record["meta"].(map[string]any)
+	_, hasContent := record["content"].(map[string]any)
+	if _, hasText := record["text"]; hasMeta && hasContent && !hasText {
+		return "v2"
+	}
+	for _, k := range []string{"textPlain", "textMarkdown", "createdAt", "modifiedAt"} {
+		if _, ok := record[k]; ok {
+			return "v1"
+		}
+	}
+	return "v3"
+}
+
+// RecordToTiddlerAs revierte record según schema: "v1", "v2", "v3",
+// "hybrid" o SchemaAuto ("" equivale a SchemaAuto).  Un fragmento devuelve
+// ErrFragment.
+func RecordToTiddlerAs(record map[string]any, schema string) (models.Tiddler, error) {
+	if IsFragment(record) {
+		return models.Tiddler{}, ErrFragment
+	}
+	if schema == "" || schema == SchemaAuto {
+		schema = DetectSchema(record)
+	}
+	switch schema {
+	case "v1", "hybrid":
+		return recordV1ToTiddler(record), nil
+	case "v2":
+		return recordV2ToTiddler(record), nil
+	case "v3":
+		return recordV3ToTiddler(record), nil
+	default:
+		return models.Tiddler{}, fmt.Errorf("esquema desconocido: %q (usa auto, v1, v2, v3 o hybrid)", schema)
+	}
+}
+
+// recordV1ToTiddler revierte models.Record (v1 e hybrid).  Las fechas ya
+// vienen en formato TiddlyWiki; una fecha RFC3339 (editada a mano) se
+// convierte.
+func recordV1ToTiddler(record map[string]any) models.Tiddler {
+	t := models.Tiddler{
+		Title:    str(record, "id"),
+		Type:     str(record, "type"),
+		Tags:     tagsToTW(record["tags"]),
+		Created:  twDate(str(record, "createdAt")),
+		Modified: twDate(str(record, "modifiedAt")),
+		Color:    str(record, "color"),
+		Source:   str(record, "source_wiki"),
+	}
+	if title := str(record, "title"); title != "" {
+		t.Title = title
+	}
+	if text, ok := record["textPlain"].(string); ok {
+		t.Text = text
+	} else {
+		t.Text = str(record, "textMarkdown")
+	}
+	if fields, ok := record["fields"].(map[string]any); ok && len(fields) > 0 {
+		t.ExtraFields = fields
+	}
+	return t
+}
+
+// recordV2ToTiddler revierte models.RecordV2.  meta.extra lleva tmap.id,
+// source_wiki y los campos personalizados; content.json vuelve como texto
+// application/json y content.markdown como text/x-markdown.
+func recordV2ToTiddler(record map[string]any) models.Tiddler {
+	meta, _ := record["meta"].(map[string]any)
+	content, _ := record["content"].(map[string]any)
+
+	t := models.Tiddler{
+		Title:    str(record, "id"),
+		Tags:     tagsToTW(meta["tags"]),
+		Created:  twDateFromTime(str(meta, "created")),
+		Modified: twDateFromTime(str(meta, "modified")),
+		Color:    str(meta, "color"),
+	}
+	if title := str(meta, "title"); title != "" {
+		t.Title = title
+	}
+
+	if extra, ok := meta["extra"].(map[string]any); ok {
+		fields := make(map[string]any, len(extra))
+		for k, v := range extra {
+			switch k {
+			case "tmap.id":
+				t.TmapID, _ = v.(string)
+			case "source_wiki":
+				t.Source, _ = v.(string)
+			case "color":
+				if t.Color == "" {
+					t.Color, _ = v.(string)
+				}
+			case "path":
+				t.Path, _ = v.(string)
+			default:
+				fields[k] = v
+			}
+		}
+		if len(fields) > 0 {
+			t.ExtraFields = fields
+		}
+	}
+
+	if obj, ok := content["json"].(map[string]any); ok {
+		if b, err := json.Marshal(obj); err == nil {
+			t.Text = string(b)
+			t.Type = "application/json"
+		}
+	} else if md := str(content, "markdown"); md != "" && str(content, "plain") == "" {
+		t.Text = md
+		t.Type = "text/x-markdown"
+	} else {
+		t.Text = str(content, "plain")
+	}
+
+	if rels, ok := record["relations"].(map[string]any); ok && len(rels) > 0 {
+		t.Relations = rels
+	}
+	return t
+}
+
+// str devuelve m[key] si es string; "" si falta o es de otro tipo.
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// tagsToTW convierte una lista JSON de etiquetas al formato TiddlyWiki
+// "[[tag1]] [[tag2]]"; nil si no es una lista.
+func tagsToTW(raw any) any {
+	tags, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if s, ok := tag.(string); ok {
+			parts = append(parts, "[["+s+"]]")
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// twDate deja una fecha TiddlyWiki (o ilegible) tal cual y convierte una
+// RFC3339 al formato TiddlyWiki.
+func twDate(s string) string {
+	if _, ok := ParseTWDate(s); ok {
+		return s
+	}
+	if tw, err := parseRFC3339ToTW(s); err == nil {
+		return tw
+	}
+	return s
+}
+
+// twDateFromTime convierte un time.Time serializado por v2; el valor cero
+// ("0001-01-01T00:00:00Z", fecha ausente o ilegible) vuelve como "".
+func twDateFromTime(s string) string {
+	if ts, err := time.Parse(time.RFC3339Nano, s); err != nil || ts.IsZero() {
+		return ""
+	}
+	return twDate(s)
+}
@@ -0,0 +1,155 @@
+// internal/transform/reverse_modes_test.go – Tests de la reversión por modo
+package transform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// throughJSON serializa v como lo hace `export` y lo lee como registro.
+func throughJSON(t *testing.T, v any) map[string]any {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestDetectSchema(t *testing.T) {
+	tid := models.Tiddler{Title: "A", Text: "hola", Created: "20250605151000"}
+	cases := map[string]any{
+		"v1":     ConvertTiddler(tid),
+		"v2":     ConvertTiddlerV2(tid),
+		"v3":     ConvertTiddlerV3(tid),
+		"hybrid": ConvertTiddlerHybrid(tid),
+	}
+	for want, rec := range cases {
+		m := throughJSON(t, rec)
+		if got := DetectSchema(m); got != want {
+			t.Errorf("DetectSchema(%s) = %s", want, got)
+		}
+		// Registros anteriores a schema_version: se detectan por sus claves
+		// (hybrid comparte el esquema de v1).
+		delete(m, SchemaVersionKey)
+		if want == "hybrid" {
+			want = "v1"
+		}
+		if got := DetectSchema(m); got != want {
+			t.Errorf("DetectSchema(%s sin schema_version) = %s", want, got)
+		}
+	}
+	m := throughJSON(t, ConvertTiddler(tid))
+	m[SchemaVersionKey] = "v9"
+	if got := DetectSchema(m); got != "v1" {
+		t.Errorf("DetectSchema(schema_version desconocido) = %s, want v1 por claves", got)
+	}
+	if _, err := DeclaredSchema(m); err == nil {
+		t.Error("DeclaredSchema(v9) sin error")
+	}
+}
+
+// V3Keys debe cubrir todas las claves que escribe ConvertTiddlerV3With.
+func TestV3Keys(t *testing.T) {
+	tid := models.Tiddler{Title: "A", Text: "x", Created: "20250101", Modified: "20250102",
+		Color: "red", Path: "p", TmapID: "u", Source: "w", TagsList: []string{"t"},
+		Relations: map[string]any{"r": []string{"B"}}, ExtraFields: map[string]any{"c": 1}}
+	known := map[string]bool{}
+	for _, k := range V3Keys {
+		known[k] = true
+	}
+	for k := range ConvertTiddlerV3(tid) {
+		if !known[k] {
+			t.Errorf("clave v3 %q falta en V3Keys", k)
+		}
+	}
+}
+
+func TestRecordToTiddler_V1(t *testing.T) {
+	orig := models.Tiddler{Title: "Nota", Text: "cuerpo", Type: "text/vnd.tiddlywiki",
+		Tags: "[[con espacios]] simple", Created: "20250605151000123", Modified: "ayer",
+		Color: "#fff", Source: "equipoA", ExtraFields: map[string]any{"caption": "Nota"}}
+	for _, rec := range []any{ConvertTiddler(orig), ConvertTiddlerHybrid(orig)} {
+		got, err := RecordToTiddler(throughJSON(t, rec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Title != orig.Title || got.Text != orig.Text || got.Type != orig.Type ||
+			got.Created != orig.Created || got.Modified != orig.Modified ||
+			got.Color != orig.Color || got.Source != orig.Source {
+			t.Errorf("v1 revertido = %+v", got)
+		}
+		if got.Tags != "[[con espacios]] [[simple]]" {
+			t.Errorf("tags = %v", got.Tags)
+		}
+		if !reflect.DeepEqual(got.ExtraFields, orig.ExtraFields) {
+			t.Errorf("fields = %v", got.ExtraFields)
+		}
+	}
+}
+
+func TestRecordToTiddler_V2(t *testing.T) {
+	orig := models.Tiddler{Title: "Datos", Type: "application/json", Text: `{"a":1}`,
+		Tags: "x", Created: "20250605151000123", TmapID: "uuid", Source: "equipoA",
+		ExtraFields: map[string]any{"priority": float64(2)}}
+	got, err := RecordToTiddler(throughJSON(t, ConvertTiddlerV2(orig)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.Tiddler{Title: "Datos", Type: "application/json", Text: `{"a":1}`,
+		Tags: "[[x]]", Created: "20250605151000123", TmapID: "uuid", Source: "equipoA",
+		ExtraFields: map[string]any{"priority": float64(2)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("v2 revertido = %+v\nwant %+v", got, want)
+	}
+
+	// Markdown conserva su tipo; una fecha ilegible (tiempo cero) vuelve vacía.
+	md := models.Tiddler{Title: "M", Type: "text/x-markdown", Text: "# hola", Modified: "ayer"}
+	got, _ = RecordToTiddler(throughJSON(t, ConvertTiddlerV2(md)))
+	if got.Type != md.Type || got.Text != md.Text || got.Modified != "" {
+		t.Errorf("markdown revertido = %+v", got)
+	}
+}
+
+func TestRecordToTiddlerAs_EsquemaFijo(t *testing.T) {
+	rec := throughJSON(t, ConvertTiddler(models.Tiddler{Title: "A", Text: "hola"}))
+	if got, _ := RecordToTiddlerAs(rec, "v3"); got.Text != "" {
+		t.Errorf("v1 leído como v3 no debería tener texto: %q", got.Text)
+	}
+	if _, err := RecordToTiddlerAs(rec, "v9"); err == nil {
+		t.Error("esquema desconocido sin error")
+	}
+}
+
+// Una línea de más de 64 KB no corta la reversión.
+func TestReverseJSONLToTiddlyJSON_LineaLarga(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "largo.jsonl")
+	text := strings.Repeat("x", 200*1024)
+	b, err := json.Marshal(ConvertTiddlerV3(models.Tiddler{Title: "Largo", Text: text}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(in, append(b, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out.json")
+	if err := ReverseJSONLToTiddlyJSON(in, out); err != nil {
+		t.Fatal(err)
+	}
+	var got []models.Tiddler
+	data, _ := os.ReadFile(out)
+	if err := json.Unmarshal(data, &got); err != nil || len(got) != 1 || got[0].Text != text {
+		t.Fatalf("salida inesperada (%v): %d bytes", err, len(data))
+	}
+}
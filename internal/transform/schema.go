@@ -0,0 +1,87 @@
+// internal/transform/schema.go – Registro de esquemas de registro (schema_version)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Cada modo de export escribe un esquema distinto y, hasta ahora, quien leía
+// un JSONL tenía que adivinarlo por sus claves (DetectSchema).  Desde esta
+// versión cada registro lo declara en "schema_version" y Schemas describe
+// todos los conocidos:
+//
+//   v1     → models.Record           compacto heredado (textPlain, createdAt…)
+//   v2     → models.RecordV2         meta ↔ content, fragmentos para RAG
+//   v3     → map[string]any          plano para JSONL estricto
+//   hybrid → models.Record           como v1, con el texto desenvuelto
+//
+// DetectSchema confía en "schema_version" si es conocido y sólo si falta
+// (JSONL anteriores) mira las claves.  internal/migrate usa el registro para
+// llevar un JSONL de una versión a otra.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// SchemaVersionKey es la clave con la versión de esquema de cada registro.
+const SchemaVersionKey = "schema_version"
+
+// Schema describe una versión de esquema de registro.
+type Schema struct {
+	Version     string // valor de schema_version (= modo de export)
+	GoType      string // tipo que la produce
+	Description string // una línea, para `migrate -list`
+}
+
+// Schemas son los esquemas conocidos, en el orden de Modes.
+var Schemas = []Schema{
+	{models.SchemaV1, "models.Record", "compacto heredado: textPlain/textMarkdown, fechas TiddlyWiki"},
+	{models.SchemaV2, "models.RecordV2", "meta ↔ content para IA; admite fragmentos (type fragment)"},
+	{models.SchemaV3, "map[string]any", "plano para JSONL estricto: text, fechas RFC3339 y *_raw"},
+	{models.SchemaHybrid, "models.Record", "como v1, con el texto de los envoltorios {\"content\":…} desenvuelto"},
+}
+
+// V3Keys son todas las claves que puede escribir ConvertTiddlerV3With, en
+// orden alfabético: las columnas fijas de v3 en formatos tabulares (CSV),
+// donde el primer registro no basta para saber qué claves traerán los demás.
+var V3Keys = []string{
+	"color", "created", "created_raw", "fields", "id", "is_system", "kind",
+	"modified", "modified_raw", "path", "relations", SchemaVersionKey,
+	"source_wiki", "tags", "tags_list", "text", "title", "tmap.id", "type",
+}
+
+// LookupSchema devuelve el esquema de version.
+func LookupSchema(version string) (Schema, bool) {
+	for _, s := range Schemas {
+		if s.Version == version {
+			return s, true
+		}
+	}
+	return Schema{}, false
+}
+
+// SchemaVersions devuelve las versiones conocidas, en el orden de Schemas.
+func SchemaVersions() []string {
+	out := make([]string, len(Schemas))
+	for i, s := range Schemas {
+		out[i] = s.Version
+	}
+	return out
+}
+
+// DeclaredSchema devuelve el schema_version de record ("" si no lo trae) y
+// un error si trae uno desconocido.
+func DeclaredSchema(record map[string]any) (string, error) {
+	raw, ok := record[SchemaVersionKey]
+	if !ok {
+		return "", nil
+	}
+	version, _ := raw.(string)
+	if _, known := LookupSchema(version); !known {
+		return "", fmt.Errorf("schema_version desconocido: %v (usa %s)", raw, strings.Join(SchemaVersions(), ", "))
+	}
+	return version, nil
+}
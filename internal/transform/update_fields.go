@@ -0,0 +1,380 @@
+// internal/transform/update_fields.go – Aplicar campos editados sobre una plantilla
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// En el flujo de revisión se exporta la wiki, se corrige el JSONL y se
+// vuelve a aplicar sobre la wiki (la "plantilla").  No sólo cambia el texto:
+// también etiquetas, colores, captions o relaciones.  UpdateFields aplica un
+// conjunto elegido de campos y deja todo lo demás de la plantilla intacto.
+//
+//   spec := UpdateSpec{Fields: []string{"text", "tags", "fields.caption"},
+//                      Bump: map[string]bool{"text": true}}
+//   wiki, rep, err := UpdateFields(wiki, editados, spec)
+//
+// Campos admitidos (los mismos nombres que `openpages roundtrip`):
+//
+//   text        → el cuerpo; si la plantilla envuelve el texto en
+//                 {"content":{"plain":…}}, se actualiza content.plain
+//   markdown    → content.markdown del envoltorio, o el cuerpo de un tiddler
+//                 text/x-markdown
+//   type, color, path, tmap.id, source_wiki, tags, tags_list, relations
+//   fields.<nombre> / fields.*  → campos personalizados (uno o todos)
+//
+// Spec.Bump dice qué campos actualizan `modified` al cambiar (por defecto,
+// el contenido: text y markdown).  Corregir un color o una etiqueta no tiene
+// por qué aparecer como una edición del tiddler.
+//
+// Un modo de export con pérdidas no trae todos los campos (v1 no guarda
+// relations): con Spec.Schema, pedir un campo que ese esquema no guarda es un
+// error, para no borrar en la wiki lo que simplemente no viajó.
+// --------------------------------------------------------------------------------
+
+package transform
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// UpdatableFields son los campos estándar que UpdateFields sabe aplicar.
+var UpdatableFields = []string{
+	"text", "markdown", "type", "color", "path", "tmap.id", "source_wiki",
+	"tags", "tags_list", "relations",
+}
+
+// DefaultBump son los campos que actualizan `modified` si no se indica otra cosa.
+var DefaultBump = map[string]bool{"text": true, "markdown": true}
+
+// schemaLacks son los campos que cada esquema de export no guarda.
+var schemaLacks = map[string][]string{
+	"v1":     {"markdown", "path", "tmap.id", "tags_list", "relations"},
+	"hybrid": {"markdown", "path", "tmap.id", "tags_list", "relations"},
+	"v2":     {"type", "path", "tags_list", "relations"},
+	"v3":     {"markdown"},
+}
+
+// UpdateSpec describe qué aplicar.
+type UpdateSpec struct {
+	Fields []string        // campos a aplicar (ver UpdatableFields y fields.<nombre>)
+	Bump   map[string]bool // campos cuyo cambio actualiza modified; nil = DefaultBump
+	Schema string          // esquema de las actualizaciones (v1, v2, v3, hybrid); "" = sin restricción
+	Now    string          // valor de modified al actualizar; "" = hora actual (UTC)
+}
+
+// FieldChange es un cambio aplicado a la plantilla.
+type FieldChange struct {
+	Title string `json:"title"`
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+	Bump  bool   `json:"bump"` // el cambio actualizó modified
+}
+
+// UpdateReport resume la actualización.
+type UpdateReport struct {
+	Fields  []string      `json:"fields"`
+	Updated int           `json:"updated"` // tiddlers con al menos un cambio
+	Bumped  int           `json:"bumped"`  // tiddlers con modified actualizado
+	Missing []string      `json:"missing"` // títulos de las actualizaciones que no están en la plantilla
+	Changes []FieldChange `json:"changes"`
+}
+
+// ParseFieldList valida una lista de campos separados por comas.
+func ParseFieldList(s string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !validUpdateField(f) {
+			return nil, fmt.Errorf("campo no actualizable: %q (usa %s, fields.<nombre> o fields.*)",
+				f, strings.Join(UpdatableFields, ", "))
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("la lista de campos está vacía")
+	}
+	return fields, nil
+}
+
+// ParseBump interpreta la regla de `modified`: "all", "none" o una lista de
+// campos.
+func ParseBump(s string, fields []string) (map[string]bool, error) {
+	switch strings.TrimSpace(s) {
+	case "all":
+		bump := make(map[string]bool, len(fields))
+		for _, f := range fields {
+			bump[f] = true
+		}
+		bump["fields.*"] = true
+		return bump, nil
+	case "none":
+		return map[string]bool{}, nil
+	}
+	list, err := ParseFieldList(s)
+	if err != nil {
+		return nil, err
+	}
+	bump := make(map[string]bool, len(list))
+	for _, f := range list {
+		bump[f] = true
+	}
+	return bump, nil
+}
+
+func validUpdateField(f string) bool {
+	if strings.HasPrefix(f, "fields.") {
+		return len(f) > len("fields.")
+	}
+	for _, u := range UpdatableFields {
+		if f == u {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateFields aplica spec.Fields de updates (por título) sobre template, que
+// se modifica y se devuelve.  Los tiddlers sin cambios quedan idénticos.
+func UpdateFields(template, updates []models.Tiddler, spec UpdateSpec) ([]models.Tiddler, UpdateReport, error) {
+	rep := UpdateReport{Fields: spec.Fields, Missing: []string{}, Changes: []FieldChange{}}
+	for _, f := range spec.Fields {
+		if !validUpdateField(f) {
+			return template, rep, fmt.Errorf("campo no actualizable: %q", f)
+		}
+		for _, lack := range schemaLacks[spec.Schema] {
+			if f == lack {
+				return template, rep, fmt.Errorf("el esquema %s no guarda %q: no se puede aplicar", spec.Schema, f)
+			}
+		}
+	}
+	bump := spec.Bump
+	if bump == nil {
+		bump = DefaultBump
+	}
+	now := spec.Now
+	if now == "" {
+		now = FormatTWDate(time.Now().Truncate(time.Second)) // formato TiddlyWiki (UTC)
+	}
+
+	byTitle := make(map[string]models.Tiddler, len(updates))
+	var order []string
+	for _, u := range updates {
+		if _, dup := byTitle[u.Title]; !dup {
+			order = append(order, u.Title)
+		}
+		byTitle[u.Title] = u
+	}
+	found := make(map[string]bool, len(updates))
+
+	for i := range template {
+		u, ok := byTitle[template[i].Title]
+		if !ok {
+			continue
+		}
+		found[u.Title] = true
+		changed, bumped := false, false
+		for _, f := range expandFields(spec.Fields, template[i], u) {
+			old, upd := getField(template[i], f), getField(u, f)
+			if sameValue(old, upd) {
+				continue
+			}
+			if f == "markdown" && !setMarkdown(&template[i], upd.(string)) {
+				continue // la plantilla no tiene dónde guardar markdown
+			}
+			if f != "markdown" {
+				setField(&template[i], f, upd)
+			}
+			b := bump[f] || (strings.HasPrefix(f, "fields.") && bump["fields.*"])
+			rep.Changes = append(rep.Changes, FieldChange{Title: u.Title, Field: f, Old: old, New: upd, Bump: b})
+			changed = true
+			bumped = bumped || b
+		}
+		if changed {
+			rep.Updated++
+		}
+		if bumped {
+			template[i].Modified = now
+			rep.Bumped++
+		}
+	}
+	for _, title := range order {
+		if !found[title] {
+			rep.Missing = append(rep.Missing, title)
+		}
+	}
+	return template, rep, nil
+}
+
+// expandFields reemplaza "fields.*" por los campos personalizados de a y b.
+func expandFields(fields []string, a, b models.Tiddler) []string {
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != "fields.*" {
+			out = append(out, f)
+			continue
+		}
+		var custom []string
+		seen := map[string]bool{}
+		for _, m := range []map[string]any{a.ExtraFields, b.ExtraFields} {
+			for k := range m {
+				if !seen[k] {
+					seen[k] = true
+					custom = append(custom, "fields."+k)
+				}
+			}
+		}
+		sort.Strings(custom)
+		out = append(out, custom...)
+	}
+	return out
+}
+
+// getField devuelve el valor de f en t con una forma comparable: strings,
+// []string para las listas y el valor JSON de los campos personalizados.
+func getField(t models.Tiddler, f string) any {
+	switch f {
+	case "text":
+		return GetTextContent(t.Text)
+	case "markdown":
+		if t.TextMarkdown != "" {
+			return t.TextMarkdown
+		}
+		if w := wrapperContent(t.Text); w != nil {
+			s, _ := w["markdown"].(string)
+			return s
+		}
+		if t.Type == "text/x-markdown" {
+			return t.Text
+		}
+		return ""
+	case "type":
+		return t.Type
+	case "color":
+		return t.Color
+	case "path":
+		return t.Path
+	case "tmap.id":
+		return t.TmapID
+	case "source_wiki":
+		return t.Source
+	case "tags":
+		return t.TagsAsSlice()
+	case "tags_list":
+		return t.TagsList
+	case "relations":
+		return t.Relations
+	}
+	return t.ExtraFields[strings.TrimPrefix(f, "fields.")]
+}
+
+// setField escribe v (con la forma de getField) en el campo f de t.
+func setField(t *models.Tiddler, f string, v any) {
+	s, _ := v.(string)
+	switch f {
+	case "text":
+		if wrapperContent(t.Text) != nil && !looksLikeJSON(s) {
+			*t = RestoreTiddlerWrapper(*t, s, "")
+		} else {
+			t.Text = s
+		}
+	case "type":
+		t.Type = s
+	case "color":
+		t.Color = s
+	case "path":
+		t.Path = s
+	case "tmap.id":
+		t.TmapID = s
+	case "source_wiki":
+		t.Source = s
+	case "tags":
+		tags, _ := v.([]string)
+		parts := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			parts = append(parts, "[["+tag+"]]")
+		}
+		t.Tags = strings.Join(parts, " ")
+	case "tags_list":
+		t.TagsList, _ = v.([]string)
+	case "relations":
+		t.Relations, _ = v.(map[string]any)
+	default:
+		name := strings.TrimPrefix(f, "fields.")
+		if isBlank(v) {
+			delete(t.ExtraFields, name)
+			return
+		}
+		if t.ExtraFields == nil {
+			t.ExtraFields = map[string]any{}
+		}
+		t.ExtraFields[name] = v
+	}
+}
+
+// setMarkdown guarda md en content.markdown del envoltorio, o como cuerpo de
+// un tiddler text/x-markdown.  Devuelve false si la plantilla no admite
+// markdown.
+func setMarkdown(t *models.Tiddler, md string) bool {
+	switch {
+	case wrapperContent(t.Text) != nil:
+		*t = RestoreTiddlerWrapper(*t, "", md)
+	case t.Type == "text/x-markdown":
+		t.Text = md
+	default:
+		return false
+	}
+	return true
+}
+
+// wrapperContent devuelve el objeto content de un texto
+// {"content":{…}}, o nil si el texto no tiene esa forma.
+func wrapperContent(text string) map[string]any {
+	if !looksLikeJSON(text) {
+		return nil
+	}
+	var w map[string]any
+	if err := json.Unmarshal([]byte(text), &w); err != nil {
+		return nil
+	}
+	content, _ := w["content"].(map[string]any)
+	return content
+}
+
+// sameValue compara dos valores de campo: vacío equivale a ausente.
+func sameValue(a, b any) bool {
+	if isBlank(a) && isBlank(b) {
+		return true
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	// Números y listas que vienen de JSON: comparar por su forma JSON.
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func isBlank(v any) bool {
+	switch vv := v.(type) {
+	case nil:
+		return true
+	case string:
+		return vv == ""
+	case []string:
+		return len(vv) == 0
+	case []any:
+		return len(vv) == 0
+	case map[string]any:
+		return len(vv) == 0
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// internal/transform/update_fields_test.go – Tests de UpdateFields
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func updateFixture() []models.Tiddler {
+	return []models.Tiddler{
+		{Title: "A", Text: "hola", Tags: "x", Color: "red", Modified: "20240101000000",
+			ExtraFields: map[string]any{"caption": "Vieja", "owner": "ana"}},
+		{Title: "B", Type: "application/json", Modified: "20240101000000",
+			Text: `{"content":{"plain":"viejo","markdown":"*viejo*"},"meta":{"k":1}}`},
+	}
+}
+
+func TestUpdateFields_ReglasDeModified(t *testing.T) {
+	updates := []models.Tiddler{
+		{Title: "A", Text: "hola", Tags: "[[x]] [[nueva etiqueta]]", Color: "blue",
+			ExtraFields: map[string]any{"caption": "Nueva", "owner": "ana"}},
+		{Title: "B", Text: "nuevo"},
+		{Title: "Z", Text: "no está en la plantilla"},
+	}
+	spec := UpdateSpec{Fields: []string{"text", "tags", "color", "fields.caption"},
+		Bump: map[string]bool{"text": true}, Now: "20250101000000"}
+	out, rep, err := UpdateFields(updateFixture(), updates, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := out[0]
+	if got := a.TagsAsSlice(); !reflect.DeepEqual(got, []string{"x", "nueva etiqueta"}) {
+		t.Errorf("tags = %v", got)
+	}
+	if a.Color != "blue" || a.ExtraFields["caption"] != "Nueva" || a.ExtraFields["owner"] != "ana" {
+		t.Errorf("A = %+v", a)
+	}
+	// Sólo cambiaron metadatos: modified no se toca.
+	if a.Modified != "20240101000000" {
+		t.Errorf("modified de A = %s", a.Modified)
+	}
+
+	// El texto envuelto se actualiza en content.plain y sí cambia modified.
+	b := out[1]
+	if !strings.Contains(b.Text, `"plain": "nuevo"`) || !strings.Contains(b.Text, `"meta"`) {
+		t.Errorf("texto de B = %s", b.Text)
+	}
+	if b.Modified != "20250101000000" {
+		t.Errorf("modified de B = %s", b.Modified)
+	}
+
+	var changes []string
+	for _, c := range rep.Changes {
+		changes = append(changes, c.Title+"."+c.Field)
+	}
+	if want := []string{"A.tags", "A.color", "A.fields.caption", "B.text"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("cambios = %v, want %v", changes, want)
+	}
+	if rep.Updated != 2 || rep.Bumped != 1 || !reflect.DeepEqual(rep.Missing, []string{"Z"}) {
+		t.Errorf("informe = %+v", rep)
+	}
+}
+
+func TestUpdateFields_CamposPersonalizadosYMarkdown(t *testing.T) {
+	updates := []models.Tiddler{
+		{Title: "A", Text: "hola", ExtraFields: map[string]any{"status": "ok"}},
+		{Title: "B", TextMarkdown: "**nuevo**"},
+	}
+	bump, err := ParseBump("all", []string{"fields.*", "markdown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, rep, err := UpdateFields(updateFixture(), updates,
+		UpdateSpec{Fields: []string{"fields.*", "markdown"}, Bump: bump, Now: "20250101000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fields.* aplica el conjunto completo: caption y owner desaparecen.
+	if want := map[string]any{"status": "ok"}; !reflect.DeepEqual(out[0].ExtraFields, want) {
+		t.Errorf("fields de A = %v", out[0].ExtraFields)
+	}
+	if !strings.Contains(out[1].Text, `"markdown": "**nuevo**"`) || !strings.Contains(out[1].Text, `"plain": "viejo"`) {
+		t.Errorf("texto de B = %s", out[1].Text)
+	}
+	if rep.Bumped != 2 || len(rep.Changes) != 4 {
+		t.Errorf("informe = %+v", rep)
+	}
+}
+
+// Un campo que el esquema de las actualizaciones no guarda no se aplica.
+func TestUpdateFields_Esquema(t *testing.T) {
+	if _, _, err := UpdateFields(updateFixture(), nil, UpdateSpec{Fields: []string{"relations"}, Schema: "v1"}); err == nil {
+		t.Error("relations desde v1 debería ser un error")
+	}
+	if _, err := ParseFieldList("text,created"); err == nil {
+		t.Error("created no es actualizable")
+	}
+}
@@ -0,0 +1,41 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+func TestUpdateTexts(t *testing.T) {
+	plantilla := []models.Tiddler{
+		{Title: "A", Text: "foo", Modified: "20240101"},
+		{Title: "B", Text: "bar", Modified: "20240102"},
+	}
+	updates := []models.Tiddler{
+		{Title: "A", Text: "foo"},         // igual, no debe cambiar
+		{Title: "B", Text: "nuevo texto"}, // diferente, debe actualizarse
+	}
+
+	result := UpdateTexts(plantilla, updates)
+
+	// El texto de A no cambia, ni la fecha
+	if result[0].Text != "foo" || result[0].Modified != "20240101" {
+		t.Errorf("No debe cambiar el tiddler A")
+	}
+
+	// El texto de B cambia, y la fecha debe ser "ahora" (formato TiddlyWiki)
+	if result[1].Text != "nuevo texto" {
+		t.Errorf("Debe actualizar el texto de B")
+	}
+	if result[1].Modified == "20240102" {
+		t.Errorf("Debe actualizar la fecha de B")
+	}
+	if len(result) != 2 {
+		t.Errorf("No debe cambiar la cantidad de tiddlers")
+	}
+
+	// Verifica formato TiddlyWiki (14 dígitos numéricos)
+	if result[1].Modified == "" || len(result[1].Modified) != 14 {
+		t.Errorf("La fecha modificada debe tener formato TiddlyWiki (yyyymmddhhMMSS)")
+	}
+}
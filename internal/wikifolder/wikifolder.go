@@ -0,0 +1,182 @@
+// internal/wikifolder/wikifolder.go – Wikis de TiddlyWiki en carpeta (.tid)
+// --------------------------------------------------------------------------------
+// Contexto pedagógico
+// -------------------
+// Además del export JSON, TiddlyWiki en Node.js guarda la wiki como una
+// carpeta con un archivo .tid por tiddler:
+//
+//   created: 20250101120000000
+//   tags: [[con espacios]] simple
+//   title: Foo
+//   type: text/vnd.tiddlywiki
+//
+//   El texto va después de la primera línea en blanco.
+//
+// Read carga todos los .tid de una carpeta (recursivamente) y recuerda en qué
+// archivo vive cada título; Format y FileName hacen el camino inverso.  Los
+// valores que no son strings (relations, tags_list…) se guardan como JSON
+// porque un .tid sólo admite texto en sus cabeceras.
+// --------------------------------------------------------------------------------
+
+package wikifolder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+// Ext es la extensión de los archivos de tiddler.
+const Ext = ".tid"
+
+// Folder es una wiki en carpeta: sus tiddlers en orden de archivo y la ruta
+// de cada título.
+type Folder struct {
+	Dir      string
+	Tiddlers []models.Tiddler
+	Paths    map[string]string
+}
+
+// Read carga los .tid de dir y sus subcarpetas.
+func Read(dir string) (*Folder, error) {
+	f := &Folder{Dir: dir, Paths: make(map[string]string)}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != Ext {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		t, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if prev, dup := f.Paths[t.Title]; dup {
+			return fmt.Errorf("%s: título %q repetido (ya está en %s)", path, t.Title, prev)
+		}
+		f.Paths[t.Title] = path
+		f.Tiddlers = append(f.Tiddlers, t)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("leyendo carpeta %s: %w", dir, err)
+	}
+	return f, nil
+}
+
+// Parse interpreta el contenido de un archivo .tid.
+func Parse(data []byte) (models.Tiddler, error) {
+	s := strings.ReplaceAll(string(data), "\r\n", "\n")
+	header, text, _ := strings.Cut(s, "\n\n")
+	raw := map[string]any{"text": strings.TrimSuffix(text, "\n")}
+	for _, line := range strings.Split(header, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return models.Tiddler{}, fmt.Errorf("cabecera sin ':' %q", line)
+		}
+		raw[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	if raw["title"] == nil || raw["title"] == "" {
+		return models.Tiddler{}, fmt.Errorf("tiddler sin título")
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return models.Tiddler{}, err
+	}
+	var t models.Tiddler
+	if err := json.Unmarshal(b, &t); err != nil {
+		return models.Tiddler{}, err
+	}
+	return t, nil
+}
+
+// Format escribe t como archivo .tid: cabeceras en orden alfabético (sin las
+// vacías) y el texto tras una línea en blanco.
+func Format(t models.Tiddler) ([]byte, error) {
+	b, err := json.Marshal(&t)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		if name != "text" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		value, err := headerValue(name, raw[name], t)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", t.Title, err)
+		}
+		if value == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s: %s\n", name, value)
+	}
+	buf.WriteString("\n")
+	buf.WriteString(t.Text)
+	return buf.Bytes(), nil
+}
+
+func headerValue(name string, v any, t models.Tiddler) (string, error) {
+	var s string
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		s = vv
+	default:
+		if name == "tags" {
+			s = models.StringifyList(t.TagsAsSlice())
+			break
+		}
+		b, err := json.Marshal(vv)
+		if err != nil {
+			return "", err
+		}
+		s = string(b)
+	}
+	if strings.ContainsAny(s, "\r\n") {
+		return "", fmt.Errorf("el campo %s tiene saltos de línea y no cabe en una cabecera .tid", name)
+	}
+	return s, nil
+}
+
+// FileName deriva el nombre de archivo de un título como TiddlyWiki: los
+// caracteres no válidos en rutas se reemplazan por "_" ("$:/config" →
+// "$__config.tid").
+func FileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, title)
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		name = "_"
+	}
+	return name + Ext
+}
@@ -0,0 +1,95 @@
+// internal/wikifolder/wikifolder_test.go – Tests de Parse, Format y FileName
+// --------------------------------------------------------------------------------
+
+package wikifolder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/diegoabeltran16/OpenPages-Source/models"
+)
+
+const sampleTid = "created: 20250101120000000\r\n" +
+	"tags: [[con espacios]] simple\r\n" +
+	"title: $:/config/Foo\r\n" +
+	"caption: Fu\r\n" +
+	"\r\n" +
+	"línea uno\r\n\r\nlínea: tres\r\n"
+
+func TestParseFormat(t *testing.T) {
+	tid, err := Parse([]byte(sampleTid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tid.Title != "$:/config/Foo" || tid.Created != "20250101120000000" || tid.ExtraFields["caption"] != "Fu" {
+		t.Errorf("Parse = %+v", tid)
+	}
+	if tags := tid.TagsAsSlice(); len(tags) != 2 || tags[0] != "con espacios" {
+		t.Errorf("tags = %v", tags)
+	}
+	if tid.Text != "línea uno\n\nlínea: tres" {
+		t.Errorf("text = %q", tid.Text)
+	}
+
+	data, err := Format(tid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "caption: Fu\ncreated: 20250101120000000\ntags: [[con espacios]] simple\ntitle: $:/config/Foo\n\nlínea uno\n\nlínea: tres"
+	if string(data) != want {
+		t.Errorf("Format =\n%s\nwant\n%s", data, want)
+	}
+
+	if _, err := Format(models.Tiddler{Title: "A", ExtraFields: map[string]any{"nota": "dos\nlíneas"}}); err == nil {
+		t.Error("campo con salto de línea sin error")
+	}
+	if _, err := Parse([]byte("tags: x\n\ntexto")); err == nil {
+		t.Error("tiddler sin título sin error")
+	}
+}
+
+func TestFileName(t *testing.T) {
+	cases := map[string]string{
+		"$:/config/Foo": "$__config_Foo.tid",
+		"¿Qué es?":      "¿Qué es_.tid",
+		"Nota.":         "Nota.tid",
+	}
+	for title, want := range cases {
+		if got := FileName(title); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range map[string]string{
+		"A.tid":      "title: A\n\na",
+		"sub/B.tid":  "title: B\n\nb",
+		"notas.txt":  "no es un tiddler",
+		"sub/C.meta": "title: C",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Tiddlers) != 2 || f.Paths["B"] != filepath.Join(dir, "sub", "B.tid") {
+		t.Errorf("Read = %+v", f)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "A2.tid"), []byte("title: A\n\notra"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(dir); err == nil {
+		t.Error("título repetido sin error")
+	}
+}
@@ -6,6 +6,19 @@
 //   • `Record`  (v1) → estructura compacta, utilizada hasta ahora.
 //   • `RecordV2` (v2) → esquema "AI‑friendly" con meta ↔ content separados.
 //
+// Los campos personalizados del tiddler (Tiddler.ExtraFields: caption,
+// status, author…) viajan con su tipo original: en v1 e híbrido bajo
+// `fields`, en v2 dentro de `meta.extra`.  La clase del tiddler (`kind`:
+// content, system, draft, state o plugin) e `is_system` se calculan al
+// convertir (ver internal/classify) y no vuelven al tiddler al revertir.
+// Con fragmentación (ver internal/chunk) un tiddler da varios RecordV2 de
+// Type "fragment", enlazados al tiddler por Parent y ubicados por Chunk.
+//
+// Todo registro declara su esquema en `schema_version` (SchemaV1, SchemaV2,
+// SchemaV3 o SchemaHybrid; el registro de esquemas está en
+// transform.Schemas).  Los JSONL anteriores no lo traen: el esquema se
+// deduce de sus claves y `openpages migrate` los lleva a otra versión.
+//
 // Mantener los dos modelos en un solo archivo permite evolucionar gradualmente
 // sin romper compatibilidad.  El conversor v1 sigue funcionando tal cual; el
 // conversor v2 emitirá la nueva forma sólo cuando el usuario pase `-mode v2`.
@@ -18,20 +31,36 @@ import (
 	"time"
 )
 
+// Versiones de esquema: valores de `schema_version`, iguales a los modos de
+// export.
+const (
+	SchemaV1     = "v1"
+	SchemaV2     = "v2"
+	SchemaV3     = "v3"
+	SchemaHybrid = "hybrid"
+)
+
 // -----------------------------------------------------------------------------
 // VERSIÓN 1 – Compacta (heredada)
 // -----------------------------------------------------------------------------
-// Usada por ConvertTiddlers (v1).  Se conserva para no romper flujos existentes.
+// Usada por ConvertTiddlers (v1) y, con SchemaVersion "hybrid", por
+// ConvertTiddlerHybrid.  Se conserva para no romper flujos existentes.
 
 type Record struct {
-	ID           string   `json:"id"` // normalmente igual a Title
-	Tags         []string `json:"tags,omitempty"`
-	ContentType  string   `json:"type,omitempty"`
-	TextMarkdown string   `json:"textMarkdown,omitempty"`
-	TextPlain    string   `json:"textPlain,omitempty"`
-	CreatedAt    string   `json:"createdAt,omitempty"` // formato yyyymmdd… (legacy)
-	ModifiedAt   string   `json:"modifiedAt,omitempty"`
-	Color        string   `json:"color,omitempty"`
+	SchemaVersion string   `json:"schema_version"` // SchemaV1 | SchemaHybrid
+	ID            string   `json:"id"`             // normalmente igual a Title
+	Tags          []string `json:"tags,omitempty"`
+	ContentType   string   `json:"type,omitempty"`
+	TextMarkdown  string   `json:"textMarkdown,omitempty"`
+	TextPlain     string   `json:"textPlain,omitempty"`
+	CreatedAt     string   `json:"createdAt,omitempty"` // formato yyyymmdd… (legacy)
+	ModifiedAt    string   `json:"modifiedAt,omitempty"`
+	Color         string   `json:"color,omitempty"`
+	Source        string   `json:"source_wiki,omitempty"` // wiki de origen (merge)
+	Kind          string   `json:"kind"`                  // content | system | draft | state | plugin
+	IsSystem      bool     `json:"is_system"`             // título $:/…
+	// Fields conserva los campos personalizados del tiddler (ExtraFields).
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
 // -----------------------------------------------------------------------------
@@ -46,26 +75,41 @@ type Content struct {
 	Sections []Section      `json:"sections,omitempty"`
 }
 
+// Section es una parte con nombre del texto (ver internal/sections): el
+// cuerpo bajo un encabezado, una definición o una clave de un objeto JSON.
 type Section struct {
 	Name     string `json:"name"`
 	RawValue string `json:"value"`
 }
 
 type RecordMeta struct {
-	Title    string            `json:"title"`
-	Tags     []string          `json:"tags,omitempty"`
-	Created  time.Time         `json:"created,omitempty"`
-	Modified time.Time         `json:"modified,omitempty"`
-	Color    string            `json:"color,omitempty"`
-	Extra    map[string]string `json:"extra,omitempty"`
+	Title    string         `json:"title"`
+	Tags     []string       `json:"tags,omitempty"`
+	Created  time.Time      `json:"created,omitempty"`
+	Modified time.Time      `json:"modified,omitempty"`
+	Color    string         `json:"color,omitempty"`
+	Kind     string         `json:"kind"`
+	IsSystem bool           `json:"is_system"`
+	Extra    map[string]any `json:"extra,omitempty"` // tmap.id, source y campos personalizados
 }
 
 type RecordV2 struct {
-	ID        string              `json:"id"`
-	Type      string              `json:"type"` // "tiddler", "fragment", etc.
-	Meta      RecordMeta          `json:"meta"`
-	Content   Content             `json:"content"`
-	Relations map[string][]string `json:"relations,omitempty"`
+	SchemaVersion string              `json:"schema_version"` // SchemaV2
+	ID            string              `json:"id"`
+	Type          string              `json:"type"` // "tiddler", "fragment", etc.
+	Meta          RecordMeta          `json:"meta"`
+	Content       Content             `json:"content"`
+	Relations     map[string][]string `json:"relations,omitempty"`
+	Parent        string              `json:"parent,omitempty"` // fragmentos: ID del registro del tiddler
+	Chunk         *ChunkInfo          `json:"chunk,omitempty"`  // fragmentos: posición y sección
+}
+
+// ChunkInfo ubica un fragmento (Type "fragment") dentro de su tiddler.
+type ChunkInfo struct {
+	Index      int      `json:"index"` // desde 0, en orden de lectura
+	Count      int      `json:"count"` // fragmentos del tiddler
+	Tokens     int      `json:"tokens"`
+	Breadcrumb []string `json:"breadcrumb,omitempty"` // encabezados que contienen el fragmento
 }
 
 // FlattenTags convierte el slice de tags en un string separado por coma.
@@ -94,22 +138,3 @@ func (r RecordV2) FlattenRelations() (define string, requiere string) {
 func (r RecordV2) IsAIReady() bool {
 	return r.ID != "" && r.Type != "" && r.Content.Plain != ""
 }
-
-// -----------------------------------------------------------------------------
-// VERSIÓN HÍBRIDA – Para compatibilidad hacia adelante
-// -----------------------------------------------------------------------------
-// Estructura que combina elementos de v1 y v2 para facilitar la migración.
-
-type RecordHybrid struct {
-	ID        string                 `json:"id"`
-	Title     string                 `json:"title"`
-	Created   string                 `json:"created"`
-	Modified  string                 `json:"modified"`
-	Tags      []string               `json:"tags"`
-	Type      string                 `json:"type"`
-	Text      string                 `json:"text"`
-	TmapID    string                 `json:"tmap.id"`
-	Meta      map[string]interface{} `json:"meta,omitempty"`
-	Content   map[string]interface{} `json:"content,omitempty"`
-	Relations map[string]interface{} `json:"relations,omitempty"`
-}
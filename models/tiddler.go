@@ -6,6 +6,7 @@ package models
 import (
 	"encoding/json"
 	"strings"
+	"unicode"
 )
 
 // Tiddler representa un elemento exportado de TiddlyWiki.
@@ -18,6 +19,7 @@ import (
 //   - Modified: Timestamp de última modificación en formato TiddlyWiki.
 //   - Color:    Color asociado (opcional).
 //   - TmapID:   Identificador interno de TiddlyMap (opcional).
+//   - Source:   Wiki de origen al combinar exports (opcional, "source_wiki").
 type Tiddler struct {
 	Title     string                 `json:"title"`
 	Text      string                 `json:"text"`
@@ -28,6 +30,7 @@ type Tiddler struct {
 	Color     string                 `json:"color,omitempty"`
 	Path      string                 `json:"path,omitempty"` // <--- AGREGA ESTA LÍNEA
 	TmapID    string                 `json:"tmap.id,omitempty"`
+	Source    string                 `json:"source_wiki,omitempty"`
 	Relations map[string]interface{} `json:"relations,omitempty"`
 	// Campos opcionales para compatibilidad
 	TextMarkdown string                 `json:"textMarkdown,omitempty"`
@@ -76,6 +79,7 @@ func (t *Tiddler) UnmarshalJSON(data []byte) error {
 		"title": true, "text": true, "type": true, "tags": true,
 		"created": true, "modified": true, "color": true, "tmap.id": true,
 		"path":         true, // <-- Añade path
+		"source_wiki":  true,
 		"tags_list":    true, // <-- Añade tags_list
 		"relations":    true, // <-- Añade relations
 		"textMarkdown": true, // <-- Añade textMarkdown si lo usas
@@ -131,17 +135,42 @@ func (t *Tiddler) TagsAsSlice() []string {
 	}
 }
 
-func parseTags(tags string) []string {
-	tags = strings.TrimSpace(tags)
-	if tags == "" {
-		return nil
+// ParseList separa una lista de títulos de TiddlyWiki (tags, list…):
+// "[[con espacios]] palabra" → ["con espacios", "palabra"].
+func ParseList(s string) []string { return parseTags(s) }
+
+// StringifyList escribe una lista de títulos como TiddlyWiki: [[…]] sólo si el
+// título tiene espacios.
+func StringifyList(titles []string) string {
+	parts := make([]string, 0, len(titles))
+	for _, title := range titles {
+		if strings.IndexFunc(title, unicode.IsSpace) >= 0 {
+			title = "[[" + title + "]]"
+		}
+		parts = append(parts, title)
 	}
+	return strings.Join(parts, " ")
+}
+
+// parseTags separa una lista TiddlyWiki: "[[con espacios]] palabra".
+func parseTags(tags string) []string {
 	var result []string
-	for _, tag := range strings.Fields(tags) {
-		tag = strings.Trim(tag, "[]")
-		if tag != "" {
-			result = append(result, tag)
+	for tags = strings.TrimSpace(tags); tags != ""; tags = strings.TrimSpace(tags) {
+		if strings.HasPrefix(tags, "[[") {
+			if end := strings.Index(tags, "]]"); end >= 0 {
+				if tag := tags[2:end]; tag != "" {
+					result = append(result, tag)
+				}
+				tags = tags[end+2:]
+				continue
+			}
+		}
+		word := tags
+		if i := strings.IndexFunc(tags, unicode.IsSpace); i >= 0 {
+			word = tags[:i]
 		}
+		result = append(result, word)
+		tags = tags[len(word):]
 	}
 	return result
 }